@@ -29,53 +29,20 @@ func (c *client) RequestPermission(ctx context.Context, params acp.RequestPermis
 	}
 
 	session.recordPermissionToolCall(params.ToolCall)
-	if session.isAllowedToolCall(ctx, params.ToolCall) {
-		// try to find an always allow or allow once option, else default to first opt
-		bestOpt := params.Options[0]
-		for _, opt := range params.Options {
-			if opt.Kind == acp.PermissionOptionKindAllowAlways {
-				bestOpt = opt
-				break
-			}
-			if opt.Kind == acp.PermissionOptionKindAllowOnce {
-				bestOpt = opt // keep going in case we find the always allow option
-			}
-		}
-
-		return acp.RequestPermissionResponse{
-			Outcome: acp.RequestPermissionOutcome{
-				Selected: &acp.RequestPermissionOutcomeSelected{
-					Outcome:  "selected",
-					OptionId: bestOpt.OptionId,
-				},
-			},
-		}, nil
-	}
-
-	found := false
-	var bestOpt acp.PermissionOption
-	for _, opt := range params.Options {
-		if opt.Kind == acp.PermissionOptionKindRejectAlways {
-			bestOpt = opt
-			found = true
-			break
-		}
-		if opt.Kind == acp.PermissionOptionKindRejectOnce {
-			bestOpt = opt
-			found = true
-		}
-	}
 
-	if !found {
-		return acp.RequestPermissionResponse{}, fmt.Errorf("no reject option provided")
+	optionId, err := session.requestPermission(ctx, params)
+	if err != nil {
+		return acp.RequestPermissionResponse{}, err
 	}
 
-	return acp.RequestPermissionResponse{Outcome: acp.RequestPermissionOutcome{
-		Selected: &acp.RequestPermissionOutcomeSelected{
-			Outcome:  "selected",
-			OptionId: bestOpt.OptionId,
+	return acp.RequestPermissionResponse{
+		Outcome: acp.RequestPermissionOutcome{
+			Selected: &acp.RequestPermissionOutcomeSelected{
+				Outcome:  "selected",
+				OptionId: optionId,
+			},
 		},
-	}}, nil
+	}, nil
 }
 
 // Notification containing a session update from the agent.
@@ -101,47 +68,136 @@ func (c *client) SessionUpdate(ctx context.Context, params acp.SessionNotificati
 //
 // Only available if the client supports the 'fs.readTextFile' capability.
 func (c *client) ReadTextFile(ctx context.Context, params acp.ReadTextFileRequest) (acp.ReadTextFileResponse, error) {
-	return acp.ReadTextFileResponse{}, fmt.Errorf("no fs.readTextFile capability")
+	session, err := c.session(params.SessionId)
+	if err != nil {
+		return acp.ReadTextFileResponse{}, err
+	}
+
+	content, err := session.readTextFile(params.Path, params.Line, params.Limit)
+	if err != nil {
+		return acp.ReadTextFileResponse{}, err
+	}
+
+	return acp.ReadTextFileResponse{Content: content}, nil
 }
 
 // Request to write content to a text file.
 //
 // Only available if the client supports the 'fs.writeTextFile' capability.
 func (c *client) WriteTextFile(ctx context.Context, params acp.WriteTextFileRequest) (acp.WriteTextFileResponse, error) {
-	return acp.WriteTextFileResponse{}, fmt.Errorf("no fs.writeTextFile capability")
+	session, err := c.session(params.SessionId)
+	if err != nil {
+		return acp.WriteTextFileResponse{}, err
+	}
+
+	if err := session.writeTextFile(params.Path, params.Content); err != nil {
+		return acp.WriteTextFileResponse{}, err
+	}
+
+	return acp.WriteTextFileResponse{}, nil
 }
 
 // Request to create a new terminal and execute a command.
 //
 // Only available if the client supports the 'terminal' capability
 func (c *client) CreateTerminal(ctx context.Context, params acp.CreateTerminalRequest) (acp.CreateTerminalResponse, error) {
-	return acp.CreateTerminalResponse{}, fmt.Errorf("no terminal capability")
+	session, err := c.session(params.SessionId)
+	if err != nil {
+		return acp.CreateTerminalResponse{}, err
+	}
+
+	id, err := session.createTerminal(params)
+	if err != nil {
+		return acp.CreateTerminalResponse{}, err
+	}
+
+	return acp.CreateTerminalResponse{TerminalId: id}, nil
 }
 
 // Request to kill a terminal command without releasing the terminal.
 //
 // Only available if the client supports the 'terminal' capability
 func (c *client) KillTerminalCommand(ctx context.Context, params acp.KillTerminalCommandRequest) (acp.KillTerminalCommandResponse, error) {
-	return acp.KillTerminalCommandResponse{}, fmt.Errorf("no terminal capability")
+	t, err := c.terminal(params.SessionId, params.TerminalId)
+	if err != nil {
+		return acp.KillTerminalCommandResponse{}, err
+	}
+
+	t.cancel()
+
+	return acp.KillTerminalCommandResponse{}, nil
 }
 
 // Request to get the current output and status of a terminal.
 //
 // Only available if the client supports the 'terminal' capability
 func (c *client) TerminalOutput(ctx context.Context, params acp.TerminalOutputRequest) (acp.TerminalOutputResponse, error) {
-	return acp.TerminalOutputResponse{}, fmt.Errorf("no terminal capability")
+	t, err := c.terminal(params.SessionId, params.TerminalId)
+	if err != nil {
+		return acp.TerminalOutputResponse{}, err
+	}
+
+	output, truncated := t.output.String()
+
+	return acp.TerminalOutputResponse{
+		Output:     output,
+		Truncated:  truncated,
+		ExitStatus: t.exitStatus(),
+	}, nil
 }
 
 // Request to release a terminal and free its resources.
 //
 // Only available if the client supports the 'terminal' capability
 func (c *client) ReleaseTerminal(ctx context.Context, params acp.ReleaseTerminalRequest) (acp.ReleaseTerminalResponse, error) {
-	return acp.ReleaseTerminalResponse{}, fmt.Errorf("no terminal capability")
+	session, err := c.session(params.SessionId)
+	if err != nil {
+		return acp.ReleaseTerminalResponse{}, err
+	}
+
+	if err := session.releaseTerminal(params.TerminalId); err != nil {
+		return acp.ReleaseTerminalResponse{}, err
+	}
+
+	return acp.ReleaseTerminalResponse{}, nil
 }
 
 // Request to wait for a terminal command to exit.
 //
 // Only available if the client supports the 'terminal' capability
 func (c *client) WaitForTerminalExit(ctx context.Context, params acp.WaitForTerminalExitRequest) (acp.WaitForTerminalExitResponse, error) {
-	return acp.WaitForTerminalExitResponse{}, fmt.Errorf("no terminal capability")
+	t, err := c.terminal(params.SessionId, params.TerminalId)
+	if err != nil {
+		return acp.WaitForTerminalExitResponse{}, err
+	}
+
+	select {
+	case <-t.done:
+	case <-ctx.Done():
+		return acp.WaitForTerminalExitResponse{}, ctx.Err()
+	}
+
+	exit := t.exitStatus()
+	return acp.WaitForTerminalExitResponse{ExitCode: exit.ExitCode, Signal: exit.Signal}, nil
+}
+
+func (c *client) session(id acp.SessionId) (*session, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	session, ok := c.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("no matching session on client")
+	}
+
+	return session, nil
+}
+
+func (c *client) terminal(sessionID acp.SessionId, terminalID string) (*terminal, error) {
+	session, err := c.session(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return session.terminal(terminalID)
 }
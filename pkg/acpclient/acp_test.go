@@ -2,6 +2,9 @@ package acpclient
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/coder/acp-go-sdk"
@@ -187,3 +190,284 @@ func newTestSession(allowedTools []*mcp.Tool) *session {
 	}
 	return NewSession(mgr)
 }
+
+func TestClient_WriteTextFile(t *testing.T) {
+	tt := map[string]struct {
+		path        string
+		absolute    bool
+		expectErr   bool
+		expectedRel string
+	}{
+		"absolute path in workspace": {
+			path:        "manifests/deploy.yaml",
+			absolute:    true,
+			expectedRel: filepath.Join("manifests", "deploy.yaml"),
+		},
+		"relative path": {
+			path:        "report.md",
+			expectedRel: "report.md",
+		},
+		"path outside of workspace": {
+			path:      "../escape.txt",
+			expectErr: true,
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			cwd := t.TempDir()
+			s := newTestSession(nil)
+			s.cwd = cwd
+			c := &client{
+				sessions: map[acp.SessionId]*session{"s1": s},
+			}
+
+			path := tc.path
+			if tc.absolute {
+				path = filepath.Join(cwd, tc.path)
+			}
+
+			_, err := c.WriteTextFile(context.Background(), acp.WriteTextFileRequest{
+				SessionId: "s1",
+				Path:      path,
+				Content:   "hello",
+			})
+
+			require.Len(t, s.fileOperations, 1)
+			assert.Equal(t, FileOperationWrite, s.fileOperations[0].Type)
+			assert.Equal(t, 5, s.fileOperations[0].Bytes)
+
+			if tc.expectErr {
+				require.Error(t, err)
+				assert.NotEmpty(t, s.fileOperations[0].Error)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRel, s.fileOperations[0].Path)
+			data, err := os.ReadFile(filepath.Join(cwd, tc.expectedRel))
+			require.NoError(t, err)
+			assert.Equal(t, "hello", string(data))
+		})
+	}
+
+	t.Run("unknown session", func(t *testing.T) {
+		c := &client{sessions: map[acp.SessionId]*session{}}
+		_, err := c.WriteTextFile(context.Background(), acp.WriteTextFileRequest{SessionId: "missing", Path: "x"})
+		require.Error(t, err)
+	})
+}
+
+func TestClient_ReadTextFile(t *testing.T) {
+	cwd := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(cwd, "notes.txt"), []byte("one\ntwo\nthree\n"), 0o644))
+
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("token"), 0o644))
+	require.NoError(t, os.Symlink(outside, filepath.Join(cwd, "link")))
+
+	tt := map[string]struct {
+		path        string
+		line        *int
+		limit       *int
+		expected    string
+		errContains string
+	}{
+		"whole file": {
+			path:     "notes.txt",
+			expected: "one\ntwo\nthree\n",
+		},
+		"line and limit": {
+			path:     "notes.txt",
+			line:     ptr(2),
+			limit:    ptr(1),
+			expected: "two\n",
+		},
+		"line past the end": {
+			path:     "notes.txt",
+			line:     ptr(10),
+			expected: "",
+		},
+		"missing file": {
+			path:        "missing.txt",
+			errContains: "no such file",
+		},
+		"path outside of workspace": {
+			path:        filepath.Join(outside, "secret.txt"),
+			errContains: "outside of the agent workspace",
+		},
+		"symlink outside of workspace": {
+			path:        "link/secret.txt",
+			errContains: "outside of the agent workspace",
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			s := newTestSession(nil)
+			s.cwd = cwd
+			c := &client{
+				sessions: map[acp.SessionId]*session{"s1": s},
+			}
+
+			resp, err := c.ReadTextFile(context.Background(), acp.ReadTextFileRequest{
+				SessionId: "s1",
+				Path:      tc.path,
+				Line:      tc.line,
+				Limit:     tc.limit,
+			})
+
+			require.Len(t, s.fileOperations, 1)
+			assert.Equal(t, FileOperationRead, s.fileOperations[0].Type)
+
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+				assert.NotEmpty(t, s.fileOperations[0].Error)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, resp.Content)
+			assert.Equal(t, tc.path, s.fileOperations[0].Path)
+			assert.Equal(t, len(tc.expected), s.fileOperations[0].Bytes)
+		})
+	}
+}
+
+func TestClient_Terminal(t *testing.T) {
+	newClient := func(t *testing.T) (*client, *session) {
+		s := newTestSession(nil)
+		s.cwd = t.TempDir()
+		return &client{sessions: map[acp.SessionId]*session{"s1": s}}, s
+	}
+	ctx := context.Background()
+
+	t.Run("run to completion", func(t *testing.T) {
+		c, s := newClient(t)
+		require.NoError(t, os.Mkdir(filepath.Join(s.cwd, "sub"), 0o755))
+
+		created, err := c.CreateTerminal(ctx, acp.CreateTerminalRequest{
+			SessionId: "s1",
+			Command:   "sh",
+			Args:      []string{"-c", `pwd; echo "$GREETING"; exit 3`},
+			Cwd:       ptr(filepath.Join(s.cwd, "sub")),
+			Env:       []acp.EnvVariable{{Name: "GREETING", Value: "hello"}},
+		})
+		require.NoError(t, err)
+
+		exit, err := c.WaitForTerminalExit(ctx, acp.WaitForTerminalExitRequest{SessionId: "s1", TerminalId: created.TerminalId})
+		require.NoError(t, err)
+		require.NotNil(t, exit.ExitCode)
+		assert.Equal(t, 3, *exit.ExitCode)
+
+		out, err := c.TerminalOutput(ctx, acp.TerminalOutputRequest{SessionId: "s1", TerminalId: created.TerminalId})
+		require.NoError(t, err)
+		assert.Contains(t, out.Output, "sub\nhello\n")
+		assert.False(t, out.Truncated)
+		require.NotNil(t, out.ExitStatus)
+
+		_, err = c.ReleaseTerminal(ctx, acp.ReleaseTerminalRequest{SessionId: "s1", TerminalId: created.TerminalId})
+		require.NoError(t, err)
+		_, err = c.TerminalOutput(ctx, acp.TerminalOutputRequest{SessionId: "s1", TerminalId: created.TerminalId})
+		require.Error(t, err)
+
+		require.Len(t, s.terminalCommands, 1)
+		cmd := s.terminalCommands[0]
+		assert.Equal(t, `sh -c pwd; echo "$GREETING"; exit 3`, cmd.CommandLine())
+		assert.Equal(t, "sub", cmd.Cwd)
+		require.NotNil(t, cmd.ExitCode)
+		assert.Equal(t, 3, *cmd.ExitCode)
+	})
+
+	t.Run("environment without credentials and with a virtual home", func(t *testing.T) {
+		t.Setenv("HOME", "/home/dev")
+		t.Setenv("KUBECONFIG", "/home/dev/.kube/config")
+		c, s := newClient(t)
+
+		created, err := c.CreateTerminal(ctx, acp.CreateTerminalRequest{
+			SessionId: "s1",
+			Command:   "sh",
+			Args:      []string{"-c", `echo "$HOME"; echo "kube=${KUBECONFIG:-}"; echo "path=${PATH:+set}"`},
+		})
+		require.NoError(t, err)
+
+		_, err = c.WaitForTerminalExit(ctx, acp.WaitForTerminalExitRequest{SessionId: "s1", TerminalId: created.TerminalId})
+		require.NoError(t, err)
+		out, err := c.TerminalOutput(ctx, acp.TerminalOutputRequest{SessionId: "s1", TerminalId: created.TerminalId})
+		require.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSpace(out.Output), "\n")
+		require.Len(t, lines, 3)
+		home := lines[0]
+		assert.NotEqual(t, "/home/dev", home)
+		assert.DirExists(t, home)
+		assert.Equal(t, "kube=", lines[1])
+		assert.Equal(t, "path=set", lines[2])
+
+		s.releaseTerminals()
+		assert.NoDirExists(t, home, "the virtual home is removed with the terminals")
+	})
+
+	t.Run("output byte limit", func(t *testing.T) {
+		c, _ := newClient(t)
+
+		created, err := c.CreateTerminal(ctx, acp.CreateTerminalRequest{
+			SessionId:       "s1",
+			Command:         "printf",
+			Args:            []string{"0123456789"},
+			OutputByteLimit: ptr(4),
+		})
+		require.NoError(t, err)
+
+		_, err = c.WaitForTerminalExit(ctx, acp.WaitForTerminalExitRequest{SessionId: "s1", TerminalId: created.TerminalId})
+		require.NoError(t, err)
+
+		out, err := c.TerminalOutput(ctx, acp.TerminalOutputRequest{SessionId: "s1", TerminalId: created.TerminalId})
+		require.NoError(t, err)
+		assert.Equal(t, "6789", out.Output)
+		assert.True(t, out.Truncated)
+	})
+
+	t.Run("kill and release at session end", func(t *testing.T) {
+		c, s := newClient(t)
+
+		killed, err := c.CreateTerminal(ctx, acp.CreateTerminalRequest{SessionId: "s1", Command: "sleep", Args: []string{"60"}})
+		require.NoError(t, err)
+		_, err = c.KillTerminalCommand(ctx, acp.KillTerminalCommandRequest{SessionId: "s1", TerminalId: killed.TerminalId})
+		require.NoError(t, err)
+
+		exit, err := c.WaitForTerminalExit(ctx, acp.WaitForTerminalExitRequest{SessionId: "s1", TerminalId: killed.TerminalId})
+		require.NoError(t, err)
+		assert.Nil(t, exit.ExitCode)
+		require.NotNil(t, exit.Signal)
+
+		_, err = c.CreateTerminal(ctx, acp.CreateTerminalRequest{SessionId: "s1", Command: "sleep", Args: []string{"60"}})
+		require.NoError(t, err)
+
+		s.releaseTerminals()
+		require.Len(t, s.terminalCommands, 2)
+		for _, cmd := range s.terminalCommands {
+			assert.Nil(t, cmd.ExitCode)
+			assert.NotEmpty(t, cmd.Signal)
+		}
+	})
+
+	t.Run("cwd outside of workspace", func(t *testing.T) {
+		c, s := newClient(t)
+
+		_, err := c.CreateTerminal(ctx, acp.CreateTerminalRequest{SessionId: "s1", Command: "ls", Cwd: ptr(t.TempDir())})
+		require.Error(t, err)
+		require.Len(t, s.terminalCommands, 1)
+		assert.Contains(t, s.terminalCommands[0].Error, "outside of the agent workspace")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		c, s := newClient(t)
+
+		_, err := c.CreateTerminal(ctx, acp.CreateTerminalRequest{SessionId: "s1", Command: "definitely-not-a-command"})
+		require.Error(t, err)
+		require.Len(t, s.terminalCommands, 1)
+		assert.NotEmpty(t, s.terminalCommands[0].Error)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
 	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
 	"github.com/mcpchecker/mcpchecker/pkg/tokens"
+	"github.com/mcpchecker/mcpchecker/pkg/util"
 )
 
 // RunResult contains the results of running a prompt, including session updates
@@ -20,6 +21,14 @@ import (
 type RunResult struct {
 	Updates []acp.SessionUpdate
 	Usage   *tokens.Usage // Actual token usage from agent (nil if not reported)
+	// FileOperations are the filesystem requests the agent made through the client
+	FileOperations []FileOperation
+	// TerminalCommands are the commands the agent ran in terminals of the client
+	TerminalCommands []TerminalCommand
+	// PermissionRequests are the permission requests of the agent and how they were decided
+	PermissionRequests []PermissionRequest
+	// ModeChanges are the modes the session started in and switched to
+	ModeChanges []ModeChange
 }
 
 type Client interface {
@@ -59,8 +68,8 @@ func (c *client) Start(ctx context.Context) error {
 	initResp, err := c.conn.Initialize(ctx, acp.InitializeRequest{
 		ProtocolVersion: acp.ProtocolVersionNumber,
 		ClientCapabilities: acp.ClientCapabilities{
-			Fs:       acp.FileSystemCapability{ReadTextFile: false, WriteTextFile: false},
-			Terminal: false,
+			Fs:       acp.FileSystemCapability{ReadTextFile: true, WriteTextFile: true},
+			Terminal: true,
 		},
 	})
 	if err != nil {
@@ -77,18 +86,25 @@ func (c *client) Start(ctx context.Context) error {
 }
 
 func (c *client) Run(ctx context.Context, prompt string, servers mcpproxy.ServerManager) ([]acp.SessionUpdate, error) {
-	updates, _, err := c.run(ctx, prompt, servers)
-	return updates, err
+	res, _, err := c.run(ctx, prompt, servers)
+	if err != nil {
+		return nil, err
+	}
+	return res.updates, nil
 }
 
 func (c *client) RunWithUsage(ctx context.Context, prompt string, servers mcpproxy.ServerManager) (*RunResult, error) {
-	updates, promptResp, err := c.run(ctx, prompt, servers)
+	res, promptResp, err := c.run(ctx, prompt, servers)
 	if err != nil {
 		return nil, err
 	}
 
 	result := &RunResult{
-		Updates: updates,
+		Updates:            res.updates,
+		FileOperations:     res.fileOperations,
+		TerminalCommands:   res.terminalCommands,
+		PermissionRequests: res.permissionRequests,
+		ModeChanges:        res.modeChanges,
 	}
 
 	// Prefer usage from the PromptResponse Meta, as it contains the final
@@ -96,25 +112,30 @@ func (c *client) RunWithUsage(ctx context.Context, prompt string, servers mcppro
 	// Fall back to scanning session update Meta fields.
 	result.Usage = ExtractUsageFromPromptResponse(promptResp)
 	if result.Usage == nil {
-		result.Usage = ExtractUsageFromMeta(updates)
+		result.Usage = ExtractUsageFromMeta(res.updates)
 	}
 
 	return result, nil
 }
 
-func (c *client) run(ctx context.Context, prompt string, servers mcpproxy.ServerManager) ([]acp.SessionUpdate, acp.PromptResponse, error) {
+func (c *client) run(ctx context.Context, prompt string, servers mcpproxy.ServerManager) (*sessionResult, acp.PromptResponse, error) {
 	if c.conn == nil {
 		return nil, acp.PromptResponse{}, fmt.Errorf("acpclient.Client.Run must be called after acpclient.Client.Start")
 	}
 
-	tmpDir, err := os.MkdirTemp("", "mcpchecker-agent-")
-	if err != nil {
-		return nil, acp.PromptResponse{}, fmt.Errorf("failed to create temporary directory for agent execution: %w", err)
-	}
+	// Run in the work directory of the task when there is one, the task removes it
+	cwd, ok := util.WorkDirFromContext(ctx)
+	if !ok {
+		tmpDir, err := os.MkdirTemp("", "mcpchecker-agent-")
+		if err != nil {
+			return nil, acp.PromptResponse{}, fmt.Errorf("failed to create temporary directory for agent execution: %w", err)
+		}
 
-	defer func() {
-		_ = os.RemoveAll(tmpDir)
-	}()
+		defer func() {
+			_ = os.RemoveAll(tmpDir)
+		}()
+		cwd = tmpDir
+	}
 
 	mcpServers := make([]acp.McpServer, 0, len(servers.GetMcpServers()))
 	for _, srv := range servers.GetMcpServers() {
@@ -123,23 +144,11 @@ func (c *client) run(ctx context.Context, prompt string, servers mcpproxy.Server
 			return nil, acp.PromptResponse{}, fmt.Errorf("failed to get config for mcp server %q: %w", srv.GetName(), err)
 		}
 
-		headers := make([]acp.HttpHeader, 0, len(cfg.Headers))
-		for k, v := range cfg.Headers {
-			headers = append(headers, acp.HttpHeader{Name: k, Value: v})
-		}
-
-		mcpServers = append(mcpServers, acp.McpServer{
-			Http: &acp.McpServerHttpInline{
-				Name:    srv.GetName(),
-				Url:     cfg.URL,
-				Type:    mcpclient.TransportTypeHttp,
-				Headers: headers,
-			},
-		})
+		mcpServers = append(mcpServers, acpMcpServer(srv.GetName(), cfg))
 	}
 
 	session, err := c.conn.NewSession(ctx, acp.NewSessionRequest{
-		Cwd:        tmpDir,
+		Cwd:        cwd,
 		McpServers: mcpServers,
 	})
 	if err != nil {
@@ -147,9 +156,21 @@ func (c *client) run(ctx context.Context, prompt string, servers mcpproxy.Server
 	}
 
 	// store the session
+	s := NewSession(servers)
+	s.cwd = cwd
+	s.policy, _ = PermissionPolicyFromContext(ctx)
 	c.mu.Lock()
-	c.sessions[session.SessionId] = NewSession(servers)
+	c.sessions[session.SessionId] = s
 	c.mu.Unlock()
+	// kill the commands the agent left running, also when the prompt fails
+	defer s.releaseTerminals()
+
+	if err := c.configureSession(ctx, session, s); err != nil {
+		c.mu.Lock()
+		delete(c.sessions, session.SessionId)
+		c.mu.Unlock()
+		return nil, acp.PromptResponse{}, err
+	}
 
 	// this runs the current prompt to completion
 	// if we were to support multi turn flows, we could run further prompts to the same session from here
@@ -161,16 +182,35 @@ func (c *client) run(ctx context.Context, prompt string, servers mcpproxy.Server
 		return nil, acp.PromptResponse{}, fmt.Errorf("failed to send prompt to acp session: %w", err)
 	}
 
+	s.releaseTerminals()
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// return all the updates from this session, remove it from storage it
-	res := slices.Clone(c.sessions[session.SessionId].updates)
+	// return everything recorded in this session, remove it from storage
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := &sessionResult{
+		updates:            slices.Clone(s.updates),
+		fileOperations:     slices.Clone(s.fileOperations),
+		terminalCommands:   slices.Clone(s.terminalCommands),
+		permissionRequests: slices.Clone(s.permissionRequests),
+		modeChanges:        slices.Clone(s.modeChanges),
+	}
 	delete(c.sessions, session.SessionId)
 
 	return res, promptResp, nil
 }
 
+// sessionResult is what a session recorded while running a prompt
+type sessionResult struct {
+	updates            []acp.SessionUpdate
+	fileOperations     []FileOperation
+	terminalCommands   []TerminalCommand
+	permissionRequests []PermissionRequest
+	modeChanges        []ModeChange
+}
+
 func (c *client) Close(ctx context.Context) error {
 	if c.cfg.Transport != nil {
 		return c.cfg.Transport.Close(ctx)
@@ -195,6 +235,12 @@ func (c *client) startTransport(ctx context.Context) (io.Writer, io.Reader, erro
 
 func (c *client) startSubprocess(ctx context.Context) (io.Writer, io.Reader, error) {
 	c.cmd = exec.CommandContext(ctx, c.cfg.Cmd, c.cfg.Args...)
+	if len(c.cfg.Env) > 0 {
+		c.cmd.Env = os.Environ()
+		for k, v := range c.cfg.Env {
+			c.cmd.Env = append(c.cmd.Env, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
 
 	stdin, err := c.cmd.StdinPipe()
 	if err != nil {
@@ -238,3 +284,45 @@ func (c *client) closeSubprocess(ctx context.Context) error {
 		return ctx.Err()
 	}
 }
+
+// acpMcpServer converts a proxy server config to the matching ACP transport
+func acpMcpServer(name string, cfg *mcpclient.ServerConfig) acp.McpServer {
+	headers := make([]acp.HttpHeader, 0, len(cfg.Headers))
+	for k, v := range cfg.Headers {
+		headers = append(headers, acp.HttpHeader{Name: k, Value: v})
+	}
+
+	switch {
+	case cfg.IsStdio():
+		env := make([]acp.EnvVariable, 0, len(cfg.Env))
+		for k, v := range cfg.Env {
+			env = append(env, acp.EnvVariable{Name: k, Value: v})
+		}
+		return acp.McpServer{
+			Stdio: &acp.McpServerStdio{
+				Name:    name,
+				Command: cfg.Command,
+				Args:    cfg.Args,
+				Env:     env,
+			},
+		}
+	case cfg.IsSse():
+		return acp.McpServer{
+			Sse: &acp.McpServerSseInline{
+				Name:    name,
+				Url:     cfg.URL,
+				Type:    mcpclient.TransportTypeSse,
+				Headers: headers,
+			},
+		}
+	default:
+		return acp.McpServer{
+			Http: &acp.McpServerHttpInline{
+				Name:    name,
+				Url:     cfg.URL,
+				Type:    mcpclient.TransportTypeHttp,
+				Headers: headers,
+			},
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package acpclient
+
+import (
+	"testing"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcpMcpServer(t *testing.T) {
+	t.Run("http", func(t *testing.T) {
+		srv := acpMcpServer("kube", &mcpclient.ServerConfig{
+			Type:    mcpclient.TransportTypeHttp,
+			URL:     "http://localhost:1234/mcp",
+			Headers: map[string]string{"X-Token": "abc"},
+		})
+		require.NotNil(t, srv.Http)
+		assert.Nil(t, srv.Sse)
+		assert.Nil(t, srv.Stdio)
+		assert.Equal(t, "kube", srv.Http.Name)
+		assert.Equal(t, "http://localhost:1234/mcp", srv.Http.Url)
+		require.Len(t, srv.Http.Headers, 1)
+		assert.Equal(t, "X-Token", srv.Http.Headers[0].Name)
+	})
+
+	t.Run("sse", func(t *testing.T) {
+		srv := acpMcpServer("kube", &mcpclient.ServerConfig{
+			Type: mcpclient.TransportTypeSse,
+			URL:  "http://localhost:1234/sse",
+		})
+		require.NotNil(t, srv.Sse)
+		assert.Nil(t, srv.Http)
+		assert.Equal(t, "http://localhost:1234/sse", srv.Sse.Url)
+		assert.Equal(t, mcpclient.TransportTypeSse, srv.Sse.Type)
+	})
+
+	t.Run("stdio", func(t *testing.T) {
+		srv := acpMcpServer("kube", &mcpclient.ServerConfig{
+			Type:    mcpclient.TransportTypeStdio,
+			Command: "/usr/bin/mcpchecker",
+			Args:    []string{"proxy-shim", "--url", "http://localhost:1234/sse"},
+		})
+		require.NotNil(t, srv.Stdio)
+		assert.Nil(t, srv.Http)
+		assert.Equal(t, "/usr/bin/mcpchecker", srv.Stdio.Command)
+		assert.Equal(t, []string{"proxy-shim", "--url", "http://localhost:1234/sse"}, srv.Stdio.Args)
+		assert.NotNil(t, srv.Stdio.Env)
+	})
+}
@@ -20,8 +20,14 @@ type AcpConfig struct {
 	// Cmd and Args are used to spawn a subprocess when Transport is nil.
 	Cmd  string   `json:"cmd"`
 	Args []string `json:"args"`
+	// Env is added to the environment of the subprocess.
+	Env map[string]string `json:"env,omitempty"`
 
 	// Transport, when set, provides the I/O streams directly instead of spawning a subprocess.
 	// This allows in-memory communication with an agent.
 	Transport Transport `json:"-"`
+
+	// Session, when set, configures the mode and config options of new sessions.
+	// It is set by the runner from the agent spec.
+	Session *SessionConfig `json:"-"`
 }
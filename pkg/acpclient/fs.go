@@ -0,0 +1,172 @@
+package acpclient
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// FileOperationRead is a fs/read_text_file request from the agent
+	FileOperationRead = "read"
+	// FileOperationWrite is a fs/write_text_file request from the agent
+	FileOperationWrite = "write"
+)
+
+// FileOperation records a filesystem request the agent made through the client
+type FileOperation struct {
+	Type string `json:"type"`
+	// Path is the path the agent requested, relative to the session working directory when inside it
+	Path string `json:"path"`
+	// Bytes is the size of the content read or written
+	Bytes int    `json:"bytes,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// resolvePath resolves a path requested by the agent, which must be inside the
+// working directory of the session. Symlinks are followed, so that they cannot
+// point outside of it.
+func (s *session) resolvePath(path string) (string, error) {
+	if s.cwd == "" {
+		return "", fmt.Errorf("session has no working directory")
+	}
+
+	root, err := filepath.EvalSymlinks(s.cwd)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve the agent workspace: %w", err)
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.cwd, path)
+	}
+	path = filepath.Clean(path)
+
+	// the working directory may itself be reached through a symlink, e.g. /tmp on macOS
+	if rel, err := filepath.Rel(s.cwd, path); err == nil && isLocal(rel) {
+		path = filepath.Join(root, rel)
+	}
+
+	resolved, err := evalExistingSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || !isLocal(rel) {
+		return "", fmt.Errorf("path %q is outside of the agent workspace", path)
+	}
+
+	return resolved, nil
+}
+
+// relPath returns path relative to the working directory of the session, or path
+// itself if it is not inside it
+func (s *session) relPath(path string) string {
+	root, err := filepath.EvalSymlinks(s.cwd)
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(root, path); err == nil && isLocal(rel) {
+		return rel
+	}
+	return path
+}
+
+// evalExistingSymlinks resolves the symlinks of the longest existing prefix of path,
+// so that paths of files that do not exist yet can be checked as well
+func evalExistingSymlinks(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		return resolved, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path, nil
+	}
+
+	resolvedParent, err := evalExistingSymlinks(parent)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(resolvedParent, filepath.Base(path)), nil
+}
+
+func isLocal(rel string) bool {
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// readTextFile reads a file for the agent inside the session working directory and
+// records it. line is 1-based, and limit caps the number of lines returned.
+func (s *session) readTextFile(path string, line, limit *int) (string, error) {
+	op := FileOperation{Type: FileOperationRead, Path: path}
+
+	var content string
+	resolved, err := s.resolvePath(path)
+	if err == nil {
+		op.Path = s.relPath(resolved)
+
+		var data []byte
+		if data, err = os.ReadFile(resolved); err == nil {
+			content = selectLines(string(data), line, limit)
+			op.Bytes = len(content)
+		}
+	}
+	if err != nil {
+		op.Error = err.Error()
+	}
+
+	s.recordFileOperation(op)
+
+	return content, err
+}
+
+// writeTextFile writes a file for the agent inside the session working directory and records it
+func (s *session) writeTextFile(path, content string) error {
+	op := FileOperation{Type: FileOperationWrite, Path: path, Bytes: len(content)}
+
+	resolved, err := s.resolvePath(path)
+	if err == nil {
+		op.Path = s.relPath(resolved)
+		if err = os.MkdirAll(filepath.Dir(resolved), 0o755); err == nil {
+			err = os.WriteFile(resolved, []byte(content), 0o644)
+		}
+	}
+	if err != nil {
+		op.Error = err.Error()
+	}
+
+	s.recordFileOperation(op)
+
+	return err
+}
+
+func (s *session) recordFileOperation(op FileOperation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fileOperations = append(s.fileOperations, op)
+}
+
+// selectLines returns limit lines of content starting at the 1-based line
+func selectLines(content string, line, limit *int) string {
+	if line == nil && limit == nil {
+		return content
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	start := 0
+	if line != nil && *line > 1 {
+		start = min(*line-1, len(lines))
+	}
+	end := len(lines)
+	if limit != nil && *limit >= 0 {
+		end = min(start+*limit, len(lines))
+	}
+
+	return strings.Join(lines[start:end], "")
+}
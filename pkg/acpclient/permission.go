@@ -0,0 +1,196 @@
+package acpclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/coder/acp-go-sdk"
+)
+
+// PermissionAction is how the client answers a permission request of the agent
+type PermissionAction string
+
+const (
+	// PermissionAllow approves the tool call, preferring the option to always allow it
+	PermissionAllow PermissionAction = "allow"
+	// PermissionDeny rejects the tool call, preferring the option to always reject it
+	PermissionDeny PermissionAction = "deny"
+	// PermissionAsk simulates a user that is asked every time and approves only this call
+	PermissionAsk PermissionAction = "ask"
+)
+
+func (a PermissionAction) validate() error {
+	switch a {
+	case PermissionAllow, PermissionDeny, PermissionAsk:
+		return nil
+	default:
+		return fmt.Errorf("invalid action %q: must be one of allow, deny or ask", a)
+	}
+}
+
+// PermissionPolicy decides the permission requests of ACP agents. Rules are checked
+// in order and the first matching rule decides. Tools of the MCP servers that match
+// no rule are allowed, other tools of the agent (e.g. its own shell or file edits)
+// get Default, which is deny when empty.
+type PermissionPolicy struct {
+	Rules   []PermissionRule `json:"rules,omitempty"`
+	Default PermissionAction `json:"default,omitempty"`
+}
+
+// PermissionRule matches permission requests. All set fields must match.
+type PermissionRule struct {
+	// Server matches tools of this MCP server
+	Server string `json:"server,omitempty"`
+	// Tool matches the name of an MCP tool, or the title of other tools
+	Tool string `json:"tool,omitempty"`
+	// ToolPattern is a regex matched against the name of an MCP tool, or the title of other tools
+	ToolPattern string `json:"toolPattern,omitempty"`
+	// Kind matches the ACP kind of the tool, e.g. execute or edit
+	Kind   string           `json:"kind,omitempty"`
+	Action PermissionAction `json:"action"`
+}
+
+func (p *PermissionPolicy) Validate() error {
+	if p.Default != "" {
+		if err := p.Default.validate(); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+
+	for i, rule := range p.Rules {
+		if err := rule.Action.validate(); err != nil {
+			return fmt.Errorf("invalid rules[%d]: %w", i, err)
+		}
+		if rule.Server == "" && rule.Tool == "" && rule.ToolPattern == "" && rule.Kind == "" {
+			return fmt.Errorf("invalid rules[%d]: at least one of server, tool, toolPattern or kind must be set", i)
+		}
+		if rule.ToolPattern != "" {
+			if _, err := regexp.Compile(rule.ToolPattern); err != nil {
+				return fmt.Errorf("invalid rules[%d]: invalid toolPattern: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// decide returns the action for a permission request and the index of the rule
+// that decided it, or nil if no rule matched
+func (p *PermissionPolicy) decide(req PermissionRequest) (PermissionAction, *int) {
+	if p != nil {
+		for i, rule := range p.Rules {
+			if rule.matches(req) {
+				return rule.Action, &i
+			}
+		}
+	}
+
+	if req.Server != "" {
+		return PermissionAllow, nil
+	}
+	if p == nil || p.Default == "" {
+		return PermissionDeny, nil
+	}
+	return p.Default, nil
+}
+
+func (r *PermissionRule) matches(req PermissionRequest) bool {
+	if r.Server != "" && r.Server != req.Server {
+		return false
+	}
+	if r.Kind != "" && r.Kind != req.Kind {
+		return false
+	}
+
+	name := req.Title
+	if req.Server != "" {
+		name = req.Tool
+	}
+	if r.Tool != "" && r.Tool != name {
+		return false
+	}
+	if r.ToolPattern != "" {
+		if matched, _ := regexp.MatchString(r.ToolPattern, name); !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PermissionRequest records a permission request of the agent and how the client answered it
+type PermissionRequest struct {
+	ToolCallId string `json:"toolCallId"`
+	Title      string `json:"title,omitempty"`
+	Kind       string `json:"kind,omitempty"`
+	// Server and Tool are the MCP tool the request was matched to, if any
+	Server   string           `json:"server,omitempty"`
+	Tool     string           `json:"tool,omitempty"`
+	Decision PermissionAction `json:"decision"`
+	// Rule is the index of the policy rule that decided, unset when the default decided
+	Rule     *int   `json:"rule,omitempty"`
+	OptionId string `json:"optionId,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// requestPermission decides a permission request with the policy of the session,
+// records it, and returns the selected option
+func (s *session) requestPermission(ctx context.Context, params acp.RequestPermissionRequest) (acp.PermissionOptionId, error) {
+	req := s.permissionRequest(ctx, params.ToolCall)
+	req.Decision, req.Rule = s.policy.decide(req)
+
+	opt, err := selectPermissionOption(req.Decision, params.Options)
+	if err != nil {
+		req.Error = err.Error()
+	} else {
+		req.OptionId = string(opt)
+	}
+
+	s.mu.Lock()
+	s.permissionRequests = append(s.permissionRequests, req)
+	s.mu.Unlock()
+
+	return opt, err
+}
+
+// selectPermissionOption picks the option the agent offered that best matches the action
+func selectPermissionOption(action PermissionAction, options []acp.PermissionOption) (acp.PermissionOptionId, error) {
+	var preferred []acp.PermissionOptionKind
+	switch action {
+	case PermissionAllow:
+		preferred = []acp.PermissionOptionKind{acp.PermissionOptionKindAllowAlways, acp.PermissionOptionKindAllowOnce}
+	case PermissionAsk:
+		preferred = []acp.PermissionOptionKind{acp.PermissionOptionKindAllowOnce, acp.PermissionOptionKindAllowAlways}
+	default:
+		preferred = []acp.PermissionOptionKind{acp.PermissionOptionKindRejectAlways, acp.PermissionOptionKindRejectOnce}
+	}
+
+	for _, kind := range preferred {
+		for _, opt := range options {
+			if opt.Kind == kind {
+				return opt.OptionId, nil
+			}
+		}
+	}
+
+	if action == PermissionDeny {
+		return "", fmt.Errorf("no reject option provided")
+	}
+
+	// default to the first option when no allow option is offered
+	return options[0].OptionId, nil
+}
+
+type permissionPolicyKey struct{}
+
+// WithPermissionPolicy returns a context whose ACP sessions decide permission requests with policy
+func WithPermissionPolicy(ctx context.Context, policy *PermissionPolicy) context.Context {
+	return context.WithValue(ctx, permissionPolicyKey{}, policy)
+}
+
+// PermissionPolicyFromContext returns the permission policy of the context, if any
+func PermissionPolicyFromContext(ctx context.Context) (*PermissionPolicy, bool) {
+	policy, ok := ctx.Value(permissionPolicyKey{}).(*PermissionPolicy)
+	return policy, ok && policy != nil
+}
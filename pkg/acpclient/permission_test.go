@@ -0,0 +1,183 @@
+package acpclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/coder/acp-go-sdk"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionPolicy_Validate(t *testing.T) {
+	tt := map[string]struct {
+		policy      PermissionPolicy
+		errContains string
+	}{
+		"valid policy": {
+			policy: PermissionPolicy{
+				Rules: []PermissionRule{
+					{Server: "kubernetes", ToolPattern: "^pods_delete$", Action: PermissionDeny},
+					{Kind: "execute", Action: PermissionAsk},
+				},
+				Default: PermissionAllow,
+			},
+		},
+		"empty policy": {},
+		"invalid default": {
+			policy:      PermissionPolicy{Default: "maybe"},
+			errContains: "invalid default",
+		},
+		"invalid rule action": {
+			policy:      PermissionPolicy{Rules: []PermissionRule{{Server: "kubernetes", Action: "yes"}}},
+			errContains: "invalid rules[0]",
+		},
+		"rule without matcher": {
+			policy:      PermissionPolicy{Rules: []PermissionRule{{Action: PermissionAllow}}},
+			errContains: "at least one of",
+		},
+		"invalid tool pattern": {
+			policy:      PermissionPolicy{Rules: []PermissionRule{{ToolPattern: "(", Action: PermissionAllow}}},
+			errContains: "invalid toolPattern",
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPermissionPolicy_Decide(t *testing.T) {
+	policy := &PermissionPolicy{
+		Rules: []PermissionRule{
+			{Server: "kubernetes", Tool: "pods_delete", Action: PermissionDeny},
+			{Server: "kubernetes", ToolPattern: "^pods_", Action: PermissionAsk},
+			{ToolPattern: "(?i)^read", Action: PermissionAllow},
+			{Kind: "execute", Action: PermissionAsk},
+		},
+	}
+
+	tt := map[string]struct {
+		policy   *PermissionPolicy
+		req      PermissionRequest
+		expected PermissionAction
+		rule     *int
+	}{
+		"exact mcp tool rule": {
+			policy:   policy,
+			req:      PermissionRequest{Title: "mcp__kubernetes__pods_delete", Server: "kubernetes", Tool: "pods_delete"},
+			expected: PermissionDeny,
+			rule:     ptr(0),
+		},
+		"mcp tool pattern rule": {
+			policy:   policy,
+			req:      PermissionRequest{Title: "mcp__kubernetes__pods_list", Server: "kubernetes", Tool: "pods_list"},
+			expected: PermissionAsk,
+			rule:     ptr(1),
+		},
+		"mcp tool without rule is allowed": {
+			policy:   policy,
+			req:      PermissionRequest{Title: "namespaces_list", Server: "kubernetes", Tool: "namespaces_list"},
+			expected: PermissionAllow,
+		},
+		"other tool matched by title": {
+			policy:   policy,
+			req:      PermissionRequest{Title: "Read README.md", Kind: "read"},
+			expected: PermissionAllow,
+			rule:     ptr(2),
+		},
+		"other tool matched by kind": {
+			policy:   policy,
+			req:      PermissionRequest{Title: "kubectl get pods", Kind: "execute"},
+			expected: PermissionAsk,
+			rule:     ptr(3),
+		},
+		"other tool without rule gets deny by default": {
+			policy:   policy,
+			req:      PermissionRequest{Title: "Edit main.go", Kind: "edit"},
+			expected: PermissionDeny,
+		},
+		"other tool without rule gets configured default": {
+			policy:   &PermissionPolicy{Default: PermissionAllow},
+			req:      PermissionRequest{Title: "Edit main.go", Kind: "edit"},
+			expected: PermissionAllow,
+		},
+		"no policy allows mcp tools": {
+			req:      PermissionRequest{Title: "pods_list", Server: "kubernetes", Tool: "pods_list"},
+			expected: PermissionAllow,
+		},
+		"no policy denies other tools": {
+			req:      PermissionRequest{Title: "Edit main.go"},
+			expected: PermissionDeny,
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			action, rule := tc.policy.decide(tc.req)
+			assert.Equal(t, tc.expected, action)
+			assert.Equal(t, tc.rule, rule)
+		})
+	}
+}
+
+func TestClient_RequestPermission_Policy(t *testing.T) {
+	options := []acp.PermissionOption{
+		{OptionId: "allow-once", Kind: acp.PermissionOptionKindAllowOnce},
+		{OptionId: "allow-always", Kind: acp.PermissionOptionKindAllowAlways},
+		{OptionId: "reject-once", Kind: acp.PermissionOptionKindRejectOnce},
+	}
+
+	s := newTestSession([]*mcp.Tool{{Name: "pods_list"}, {Name: "pods_delete"}})
+	s.policy = &PermissionPolicy{
+		Rules: []PermissionRule{
+			{Server: "test-server", Tool: "pods_delete", Action: PermissionDeny},
+			{Kind: string(acp.ToolKindExecute), Action: PermissionAsk},
+		},
+	}
+	c := &client{sessions: map[acp.SessionId]*session{"s1": s}}
+
+	calls := []acp.ToolCallUpdate{
+		{ToolCallId: "call-1", Title: ptr("mcp__test-server__pods_list")},
+		{ToolCallId: "call-2", Title: ptr("mcp__test-server__pods_delete")},
+		{ToolCallId: "call-3", Title: ptr("kubectl get pods"), Kind: ptr(acp.ToolKindExecute)},
+		{ToolCallId: "call-4", Title: ptr("Edit main.go"), Kind: ptr(acp.ToolKindEdit)},
+	}
+	expectedOpts := []string{"allow-always", "reject-once", "allow-once", "reject-once"}
+
+	for i, call := range calls {
+		resp, err := c.RequestPermission(context.Background(), acp.RequestPermissionRequest{
+			SessionId: "s1",
+			ToolCall:  call,
+			Options:   options,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp.Outcome.Selected)
+		assert.Equal(t, acp.PermissionOptionId(expectedOpts[i]), resp.Outcome.Selected.OptionId, "call %d", i+1)
+	}
+
+	assert.Equal(t, []PermissionRequest{
+		{ToolCallId: "call-1", Title: "mcp__test-server__pods_list", Server: "test-server", Tool: "pods_list", Decision: PermissionAllow, OptionId: "allow-always"},
+		{ToolCallId: "call-2", Title: "mcp__test-server__pods_delete", Server: "test-server", Tool: "pods_delete", Decision: PermissionDeny, Rule: ptr(0), OptionId: "reject-once"},
+		{ToolCallId: "call-3", Title: "kubectl get pods", Kind: "execute", Decision: PermissionAsk, Rule: ptr(1), OptionId: "allow-once"},
+		{ToolCallId: "call-4", Title: "Edit main.go", Kind: "edit", Decision: PermissionDeny, OptionId: "reject-once"},
+	}, s.permissionRequests)
+
+	_, err := c.RequestPermission(context.Background(), acp.RequestPermissionRequest{
+		SessionId: "s1",
+		ToolCall:  acp.ToolCallUpdate{ToolCallId: "call-5", Title: ptr("Edit go.mod")},
+		Options:   options[:2],
+	})
+	require.Error(t, err)
+	require.Len(t, s.permissionRequests, 5)
+	assert.Equal(t, "no reject option provided", s.permissionRequests[4].Error)
+}
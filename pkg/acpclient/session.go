@@ -14,6 +14,18 @@ type session struct {
 	updates          []acp.SessionUpdate // track all the updates in a json serializable way for future analysis
 	toolCallStatuses map[acp.ToolCallId]*acp.SessionToolCallUpdate
 	mcpServers       mcpproxy.ServerManager
+	// cwd is the working directory of the session, which file requests and terminals are confined to
+	cwd              string
+	fileOperations   []FileOperation
+	terminals        map[string]*terminal
+	nextTerminalID   int
+	terminalCommands []TerminalCommand
+	// policy decides permission requests, nil for the default policy
+	policy             *PermissionPolicy
+	permissionRequests []PermissionRequest
+	modeChanges        []ModeChange
+	// home is the virtual $HOME of terminal commands, created with the first terminal
+	home string
 }
 
 func NewSession(mcpServers mcpproxy.ServerManager) *session {
@@ -21,6 +33,7 @@ func NewSession(mcpServers mcpproxy.ServerManager) *session {
 		updates:          make([]acp.SessionUpdate, 0),
 		toolCallStatuses: make(map[acp.ToolCallId]*acp.SessionToolCallUpdate),
 		mcpServers:       mcpServers,
+		terminals:        make(map[string]*terminal),
 	}
 }
 
@@ -41,25 +54,29 @@ func (s *session) recordPermissionToolCall(call acp.ToolCallUpdate) {
 	})
 }
 
-func (s *session) isAllowedToolCall(ctx context.Context, call acp.ToolCallUpdate) bool {
+// permissionRequest describes the tool call of a permission request, matching it to
+// a tool of the MCP servers when possible
+func (s *session) permissionRequest(ctx context.Context, call acp.ToolCallUpdate) PermissionRequest {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	var title string
-	if call.Title != nil {
-		title = *call.Title
-	} else {
-		// look up the original update with the tool call id
-		curr, ok := s.toolCallStatuses[call.ToolCallId]
-		if !ok {
-			return false
-		}
+	req := PermissionRequest{ToolCallId: string(call.ToolCallId)}
 
-		if curr.Title == nil {
-			return false
-		}
+	// fall back to the original update with the tool call id
+	curr := s.toolCallStatuses[call.ToolCallId]
+	if call.Title != nil {
+		req.Title = *call.Title
+	} else if curr != nil && curr.Title != nil {
+		req.Title = *curr.Title
+	}
+	if call.Kind != nil {
+		req.Kind = string(*call.Kind)
+	} else if curr != nil && curr.Kind != nil {
+		req.Kind = string(*curr.Kind)
+	}
 
-		title = *curr.Title
+	if req.Title == "" {
+		return req
 	}
 
 	for _, srv := range s.mcpServers.GetMcpServers() {
@@ -68,13 +85,15 @@ func (s *session) isAllowedToolCall(ctx context.Context, call acp.ToolCallUpdate
 				continue
 			}
 
-			if toolTitleProbablyMatches(title, t.Title, t.Name, srv.GetName()) {
-				return true
+			if toolTitleProbablyMatches(req.Title, t.Title, t.Name, srv.GetName()) {
+				req.Server = srv.GetName()
+				req.Tool = t.Name
+				return req
 			}
 		}
 	}
 
-	return false
+	return req
 }
 
 func (s *session) update(update acp.SessionUpdate) {
@@ -82,6 +101,13 @@ func (s *session) update(update acp.SessionUpdate) {
 	defer s.mu.Unlock()
 	s.updates = append(s.updates, update)
 
+	if update.CurrentModeUpdate != nil {
+		s.modeChanges = append(s.modeChanges, ModeChange{
+			Mode:   string(update.CurrentModeUpdate.CurrentModeId),
+			Source: ModeSourceAgent,
+		})
+	}
+
 	// handle tool call updates
 	if update.ToolCall != nil {
 		s.toolCallStatusUpdateLocked(&acp.SessionToolCallUpdate{
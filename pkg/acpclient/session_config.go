@@ -0,0 +1,109 @@
+package acpclient
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/coder/acp-go-sdk"
+)
+
+const (
+	// ModeSourceSession is the mode a session started in
+	ModeSourceSession = "session"
+	// ModeSourceClient is a mode the client set from the session config
+	ModeSourceClient = "client"
+	// ModeSourceAgent is a mode change the agent reported in a session update
+	ModeSourceAgent = "agent"
+)
+
+// SessionConfig configures new ACP sessions before the prompt is sent
+type SessionConfig struct {
+	// Mode is the id of the session mode to run in, e.g. plan
+	Mode string `json:"mode,omitempty"`
+	// ConfigOptions maps ids of session config options to the ids of their values
+	ConfigOptions map[string]string `json:"configOptions,omitempty"`
+}
+
+// ModeChange records the mode of a session changing
+type ModeChange struct {
+	Mode   string `json:"mode"`
+	Source string `json:"source"`
+}
+
+// configureSession records the initial mode of a new session, then sets the mode and
+// config options of the session config. The agent must advertise them.
+func (c *client) configureSession(ctx context.Context, resp acp.NewSessionResponse, s *session) error {
+	if resp.Modes != nil {
+		s.recordModeChange(string(resp.Modes.CurrentModeId), ModeSourceSession)
+	}
+
+	cfg := c.cfg.Session
+	if cfg == nil {
+		return nil
+	}
+
+	if cfg.Mode != "" {
+		if resp.Modes == nil {
+			return fmt.Errorf("agent does not support session modes, cannot set mode %q", cfg.Mode)
+		}
+
+		modeId := acp.SessionModeId(cfg.Mode)
+		available := make([]string, 0, len(resp.Modes.AvailableModes))
+		for _, m := range resp.Modes.AvailableModes {
+			available = append(available, string(m.Id))
+		}
+		if !slices.Contains(available, cfg.Mode) {
+			return fmt.Errorf("agent does not support mode %q, available modes: %v", cfg.Mode, available)
+		}
+
+		if resp.Modes.CurrentModeId != modeId {
+			if _, err := c.conn.SetSessionMode(ctx, acp.SetSessionModeRequest{
+				SessionId: resp.SessionId,
+				ModeId:    modeId,
+			}); err != nil {
+				return fmt.Errorf("failed to set session mode %q: %w", cfg.Mode, err)
+			}
+			s.recordModeChange(cfg.Mode, ModeSourceClient)
+		}
+	}
+
+	ids := make([]string, 0, len(cfg.ConfigOptions))
+	for id := range cfg.ConfigOptions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		if !hasConfigOption(resp.ConfigOptions, id) {
+			return fmt.Errorf("agent does not support config option %q", id)
+		}
+
+		if _, err := c.conn.SetSessionConfigOption(ctx, acp.SetSessionConfigOptionRequest{
+			SessionId: resp.SessionId,
+			ConfigId:  acp.SessionConfigId(id),
+			Value:     acp.SessionConfigValueId(cfg.ConfigOptions[id]),
+		}); err != nil {
+			return fmt.Errorf("failed to set config option %q to %q: %w", id, cfg.ConfigOptions[id], err)
+		}
+	}
+
+	return nil
+}
+
+func hasConfigOption(options []acp.SessionConfigOption, id string) bool {
+	for _, opt := range options {
+		if opt.Select != nil && string(opt.Select.Id) == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *session) recordModeChange(mode, source string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.modeChanges = append(s.modeChanges, ModeChange{Mode: mode, Source: source})
+}
@@ -0,0 +1,176 @@
+package acpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/coder/acp-go-sdk"
+	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAgent is an in-memory ACP agent with a default and a plan mode and a model config option
+type fakeAgent struct {
+	conn          *acp.AgentSideConnection
+	modeRequests  []acp.SessionModeId
+	configOptions map[acp.SessionConfigId]acp.SessionConfigValueId
+	// switchTo is a mode the agent switches to itself while running the prompt
+	switchTo acp.SessionModeId
+}
+
+var _ acp.Agent = &fakeAgent{}
+
+func (a *fakeAgent) Authenticate(context.Context, acp.AuthenticateRequest) (acp.AuthenticateResponse, error) {
+	return acp.AuthenticateResponse{}, nil
+}
+
+func (a *fakeAgent) Initialize(context.Context, acp.InitializeRequest) (acp.InitializeResponse, error) {
+	return acp.InitializeResponse{
+		ProtocolVersion:   acp.ProtocolVersionNumber,
+		AgentCapabilities: acp.AgentCapabilities{McpCapabilities: acp.McpCapabilities{Http: true}},
+	}, nil
+}
+
+func (a *fakeAgent) Cancel(context.Context, acp.CancelNotification) error { return nil }
+
+func (a *fakeAgent) NewSession(context.Context, acp.NewSessionRequest) (acp.NewSessionResponse, error) {
+	return acp.NewSessionResponse{
+		SessionId: "s1",
+		Modes: &acp.SessionModeState{
+			AvailableModes: []acp.SessionMode{{Id: "default", Name: "Default"}, {Id: "plan", Name: "Plan"}},
+			CurrentModeId:  "default",
+		},
+		ConfigOptions: []acp.SessionConfigOption{{Select: &acp.SessionConfigOptionSelect{
+			Id:           "model",
+			Name:         "Model",
+			CurrentValue: "small",
+			Type:         "select",
+			Options: acp.SessionConfigSelectOptions{Ungrouped: &acp.SessionConfigSelectOptionsUngrouped{
+				{Name: "Small", Value: "small"},
+				{Name: "Large", Value: "large"},
+			}},
+		}}},
+	}, nil
+}
+
+func (a *fakeAgent) SetSessionMode(_ context.Context, params acp.SetSessionModeRequest) (acp.SetSessionModeResponse, error) {
+	a.modeRequests = append(a.modeRequests, params.ModeId)
+	return acp.SetSessionModeResponse{}, nil
+}
+
+func (a *fakeAgent) SetSessionConfigOption(_ context.Context, params acp.SetSessionConfigOptionRequest) (acp.SetSessionConfigOptionResponse, error) {
+	if params.ConfigId != "model" {
+		return acp.SetSessionConfigOptionResponse{}, fmt.Errorf("unknown config option %q", params.ConfigId)
+	}
+	a.configOptions[params.ConfigId] = params.Value
+	return acp.SetSessionConfigOptionResponse{ConfigOptions: []acp.SessionConfigOption{}}, nil
+}
+
+func (a *fakeAgent) Prompt(ctx context.Context, params acp.PromptRequest) (acp.PromptResponse, error) {
+	if a.switchTo != "" {
+		if err := a.conn.SessionUpdate(ctx, acp.SessionNotification{
+			SessionId: params.SessionId,
+			Update: acp.SessionUpdate{CurrentModeUpdate: &acp.SessionCurrentModeUpdate{
+				CurrentModeId: a.switchTo,
+				SessionUpdate: "current_mode_update",
+			}},
+		}); err != nil {
+			return acp.PromptResponse{}, err
+		}
+	}
+	return acp.PromptResponse{StopReason: acp.StopReasonEndTurn}, nil
+}
+
+// fakeAgentTransport connects the client to a fakeAgent with in-memory pipes
+type fakeAgentTransport struct {
+	agent *fakeAgent
+}
+
+func (t *fakeAgentTransport) Start(context.Context) (io.Writer, io.Reader, error) {
+	clientToAgentReader, clientToAgentWriter := io.Pipe()
+	agentToClientReader, agentToClientWriter := io.Pipe()
+	t.agent.conn = acp.NewAgentSideConnection(t.agent, agentToClientWriter, clientToAgentReader)
+	return clientToAgentWriter, agentToClientReader, nil
+}
+
+func (t *fakeAgentTransport) Close(context.Context) error { return nil }
+
+func TestClient_SessionConfig(t *testing.T) {
+	tt := map[string]struct {
+		session             *SessionConfig
+		switchTo            acp.SessionModeId
+		expectedModes       []acp.SessionModeId
+		expectedOptions     map[acp.SessionConfigId]acp.SessionConfigValueId
+		expectedModeChanges []ModeChange
+		errContains         string
+	}{
+		"no session config": {
+			expectedOptions:     map[acp.SessionConfigId]acp.SessionConfigValueId{},
+			expectedModeChanges: []ModeChange{{Mode: "default", Source: ModeSourceSession}},
+		},
+		"mode and config option": {
+			session:         &SessionConfig{Mode: "plan", ConfigOptions: map[string]string{"model": "large"}},
+			expectedModes:   []acp.SessionModeId{"plan"},
+			expectedOptions: map[acp.SessionConfigId]acp.SessionConfigValueId{"model": "large"},
+			expectedModeChanges: []ModeChange{
+				{Mode: "default", Source: ModeSourceSession},
+				{Mode: "plan", Source: ModeSourceClient},
+			},
+		},
+		"current mode is not set again": {
+			session:             &SessionConfig{Mode: "default"},
+			expectedOptions:     map[acp.SessionConfigId]acp.SessionConfigValueId{},
+			expectedModeChanges: []ModeChange{{Mode: "default", Source: ModeSourceSession}},
+		},
+		"agent switches mode": {
+			session:         &SessionConfig{Mode: "plan"},
+			switchTo:        "default",
+			expectedModes:   []acp.SessionModeId{"plan"},
+			expectedOptions: map[acp.SessionConfigId]acp.SessionConfigValueId{},
+			expectedModeChanges: []ModeChange{
+				{Mode: "default", Source: ModeSourceSession},
+				{Mode: "plan", Source: ModeSourceClient},
+				{Mode: "default", Source: ModeSourceAgent},
+			},
+		},
+		"unknown mode": {
+			session:     &SessionConfig{Mode: "yolo"},
+			errContains: `agent does not support mode "yolo"`,
+		},
+		"unknown config option": {
+			session:     &SessionConfig{ConfigOptions: map[string]string{"temperature": "high"}},
+			errContains: `agent does not support config option "temperature"`,
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			ctx := context.Background()
+			agent := &fakeAgent{
+				configOptions: map[acp.SessionConfigId]acp.SessionConfigValueId{},
+				switchTo:      tc.switchTo,
+			}
+			c := NewClient(ctx, &AcpConfig{
+				Transport: &fakeAgentTransport{agent: agent},
+				Session:   tc.session,
+			})
+			require.NoError(t, c.Start(ctx))
+			defer c.Close(ctx)
+
+			res, err := c.RunWithUsage(ctx, "hello", &mockServerManager{servers: []mcpproxy.Server{}})
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedModes, agent.modeRequests)
+			assert.Equal(t, tc.expectedOptions, agent.configOptions)
+			assert.Equal(t, tc.expectedModeChanges, res.ModeChanges)
+		})
+	}
+}
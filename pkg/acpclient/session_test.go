@@ -24,6 +24,7 @@ func (m *mockServer) GetAllowedTools(_ context.Context) []*mcp.Tool { return m.a
 func (m *mockServer) GetInstructions() string                       { return "" }
 func (m *mockServer) Close() error                                  { return nil }
 func (m *mockServer) GetCallHistory() mcpproxy.CallHistory          { return mcpproxy.CallHistory{} }
+func (m *mockServer) ResetCallHistory()                             {}
 func (m *mockServer) WaitReady(_ context.Context) error             { return nil }
 
 // mockServerManager implements mcpproxy.ServerManager for testing
@@ -36,11 +37,12 @@ func (m *mockServerManager) GetMcpServers() []mcpproxy.Server         { return m
 func (m *mockServerManager) Start(_ context.Context) error            { return nil }
 func (m *mockServerManager) Close() error                             { return nil }
 func (m *mockServerManager) GetAllCallHistory() *mcpproxy.CallHistory { return nil }
+func (m *mockServerManager) ResetCallHistory()                        {}
 func (m *mockServerManager) GetCallHistoryForServer(_ string) (mcpproxy.CallHistory, bool) {
 	return mcpproxy.CallHistory{}, false
 }
 
-func TestSession_IsAllowedToolCall(t *testing.T) {
+func TestSession_PermissionRequest(t *testing.T) {
 	tt := map[string]struct {
 		allowedTools []*mcp.Tool
 		call         acp.ToolCallUpdate
@@ -89,14 +91,14 @@ func TestSession_IsAllowedToolCall(t *testing.T) {
 			}
 			s := NewSession(mgr)
 
-			result := s.isAllowedToolCall(context.Background(), tc.call)
-			assert.Equal(t, tc.expected, result)
+			req := s.permissionRequest(context.Background(), tc.call)
+			assert.Equal(t, tc.expected, req.Server == "test-server")
 		})
 	}
 }
 
-func TestSession_IsAllowedToolCall_WithPriorUpdate(t *testing.T) {
-	// Test that IsAllowedToolCall uses title from prior update when call.Title is nil
+func TestSession_PermissionRequest_WithPriorUpdate(t *testing.T) {
+	// Test that permissionRequest uses title from prior update when call.Title is nil
 	mgr := &mockServerManager{
 		servers: []mcpproxy.Server{
 			&mockServer{name: "test-server", allowedTools: []*mcp.Tool{{Name: "read_file", Title: "Read File"}}},
@@ -116,8 +118,10 @@ func TestSession_IsAllowedToolCall_WithPriorUpdate(t *testing.T) {
 		Title:      nil,
 	}
 
-	result := s.isAllowedToolCall(context.Background(), call)
-	assert.True(t, result)
+	req := s.permissionRequest(context.Background(), call)
+	assert.Equal(t, "Read File", req.Title)
+	assert.Equal(t, "test-server", req.Server)
+	assert.Equal(t, "read_file", req.Tool)
 }
 
 func TestSession_ToolCallStatusUpdateLocked(t *testing.T) {
@@ -266,7 +270,7 @@ func TestToolTitleProbablyMatches(t *testing.T) {
 	}
 }
 
-func TestSession_IsAllowedToolCall_FuzzyMatching(t *testing.T) {
+func TestSession_PermissionRequest_FuzzyMatching(t *testing.T) {
 	tt := map[string]struct {
 		serverName   string
 		allowedTools []*mcp.Tool
@@ -313,8 +317,8 @@ func TestSession_IsAllowedToolCall_FuzzyMatching(t *testing.T) {
 				Title:      ptr(tc.callTitle),
 			}
 
-			result := s.isAllowedToolCall(context.Background(), call)
-			assert.Equal(t, tc.expected, result)
+			req := s.permissionRequest(context.Background(), call)
+			assert.Equal(t, tc.expected, req.Server == tc.serverName)
 		})
 	}
 }
@@ -0,0 +1,245 @@
+package acpclient
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/coder/acp-go-sdk"
+	"github.com/mcpchecker/mcpchecker/pkg/util"
+)
+
+// terminalWaitDelay bounds how long to wait for the output of a killed command,
+// e.g. when a background process it started keeps the output open
+const terminalWaitDelay = time.Second
+
+// TerminalCommand records a command the agent ran in a terminal of the client
+type TerminalCommand struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+	// Cwd is the working directory of the command, relative to the session working directory
+	Cwd      string `json:"cwd,omitempty"`
+	ExitCode *int   `json:"exitCode,omitempty"`
+	Signal   string `json:"signal,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// CommandLine returns the command with its arguments, separated by spaces
+func (c TerminalCommand) CommandLine() string {
+	return strings.TrimSpace(c.Command + " " + strings.Join(c.Args, " "))
+}
+
+type terminal struct {
+	cmd    *exec.Cmd
+	cancel context.CancelFunc
+	output *limitedBuffer
+	done   chan struct{}
+
+	mu   sync.Mutex
+	exit *acp.TerminalExitStatus
+}
+
+func (t *terminal) exitStatus() *acp.TerminalExitStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.exit
+}
+
+// createTerminal starts a command for the agent in the session working directory and records it
+func (s *session) createTerminal(params acp.CreateTerminalRequest) (string, error) {
+	record := TerminalCommand{Command: params.Command, Args: params.Args}
+
+	dir := s.cwd
+	var err error
+	if params.Cwd != nil {
+		dir, err = s.resolvePath(*params.Cwd)
+	}
+	if err == nil && dir == "" {
+		err = fmt.Errorf("session has no working directory")
+	}
+	if err != nil {
+		record.Error = err.Error()
+		s.recordTerminalCommand(record)
+		return "", err
+	}
+	if rel := s.relPath(dir); rel != "." {
+		record.Cwd = rel
+	}
+
+	home, err := s.terminalHome()
+	if err != nil {
+		record.Error = err.Error()
+		s.recordTerminalCommand(record)
+		return "", err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, params.Command, params.Args...)
+	cmd.Dir = dir
+	// Commands only get PATH, locale and terminal settings, like sandboxed shell agents,
+	// so that they can't use the credentials or config of the user
+	cmd.Env = append(util.FilterEnv(os.Environ()), fmt.Sprintf("HOME=%s", home))
+	for _, env := range params.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", env.Name, env.Value))
+	}
+	cmd.WaitDelay = terminalWaitDelay
+
+	limit := 0
+	if params.OutputByteLimit != nil {
+		limit = *params.OutputByteLimit
+	}
+	output := &limitedBuffer{limit: limit}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		record.Error = err.Error()
+		s.recordTerminalCommand(record)
+		return "", fmt.Errorf("failed to start %q: %w", params.Command, err)
+	}
+
+	t := &terminal{cmd: cmd, cancel: cancel, output: output, done: make(chan struct{})}
+
+	s.mu.Lock()
+	s.nextTerminalID++
+	id := fmt.Sprintf("term_%d", s.nextTerminalID)
+	index := len(s.terminalCommands)
+	s.terminalCommands = append(s.terminalCommands, record)
+	s.terminals[id] = t
+	s.mu.Unlock()
+
+	go func() {
+		defer close(t.done)
+		_ = cmd.Wait()
+
+		exit := &acp.TerminalExitStatus{}
+		if code := cmd.ProcessState.ExitCode(); code >= 0 {
+			exit.ExitCode = &code
+		} else {
+			signal := strings.TrimPrefix(cmd.ProcessState.String(), "signal: ")
+			exit.Signal = &signal
+		}
+
+		t.mu.Lock()
+		t.exit = exit
+		t.mu.Unlock()
+
+		s.mu.Lock()
+		s.terminalCommands[index].ExitCode = exit.ExitCode
+		if exit.Signal != nil {
+			s.terminalCommands[index].Signal = *exit.Signal
+		}
+		s.mu.Unlock()
+	}()
+
+	return id, nil
+}
+
+func (s *session) terminal(id string) (*terminal, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.terminals[id]
+	if !ok {
+		return nil, fmt.Errorf("no terminal with id %q", id)
+	}
+	return t, nil
+}
+
+// releaseTerminal kills the command of a terminal if it is still running and forgets the terminal
+func (s *session) releaseTerminal(id string) error {
+	s.mu.Lock()
+	t, ok := s.terminals[id]
+	delete(s.terminals, id)
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("no terminal with id %q", id)
+	}
+
+	t.cancel()
+	<-t.done
+	return nil
+}
+
+// terminalHome returns the virtual $HOME of terminal commands, creating it on first use
+func (s *session) terminalHome() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.home == "" {
+		home, err := os.MkdirTemp("", "mcpchecker-home-")
+		if err != nil {
+			return "", fmt.Errorf("failed to create virtual home directory: %w", err)
+		}
+		s.home = home
+	}
+
+	return s.home, nil
+}
+
+// releaseTerminals kills the commands the agent left running when the session ends
+// and removes their virtual $HOME
+func (s *session) releaseTerminals() {
+	s.mu.Lock()
+	terminals := s.terminals
+	s.terminals = make(map[string]*terminal)
+	home := s.home
+	s.home = ""
+	s.mu.Unlock()
+
+	for _, t := range terminals {
+		t.cancel()
+		<-t.done
+	}
+
+	if home != "" {
+		_ = os.RemoveAll(home)
+	}
+}
+
+func (s *session) recordTerminalCommand(record TerminalCommand) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.terminalCommands = append(s.terminalCommands, record)
+}
+
+// limitedBuffer collects the output of a terminal, keeping only the last limit bytes
+// if limit is positive
+type limitedBuffer struct {
+	mu        sync.Mutex
+	buf       []byte
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, p...)
+	if b.limit > 0 && len(b.buf) > b.limit {
+		start := len(b.buf) - b.limit
+		// truncate at a character boundary
+		for start < len(b.buf) && !utf8.RuneStart(b.buf[start]) {
+			start++
+		}
+		b.buf = append([]byte(nil), b.buf[start:]...)
+		b.truncated = true
+	}
+
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf), b.truncated
+}
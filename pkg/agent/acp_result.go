@@ -2,17 +2,23 @@ package agent
 
 import (
 	"github.com/coder/acp-go-sdk"
+	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
 	"github.com/mcpchecker/mcpchecker/pkg/tokens"
 )
 
 // acpResult is a shared AgentResult implementation for ACP-based runners.
 type acpResult struct {
-	updates     []acp.SessionUpdate
-	prompt      string
-	actualUsage *tokens.Usage
+	updates            []acp.SessionUpdate
+	prompt             string
+	actualUsage        *tokens.Usage
+	fileOperations     []acpclient.FileOperation
+	terminalCommands   []acpclient.TerminalCommand
+	permissionRequests []acpclient.PermissionRequest
+	modeChanges        []acpclient.ModeChange
 }
 
 var _ AgentResult = &acpResult{}
+var _ ClientActivityResult = &acpResult{}
 
 func (res *acpResult) GetOutput() []OutputStep {
 	return ExtractOutputSteps(res.updates)
@@ -30,6 +36,22 @@ func (res *acpResult) getThinking() string {
 	return ExtractThinking(res.updates)
 }
 
+func (res *acpResult) GetFileOperations() []acpclient.FileOperation {
+	return res.fileOperations
+}
+
+func (res *acpResult) GetTerminalCommands() []acpclient.TerminalCommand {
+	return res.terminalCommands
+}
+
+func (res *acpResult) GetPermissionRequests() []acpclient.PermissionRequest {
+	return res.permissionRequests
+}
+
+func (res *acpResult) GetModeChanges() []acpclient.ModeChange {
+	return res.modeChanges
+}
+
 func (res *acpResult) GetRawUpdates() any {
 	return res.updates
 }
@@ -38,9 +38,13 @@ func (r *acpRunner) RunTask(ctx context.Context, prompt string) (AgentResult, er
 	}
 
 	return &acpResult{
-		updates:     result.Updates,
-		prompt:      prompt,
-		actualUsage: result.Usage,
+		updates:            result.Updates,
+		prompt:             prompt,
+		actualUsage:        result.Usage,
+		fileOperations:     result.FileOperations,
+		terminalCommands:   result.TerminalCommands,
+		permissionRequests: result.PermissionRequests,
+		modeChanges:        result.ModeChanges,
 	}, nil
 }
 
@@ -150,27 +150,31 @@ func TestAcpRunnerResult_GetOutput_WithAgentMessageChunk(t *testing.T) {
 type mockServer struct {
 	name         string
 	allowedTools []*mcp.Tool
+	config       *mcpclient.ServerConfig
 }
 
 func (m *mockServer) Run(_ context.Context) error                   { return nil }
-func (m *mockServer) GetConfig() (*mcpclient.ServerConfig, error)   { return nil, nil }
+func (m *mockServer) GetConfig() (*mcpclient.ServerConfig, error)   { return m.config, nil }
 func (m *mockServer) GetName() string                               { return m.name }
 func (m *mockServer) GetAllowedTools(_ context.Context) []*mcp.Tool { return m.allowedTools }
 func (m *mockServer) GetInstructions() string                       { return "" }
 func (m *mockServer) Close() error                                  { return nil }
 func (m *mockServer) GetCallHistory() mcpproxy.CallHistory          { return mcpproxy.CallHistory{} }
+func (m *mockServer) ResetCallHistory()                             {}
 func (m *mockServer) WaitReady(_ context.Context) error             { return nil }
 
 // mockServerManager implements mcpproxy.ServerManager for testing
 type mockServerManager struct {
 	servers []mcpproxy.Server
+	files   []string
 }
 
-func (m *mockServerManager) GetMcpServerFiles() ([]string, error)     { return nil, nil }
+func (m *mockServerManager) GetMcpServerFiles() ([]string, error)     { return m.files, nil }
 func (m *mockServerManager) GetMcpServers() []mcpproxy.Server         { return m.servers }
 func (m *mockServerManager) Start(_ context.Context) error            { return nil }
 func (m *mockServerManager) Close() error                             { return nil }
 func (m *mockServerManager) GetAllCallHistory() *mcpproxy.CallHistory { return nil }
+func (m *mockServerManager) ResetCallHistory()                        {}
 func (m *mockServerManager) GetCallHistoryForServer(_ string) (mcpproxy.CallHistory, bool) {
 	return mcpproxy.CallHistory{}, false
 }
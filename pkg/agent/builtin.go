@@ -5,6 +5,10 @@ var builtinTypes = map[string]BuiltinAgent{
 	"openai-agent": &LLMAgent{}, // deprecated alias
 	"openai-acp":   &LLMAgent{}, // deprecated alias
 	"claude-code":  &ClaudeCodeAgent{},
+	"codex":        &CodexAgent{},
+	"gemini":       &GeminiAgent{},
+	"goose":        &GooseAgent{},
+	"opencode":     &OpenCodeAgent{},
 }
 
 // GetBuiltinType retrieves a builtin agent by name
@@ -1,9 +1,15 @@
 package agent
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -34,6 +40,26 @@ func TestGetBuiltinType(t *testing.T) {
 			shouldExist:  true,
 			expectedName: "claude-code",
 		},
+		"codex exists": {
+			agentType:    "codex",
+			shouldExist:  true,
+			expectedName: "codex",
+		},
+		"gemini exists": {
+			agentType:    "gemini",
+			shouldExist:  true,
+			expectedName: "gemini",
+		},
+		"goose exists": {
+			agentType:    "goose",
+			shouldExist:  true,
+			expectedName: "goose",
+		},
+		"opencode exists": {
+			agentType:    "opencode",
+			shouldExist:  true,
+			expectedName: "opencode",
+		},
 		"non-existent agent": {
 			agentType:   "non-existent",
 			shouldExist: false,
@@ -65,6 +91,10 @@ func TestListBuiltinTypes(t *testing.T) {
 	expectedAgents := map[string]bool{
 		"llm-agent":   false,
 		"claude-code": false,
+		"codex":       false,
+		"gemini":      false,
+		"goose":       false,
+		"opencode":    false,
 	}
 
 	for _, agent := range agents {
@@ -155,3 +185,126 @@ func TestClaudeCodeAgent(t *testing.T) {
 		require.NotNil(t, spec.AcpConfig)
 	})
 }
+
+// fakeBinary puts an executable shell script named name on an otherwise empty PATH
+func fakeBinary(t *testing.T, name, script string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script+"\n"), 0o755)
+	require.NoError(t, err)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+"/usr/bin:/bin")
+}
+
+func TestCLIAgents(t *testing.T) {
+	tests := map[string]struct {
+		agent        BuiltinAgent
+		binary       string
+		model        string
+		expectedName string
+		expectedAcp  *acpclient.AcpConfig
+	}{
+		"codex": {
+			agent:        &CodexAgent{},
+			binary:       "codex",
+			model:        "gpt-5-codex",
+			expectedName: "codex-gpt-5-codex",
+		},
+		"gemini": {
+			agent:        &GeminiAgent{},
+			binary:       "gemini",
+			model:        "gemini-2.5-pro",
+			expectedName: "gemini-gemini-2.5-pro",
+			expectedAcp:  &acpclient.AcpConfig{Cmd: "gemini", Args: []string{"--experimental-acp", "--model", "gemini-2.5-pro"}},
+		},
+		"gemini without model": {
+			agent:        &GeminiAgent{},
+			binary:       "gemini",
+			expectedName: "gemini",
+			expectedAcp:  &acpclient.AcpConfig{Cmd: "gemini", Args: []string{"--experimental-acp"}},
+		},
+		"goose": {
+			agent:        &GooseAgent{},
+			binary:       "goose",
+			model:        "gpt-4o",
+			expectedName: "goose-gpt-4o",
+			expectedAcp:  &acpclient.AcpConfig{Cmd: "goose", Args: []string{"acp"}, Env: map[string]string{"GOOSE_MODEL": "gpt-4o"}},
+		},
+		"goose without model": {
+			agent:        &GooseAgent{},
+			binary:       "goose",
+			expectedName: "goose",
+			expectedAcp:  &acpclient.AcpConfig{Cmd: "goose", Args: []string{"acp"}},
+		},
+		"opencode": {
+			agent:        &OpenCodeAgent{},
+			binary:       "opencode",
+			model:        "anthropic/claude-sonnet-4",
+			expectedName: "opencode-anthropic-claude-sonnet-4",
+			expectedAcp:  &acpclient.AcpConfig{Cmd: "opencode", Args: []string{"acp", "--model", "anthropic/claude-sonnet-4"}},
+		},
+		"opencode without model": {
+			agent:        &OpenCodeAgent{},
+			binary:       "opencode",
+			expectedName: "opencode",
+			expectedAcp:  &acpclient.AcpConfig{Cmd: "opencode", Args: []string{"acp"}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.NotEmpty(t, tc.agent.Description())
+			assert.False(t, tc.agent.RequiresModel())
+
+			t.Setenv("PATH", t.TempDir())
+			err := tc.agent.ValidateEnvironment()
+			assert.ErrorContains(t, err, tc.binary)
+
+			fakeBinary(t, tc.binary, `if [ "$1" = "--version" ]; then echo "1.2.3"; echo "update available"; fi`)
+			require.NoError(t, tc.agent.ValidateEnvironment())
+
+			spec, err := tc.agent.GetDefaults(tc.model)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedName, spec.Metadata.Name)
+			assert.Equal(t, tc.expectedAcp, spec.AcpConfig)
+
+			version, err := spec.ResolveVersion(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, "1.2.3", version)
+		})
+	}
+}
+
+func TestCodexAgentRunTask(t *testing.T) {
+	fakeBinary(t, "codex", `echo "HOME=$HOME"; for arg in "$@"; do echo "ARG=$arg"; done`)
+	t.Setenv("CODEX_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+
+	spec, err := (&CodexAgent{}).GetDefaults("gpt-5-codex")
+	require.NoError(t, err)
+	spec.Commands.RunPrompt += ` && echo "KEY=$CODEX_API_KEY"`
+
+	runner, err := NewRunnerForSpec(spec)
+	require.NoError(t, err)
+	runner = runner.WithMcpServerInfo(&mockServerManager{
+		servers: []mcpproxy.Server{&mockServer{name: "kubernetes", config: &mcpclient.ServerConfig{URL: "http://localhost:8080/mcp"}}},
+		files:   []string{"/tmp/kubernetes.json"},
+	})
+
+	prompt := `List the pods in "default" and don't use $HOME`
+	result, err := runner.RunTask(context.Background(), prompt)
+	require.NoError(t, err)
+
+	output := result.GetOutput()
+	require.Len(t, output, 1)
+	lines := strings.Split(strings.TrimSpace(output[0].Content), "\n")
+
+	home, _ := os.UserHomeDir()
+	require.NotEmpty(t, lines)
+	assert.NotEqual(t, "HOME="+home, lines[0], "codex should run in a virtual home")
+	assert.True(t, strings.HasSuffix(lines[0], "/home"), lines[0])
+	assert.Contains(t, lines, `ARG=mcp_servers.kubernetes.url="http://localhost:8080/mcp"`)
+	assert.Contains(t, lines, "ARG=gpt-5-codex")
+	assert.Contains(t, lines, "ARG="+prompt, "the prompt should be passed as a single argument")
+	assert.Contains(t, lines, "KEY=sk-test")
+}
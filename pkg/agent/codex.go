@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// codexRunPrompt runs codex non-interactively. codex cannot use the login of the
+// user's home inside the virtual home, so OPENAI_API_KEY is passed on as CODEX_API_KEY.
+const codexRunPrompt = `if [ -z "${CODEX_API_KEY:-}" ] && [ -n "${OPENAI_API_KEY:-}" ]; then
+  export CODEX_API_KEY="${OPENAI_API_KEY}"
+fi
+codex exec --json --skip-git-repo-check --sandbox workspace-write -c features.rmcp_client=true {{ .McpServerFileArgs }}%s {{ shellQuote .Prompt }}`
+
+type CodexAgent struct{}
+
+func (a *CodexAgent) Name() string {
+	return "codex"
+}
+
+func (a *CodexAgent) Description() string {
+	return "OpenAI's Codex CLI"
+}
+
+func (a *CodexAgent) RequiresModel() bool {
+	return false // Codex uses its configured model unless one is given
+}
+
+func (a *CodexAgent) ValidateEnvironment() error {
+	if _, err := exec.LookPath("codex"); err != nil {
+		return fmt.Errorf("'codex' binary not found in PATH (install with: npm install -g @openai/codex): %w", err)
+	}
+	return nil
+}
+
+func (a *CodexAgent) GetDefaults(model string) (*AgentSpec, error) {
+	name := "codex"
+	modelArgs := ""
+	if model != "" {
+		name = fmt.Sprintf("codex-%s", model)
+		modelArgs = " --model " + shellQuote(model)
+	}
+
+	useVirtualHome := true
+	getVersion := "codex --version"
+
+	return &AgentSpec{
+		Metadata: AgentMetadata{
+			Name: name,
+		},
+		Commands: AgentCommands{
+			UseVirtualHome:       &useVirtualHome,
+			ArgTemplateMcpServer: `-c {{ shellQuote (printf "mcp_servers.%s.url=%q" .Name .URL) }}`,
+			RunPrompt:            strings.TrimSpace(fmt.Sprintf(codexRunPrompt, modelArgs)),
+			OutputFormat:         OutputFormatCodexJSON,
+			GetVersion:           &getVersion,
+		},
+	}, nil
+}
@@ -1,8 +1,11 @@
 package agent
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
 	"github.com/mcpchecker/mcpchecker/pkg/util"
@@ -19,6 +22,12 @@ type AgentSpec struct {
 	Builtin       *BuiltinRef          `json:"builtin,omitempty"`
 	AcpConfig     *acpclient.AcpConfig `json:"acp,omitempty"` // if builtin and acp are both set, default to acp
 	Commands      AgentCommands        `json:"commands"`
+
+	// Sandbox optionally isolates shell agents from the host environment
+	Sandbox *SandboxConfig `json:"sandbox,omitempty"`
+
+	// Session optionally sets the mode and config options of ACP sessions
+	Session *acpclient.SessionConfig `json:"session,omitempty"`
 }
 
 // AgentRef specifies how to configure the agent
@@ -27,14 +36,19 @@ type AgentRef struct {
 	// Type specifies the agent type:
 	// - "builtin.claude-code" for Claude Code
 	// - "builtin.llm-agent" for LLM agents (supports openai, anthropic, gemini, etc.)
+	// - "builtin.codex", "builtin.gemini", "builtin.goose" or "builtin.opencode" for those CLIs
 	// - "file" for custom agent configuration files
 	Type string `json:"type"`
 
 	// Path to agent configuration file (required when type is "file")
 	Path string `json:"path,omitempty"`
 
-	// Model in "provider:model-id" format (required for builtin.llm-agent)
+	// Model in "provider:model-id" format (required for builtin.llm-agent).
+	// builtin.codex and builtin.gemini take an optional model name of their own provider.
 	Model string `json:"model,omitempty"`
+
+	// Session overrides the ACP session mode and config options of the agent
+	Session *acpclient.SessionConfig `json:"session,omitempty"`
 }
 
 // BuiltinRef references a built-in agent type with optional model
@@ -62,10 +76,12 @@ type AgentMetadata struct {
 }
 
 type AgentCommands struct {
-	// Whether or not to create a virtual $HOME for executing the agent without existing config
+	// Whether or not to create a virtual $HOME for executing the agent without existing config,
+	// defaults to true. Sandboxed agents always get a virtual $HOME.
 	UseVirtualHome *bool `json:"useVirtualHome,omitempty"`
 
 	// A template for how the mcp servers config files should be provided to the prompt
+	// the server name will be in {{ .Name }}
 	// the server file will be in {{ .File }}
 	// the server URL will be in {{ .URL }}
 	ArgTemplateMcpServer string `json:"argTemplateMcpServer"`
@@ -85,11 +101,40 @@ type AgentCommands struct {
 	// the allowed tools will be in {{ .AllowedToolArgs }}
 	RunPrompt string `json:"runPrompt"`
 
-	// An optional command to get the version of the agent
+	// The format of the output of RunPrompt, parsed into output steps, tool calls and token usage.
+	// One of "text" (default), "claude-stream-json" or "codex-json"
+	OutputFormat string `json:"outputFormat,omitempty"`
+
+	// An optional command to get the version of the agent, reported in the eval summary
 	// useful for generic agents such as claude code that may autoupdate/have different versions on different machines
 	GetVersion *string `json:"getVersion,omitempty"`
 }
 
+// ResolveVersion returns the version of the agent from Commands.GetVersion, or from
+// Metadata.Version if no command is set. It returns "" if neither is set.
+func (s *AgentSpec) ResolveVersion(ctx context.Context) (string, error) {
+	if s.Commands.GetVersion == nil || *s.Commands.GetVersion == "" {
+		if s.Metadata.Version != nil {
+			return *s.Metadata.Version, nil
+		}
+		return "", nil
+	}
+
+	shell, ok := os.LookupEnv("SHELL")
+	if !ok {
+		shell = "/usr/bin/bash"
+	}
+
+	out, err := exec.CommandContext(ctx, shell, "-c", *s.Commands.GetVersion).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run getVersion command %q: %w", *s.Commands.GetVersion, err)
+	}
+
+	// Only the first line, some agents print update notices after the version
+	version, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+	return strings.TrimSpace(version), nil
+}
+
 func Read(data []byte) (*AgentSpec, error) {
 	spec := &AgentSpec{}
 
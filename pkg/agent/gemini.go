@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
+)
+
+type GeminiAgent struct{}
+
+func (a *GeminiAgent) Name() string {
+	return "gemini"
+}
+
+func (a *GeminiAgent) Description() string {
+	return "Google's Gemini CLI"
+}
+
+func (a *GeminiAgent) RequiresModel() bool {
+	return false // Gemini CLI uses its default model unless one is given
+}
+
+func (a *GeminiAgent) ValidateEnvironment() error {
+	if _, err := exec.LookPath("gemini"); err != nil {
+		return fmt.Errorf("'gemini' binary not found in PATH (install with: npm install -g @google/gemini-cli): %w", err)
+	}
+	return nil
+}
+
+func (a *GeminiAgent) GetDefaults(model string) (*AgentSpec, error) {
+	name := "gemini"
+	args := []string{"--experimental-acp"}
+	if model != "" {
+		name = fmt.Sprintf("gemini-%s", model)
+		args = append(args, "--model", model)
+	}
+
+	getVersion := "gemini --version"
+
+	return &AgentSpec{
+		Metadata: AgentMetadata{
+			Name: name,
+		},
+		AcpConfig: &acpclient.AcpConfig{
+			Cmd:  "gemini",
+			Args: args,
+		},
+		Commands: AgentCommands{
+			GetVersion: &getVersion,
+		},
+	}, nil
+}
@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
+)
+
+type GooseAgent struct{}
+
+func (a *GooseAgent) Name() string {
+	return "goose"
+}
+
+func (a *GooseAgent) Description() string {
+	return "Block's Goose CLI"
+}
+
+func (a *GooseAgent) RequiresModel() bool {
+	return false // Goose reads its provider and model from GOOSE_PROVIDER and GOOSE_MODEL unless one is given
+}
+
+func (a *GooseAgent) ValidateEnvironment() error {
+	if _, err := exec.LookPath("goose"); err != nil {
+		return fmt.Errorf("'goose' binary not found in PATH (install from: https://block.github.io/goose/docs/getting-started/installation): %w", err)
+	}
+	return nil
+}
+
+func (a *GooseAgent) GetDefaults(model string) (*AgentSpec, error) {
+	name := "goose"
+	var env map[string]string
+	if model != "" {
+		name = fmt.Sprintf("goose-%s", model)
+		env = map[string]string{"GOOSE_MODEL": model}
+	}
+
+	getVersion := "goose --version"
+
+	return &AgentSpec{
+		Metadata: AgentMetadata{
+			Name: name,
+		},
+		AcpConfig: &acpclient.AcpConfig{
+			Cmd:  "goose",
+			Args: []string{"acp"},
+			Env:  env,
+		},
+		Commands: AgentCommands{
+			GetVersion: &getVersion,
+		},
+	}, nil
+}
@@ -15,6 +15,7 @@ import (
 
 type llmACPRunner struct {
 	model      string
+	session    *acpclient.SessionConfig
 	mcpServers mcpproxy.ServerManager
 }
 
@@ -22,13 +23,15 @@ var _ Runner = &llmACPRunner{}
 
 // NewLLMACPRunner creates a runner that uses the llmagent package with ACP protocol.
 // The model string is in "provider:model-id" format (e.g. "openai:gpt-4o").
-func NewLLMACPRunner(model string) (Runner, error) {
+// session optionally sets the mode of the ACP session, e.g. plan.
+func NewLLMACPRunner(model string, session *acpclient.SessionConfig) (Runner, error) {
 	if model == "" {
 		return nil, fmt.Errorf("model is required for llm-agent")
 	}
 
 	return &llmACPRunner{
-		model: model,
+		model:   model,
+		session: session,
 	}, nil
 }
 
@@ -39,6 +42,7 @@ func (r *llmACPRunner) AgentName() string {
 func (r *llmACPRunner) WithMcpServerInfo(mcpServers mcpproxy.ServerManager) Runner {
 	return &llmACPRunner{
 		model:      r.model,
+		session:    r.session,
 		mcpServers: mcpServers,
 	}
 }
@@ -53,6 +57,7 @@ func (r *llmACPRunner) RunTask(ctx context.Context, prompt string) (AgentResult,
 
 	client := acpclient.NewClient(ctx, &acpclient.AcpConfig{
 		Transport: transport,
+		Session:   r.session,
 	})
 
 	if err := client.Start(ctx); err != nil {
@@ -66,9 +71,13 @@ func (r *llmACPRunner) RunTask(ctx context.Context, prompt string) (AgentResult,
 	}
 
 	return &acpResult{
-		updates:     result.Updates,
-		prompt:      prompt,
-		actualUsage: result.Usage,
+		updates:            result.Updates,
+		prompt:             prompt,
+		actualUsage:        result.Usage,
+		fileOperations:     result.FileOperations,
+		terminalCommands:   result.TerminalCommands,
+		permissionRequests: result.PermissionRequests,
+		modeChanges:        result.ModeChanges,
 	}, nil
 }
 
@@ -83,6 +83,7 @@ func mergeAgentSpecs(defaults, overrides *AgentSpec) *AgentSpec {
 		overrides.Commands.RunPrompt != "" ||
 		overrides.Commands.AllowedToolsJoinSeparator != nil ||
 		overrides.Commands.GetVersion != nil ||
+		overrides.Commands.OutputFormat != "" ||
 		overrides.Commands.UseVirtualHome != nil
 
 	if commandsSpecified {
@@ -102,11 +103,22 @@ func mergeAgentSpecs(defaults, overrides *AgentSpec) *AgentSpec {
 		if overrides.Commands.GetVersion != nil {
 			result.Commands.GetVersion = overrides.Commands.GetVersion
 		}
+		if overrides.Commands.OutputFormat != "" {
+			result.Commands.OutputFormat = overrides.Commands.OutputFormat
+		}
 		// Only override UseVirtualHome when explicitly set in overrides
 		if overrides.Commands.UseVirtualHome != nil {
 			result.Commands.UseVirtualHome = overrides.Commands.UseVirtualHome
 		}
 	}
 
+	if overrides.Sandbox != nil {
+		result.Sandbox = overrides.Sandbox
+	}
+
+	if overrides.Session != nil {
+		result.Session = overrides.Session
+	}
+
 	return &result
 }
@@ -151,6 +151,41 @@ func TestNewRunnerForSpec(t *testing.T) {
 				assert.Equal(t, "acp-priority", runner.AgentName())
 			},
 		},
+		"acp config gets session config": {
+			spec: &AgentSpec{
+				Metadata:  AgentMetadata{Name: "acp-plan"},
+				AcpConfig: &acpclient.AcpConfig{Cmd: "acp-cmd"},
+				Session:   &acpclient.SessionConfig{Mode: "plan"},
+			},
+			validate: func(t *testing.T, runner Runner) {
+				r, ok := runner.(*acpRunner)
+				require.True(t, ok, "expected runner to be *acpRunner")
+				require.NotNil(t, r.cfg.Session)
+				assert.Equal(t, "plan", r.cfg.Session.Mode)
+			},
+		},
+		"llm agent gets session config": {
+			spec: &AgentSpec{
+				Metadata: AgentMetadata{Name: "llm-plan"},
+				Builtin:  &BuiltinRef{Type: "llm-agent", Model: "openai:gpt-4o"},
+				Session:  &acpclient.SessionConfig{Mode: "plan"},
+			},
+			validate: func(t *testing.T, runner Runner) {
+				r, ok := runner.(*llmACPRunner)
+				require.True(t, ok, "expected runner to be *llmACPRunner")
+				require.NotNil(t, r.session)
+				assert.Equal(t, "plan", r.session.Mode)
+			},
+		},
+		"session config for shell agent returns error": {
+			spec: &AgentSpec{
+				Metadata: AgentMetadata{Name: "shell-agent"},
+				Commands: AgentCommands{RunPrompt: "echo hello"},
+				Session:  &acpclient.SessionConfig{Mode: "plan"},
+			},
+			expectErr:   true,
+			errContains: "only supported for ACP agents",
+		},
 		"spec without acp or builtin returns agentSpecRunner": {
 			spec: &AgentSpec{
 				Metadata: AgentMetadata{Name: "shell-agent"},
@@ -245,4 +280,19 @@ func TestMergeAgentSpecs(t *testing.T) {
 		assert.Equal(t, "{{ .File }}", result.Commands.ArgTemplateMcpServer)
 		assert.Equal(t, "base command", result.Commands.RunPrompt)
 	})
+
+	t.Run("override session", func(t *testing.T) {
+		base := &AgentSpec{
+			Metadata:  AgentMetadata{Name: "base"},
+			AcpConfig: &acpclient.AcpConfig{Cmd: "acp-cmd"},
+		}
+		override := &AgentSpec{
+			Session: &acpclient.SessionConfig{Mode: "plan"},
+		}
+		result := mergeAgentSpecs(base, override)
+
+		require.NotNil(t, result.Session)
+		assert.Equal(t, "plan", result.Session.Mode)
+		assert.Equal(t, "acp-cmd", result.AcpConfig.Cmd)
+	})
 }
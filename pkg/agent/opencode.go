@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
+)
+
+type OpenCodeAgent struct{}
+
+func (a *OpenCodeAgent) Name() string {
+	return "opencode"
+}
+
+func (a *OpenCodeAgent) Description() string {
+	return "SST's OpenCode CLI"
+}
+
+func (a *OpenCodeAgent) RequiresModel() bool {
+	return false // OpenCode uses the model from its own config unless one is given
+}
+
+func (a *OpenCodeAgent) ValidateEnvironment() error {
+	if _, err := exec.LookPath("opencode"); err != nil {
+		return fmt.Errorf("'opencode' binary not found in PATH (install with: npm install -g opencode-ai): %w", err)
+	}
+	return nil
+}
+
+func (a *OpenCodeAgent) GetDefaults(model string) (*AgentSpec, error) {
+	name := "opencode"
+	args := []string{"acp"}
+	if model != "" {
+		// OpenCode models are "provider/model", keep the name usable in file names
+		name = fmt.Sprintf("opencode-%s", strings.ReplaceAll(model, "/", "-"))
+		args = append(args, "--model", model)
+	}
+
+	getVersion := "opencode --version"
+
+	return &AgentSpec{
+		Metadata: AgentMetadata{
+			Name: name,
+		},
+		AcpConfig: &acpclient.AcpConfig{
+			Cmd:  "opencode",
+			Args: args,
+		},
+		Commands: AgentCommands{
+			GetVersion: &getVersion,
+		},
+	}, nil
+}
@@ -0,0 +1,378 @@
+package agent
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mcpchecker/mcpchecker/pkg/tokens"
+)
+
+const (
+	// OutputFormatText keeps the output of a shell agent as a single message (default)
+	OutputFormatText = "text"
+	// OutputFormatClaudeStreamJSON parses the output of `claude --output-format stream-json --verbose`
+	OutputFormatClaudeStreamJSON = "claude-stream-json"
+	// OutputFormatCodexJSON parses the output of `codex exec --json`
+	OutputFormatCodexJSON = "codex-json"
+)
+
+// OutputParser converts the output of a shell agent into structured results
+type OutputParser interface {
+	Parse(output string) (*ParsedOutput, error)
+}
+
+// ParsedOutput is the structured result of a shell agent run
+type ParsedOutput struct {
+	Steps []OutputStep
+	// Usage is the token usage reported by the agent, nil if it reported none
+	Usage *tokens.Usage
+}
+
+var outputParsers = map[string]OutputParser{
+	OutputFormatClaudeStreamJSON: &claudeStreamJSONParser{},
+	OutputFormatCodexJSON:        &codexJSONParser{},
+}
+
+// GetOutputParser retrieves the output parser for an output format
+func GetOutputParser(format string) (OutputParser, bool) {
+	parser, ok := outputParsers[format]
+	return parser, ok
+}
+
+// RegisterOutputParser adds an output parser for a new output format.
+// It is not safe to call concurrently with running agents.
+func RegisterOutputParser(format string, parser OutputParser) {
+	outputParsers[format] = parser
+}
+
+// jsonLines calls fn for every line of output that is a JSON object. Other lines,
+// such as log messages written to stderr, are skipped.
+func jsonLines(output string, fn func(line []byte) error) error {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		if err := fn([]byte(line)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// stepsBuilder appends output steps, consolidating consecutive thinking and message text
+type stepsBuilder struct {
+	steps     []OutputStep
+	toolCalls map[string]*ToolCallSummary
+}
+
+func newStepsBuilder() *stepsBuilder {
+	return &stepsBuilder{toolCalls: make(map[string]*ToolCallSummary)}
+}
+
+func (b *stepsBuilder) text(stepType, text string) {
+	if text == "" {
+		return
+	}
+	if n := len(b.steps); n > 0 && b.steps[n-1].Type == stepType {
+		b.steps[n-1].Content += text
+		return
+	}
+	b.steps = append(b.steps, OutputStep{Type: stepType, Content: text})
+}
+
+func (b *stepsBuilder) toolCall(id string, tc *ToolCallSummary) {
+	if id != "" {
+		b.toolCalls[id] = tc
+	}
+	b.steps = append(b.steps, OutputStep{Type: "tool_call", ToolCall: tc})
+}
+
+// claudeStreamJSONParser parses the stream-json output of the Claude Code CLI
+type claudeStreamJSONParser struct{}
+
+type claudeStreamEvent struct {
+	Type    string `json:"type"`
+	Message *struct {
+		Content []claudeContentBlock `json:"content"`
+	} `json:"message,omitempty"`
+	Result string       `json:"result,omitempty"`
+	Usage  *claudeUsage `json:"usage,omitempty"`
+}
+
+type claudeContentBlock struct {
+	Type      string `json:"type"`
+	Text      string `json:"text,omitempty"`
+	Thinking  string `json:"thinking,omitempty"`
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Input     any    `json:"input,omitempty"`
+	ToolUseID string `json:"tool_use_id,omitempty"`
+	Content   any    `json:"content,omitempty"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+type claudeUsage struct {
+	InputTokens              int64 `json:"input_tokens"`
+	OutputTokens             int64 `json:"output_tokens"`
+	CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+	CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+}
+
+func (p *claudeStreamJSONParser) Parse(output string) (*ParsedOutput, error) {
+	b := newStepsBuilder()
+	var usage *tokens.Usage
+	var result string
+	events := 0
+
+	err := jsonLines(output, func(line []byte) error {
+		var evt claudeStreamEvent
+		if err := json.Unmarshal(line, &evt); err != nil {
+			return nil
+		}
+
+		switch evt.Type {
+		case "assistant":
+			events++
+			if evt.Message == nil {
+				return nil
+			}
+			for _, block := range evt.Message.Content {
+				switch block.Type {
+				case "text":
+					b.text("message", block.Text)
+				case "thinking":
+					b.text("thinking", block.Thinking)
+				case "tool_use":
+					b.toolCall(block.ID, &ToolCallSummary{
+						Title:    block.Name,
+						Status:   "pending",
+						RawInput: block.Input,
+					})
+				}
+			}
+		case "user":
+			events++
+			if evt.Message == nil {
+				return nil
+			}
+			for _, block := range evt.Message.Content {
+				tc, ok := b.toolCalls[block.ToolUseID]
+				if block.Type != "tool_result" || !ok {
+					continue
+				}
+				tc.RawOutput = block.Content
+				tc.Status = "completed"
+				if block.IsError {
+					tc.Status = "failed"
+				}
+			}
+		case "result":
+			events++
+			result = evt.Result
+			if evt.Usage != nil {
+				// Anthropic reports cached input separately from the other input tokens
+				cacheWrite := evt.Usage.CacheCreationInputTokens
+				cacheRead := evt.Usage.CacheReadInputTokens
+				input := evt.Usage.InputTokens + cacheWrite + cacheRead
+				usage = &tokens.Usage{
+					InputTokens:       input,
+					OutputTokens:      evt.Usage.OutputTokens,
+					TotalTokens:       input + evt.Usage.OutputTokens,
+					CachedReadTokens:  &cacheRead,
+					CachedWriteTokens: &cacheWrite,
+				}
+			}
+		case "system":
+			events++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if events == 0 {
+		return nil, fmt.Errorf("no %s events found in output", OutputFormatClaudeStreamJSON)
+	}
+
+	if FinalMessageFromSteps(b.steps) == "" {
+		b.text("message", result)
+	}
+
+	return &ParsedOutput{Steps: b.steps, Usage: usage}, nil
+}
+
+// codexJSONParser parses the JSONL output of `codex exec --json`
+type codexJSONParser struct{}
+
+type codexEvent struct {
+	Type  string      `json:"type"`
+	Item  *codexItem  `json:"item,omitempty"`
+	Usage *codexUsage `json:"usage,omitempty"`
+}
+
+type codexItem struct {
+	ID               string `json:"id"`
+	Type             string `json:"type"`
+	Text             string `json:"text,omitempty"`
+	Command          string `json:"command,omitempty"`
+	AggregatedOutput string `json:"aggregated_output,omitempty"`
+	Status           string `json:"status,omitempty"`
+	Server           string `json:"server,omitempty"`
+	Tool             string `json:"tool,omitempty"`
+	Arguments        any    `json:"arguments,omitempty"`
+	Result           any    `json:"result,omitempty"`
+	Error            any    `json:"error,omitempty"`
+}
+
+type codexUsage struct {
+	InputTokens           int64 `json:"input_tokens"`
+	CachedInputTokens     int64 `json:"cached_input_tokens"`
+	OutputTokens          int64 `json:"output_tokens"`
+	ReasoningOutputTokens int64 `json:"reasoning_output_tokens"`
+}
+
+func (p *codexJSONParser) Parse(output string) (*ParsedOutput, error) {
+	b := newStepsBuilder()
+	var usage *tokens.Usage
+	events := 0
+
+	err := jsonLines(output, func(line []byte) error {
+		var evt codexEvent
+		if err := json.Unmarshal(line, &evt); err != nil {
+			return nil
+		}
+
+		switch evt.Type {
+		case "thread.started", "turn.started", "item.started", "item.updated":
+			events++
+		case "turn.completed":
+			events++
+			if evt.Usage == nil {
+				return nil
+			}
+			if usage == nil {
+				usage = &tokens.Usage{}
+			}
+			// OpenAI input tokens include the cached ones
+			cached := evt.Usage.CachedInputTokens
+			reasoning := evt.Usage.ReasoningOutputTokens
+			usage.Add(&tokens.Usage{
+				InputTokens:      evt.Usage.InputTokens,
+				OutputTokens:     evt.Usage.OutputTokens,
+				TotalTokens:      evt.Usage.InputTokens + evt.Usage.OutputTokens,
+				CachedReadTokens: &cached,
+				ThoughtTokens:    &reasoning,
+			})
+		case "item.completed":
+			events++
+			if evt.Item == nil {
+				return nil
+			}
+			item := evt.Item
+			switch item.Type {
+			case "reasoning":
+				b.text("thinking", item.Text)
+			case "agent_message":
+				b.text("message", item.Text)
+			case "mcp_tool_call":
+				output := item.Result
+				if item.Error != nil {
+					output = item.Error
+				}
+				b.toolCall(item.ID, &ToolCallSummary{
+					Title:     fmt.Sprintf("%s.%s", item.Server, item.Tool),
+					Status:    codexStatus(item.Status),
+					RawInput:  item.Arguments,
+					RawOutput: output,
+				})
+			case "command_execution":
+				b.toolCall(item.ID, &ToolCallSummary{
+					Title:     item.Command,
+					Kind:      "execute",
+					Status:    codexStatus(item.Status),
+					RawInput:  map[string]any{"command": item.Command},
+					RawOutput: item.AggregatedOutput,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if events == 0 {
+		return nil, fmt.Errorf("no %s events found in output", OutputFormatCodexJSON)
+	}
+
+	return &ParsedOutput{Steps: b.steps, Usage: usage}, nil
+}
+
+// codexStatus maps the status of a codex item to an ACP tool call status
+func codexStatus(status string) string {
+	switch status {
+	case "completed", "failed":
+		return status
+	case "declined":
+		return "failed"
+	default:
+		return "in_progress"
+	}
+}
+
+// toolCallsFromSteps returns the tool calls of the output steps in order
+func toolCallsFromSteps(steps []OutputStep) []ToolCallSummary {
+	var toolCalls []ToolCallSummary
+	for _, step := range steps {
+		if step.Type == "tool_call" && step.ToolCall != nil {
+			toolCalls = append(toolCalls, *step.ToolCall)
+		}
+	}
+	return toolCalls
+}
+
+// thinkingFromSteps joins the content of all thinking steps
+func thinkingFromSteps(steps []OutputStep) string {
+	var thinking strings.Builder
+	for _, step := range steps {
+		if step.Type == "thinking" {
+			thinking.WriteString(step.Content)
+		}
+	}
+	return thinking.String()
+}
+
+// turnsFromSteps identifies LLM turns from output steps, the same way ExtractTurns
+// does from session updates. Tool calls in parsed output always carry their results.
+func turnsFromSteps(steps []OutputStep) []tokens.TurnTokens {
+	tb := newTurnBuilder()
+
+	for _, step := range steps {
+		switch step.Type {
+		case "thinking", "message":
+			if tb.seenResults {
+				tb.flush()
+			}
+			tb.started = true
+			if step.Type == "thinking" {
+				tb.thinking.WriteString(step.Content)
+			} else {
+				tb.message.WriteString(step.Content)
+			}
+		case "tool_call":
+			tb.started = true
+			tb.numToolCalls++
+			tb.seenResults = true
+		}
+	}
+
+	if tb.started {
+		tb.flush()
+	}
+
+	return tb.turns
+}
@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mcpchecker/mcpchecker/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const claudeStreamJSON = `{"type":"system","subtype":"init","session_id":"abc","tools":["mcp__kubernetes__pods_list"]}
+{"type":"assistant","message":{"content":[{"type":"thinking","thinking":"I should list the pods."},{"type":"tool_use","id":"toolu_1","name":"mcp__kubernetes__pods_list","input":{"namespace":"default"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"nginx"}]}]}}
+{"type":"assistant","message":{"content":[{"type":"text","text":"There is one pod: "}]}}
+{"type":"assistant","message":{"content":[{"type":"text","text":"nginx."}]}}
+{"type":"result","subtype":"success","result":"There is one pod: nginx.","usage":{"input_tokens":100,"output_tokens":20,"cache_creation_input_tokens":10,"cache_read_input_tokens":5}}
+`
+
+const codexJSON = `Reading prompt from stdin...
+{"type":"thread.started","thread_id":"t1"}
+{"type":"turn.started"}
+{"type":"item.completed","item":{"id":"item_0","type":"reasoning","text":"Listing pods"}}
+{"type":"item.started","item":{"id":"item_1","type":"mcp_tool_call","server":"kubernetes","tool":"pods_list","arguments":{"namespace":"default"},"status":"in_progress"}}
+{"type":"item.completed","item":{"id":"item_1","type":"mcp_tool_call","server":"kubernetes","tool":"pods_list","arguments":{"namespace":"default"},"result":{"content":[{"type":"text","text":"nginx"}]},"status":"completed"}}
+{"type":"item.completed","item":{"id":"item_2","type":"command_execution","command":"bash -lc 'kubectl get pods'","aggregated_output":"denied","exit_code":1,"status":"failed"}}
+{"type":"item.completed","item":{"id":"item_3","type":"agent_message","text":"There is one pod: nginx."}}
+{"type":"turn.completed","usage":{"input_tokens":200,"cached_input_tokens":50,"output_tokens":30,"reasoning_output_tokens":12}}
+`
+
+func TestOutputParsers(t *testing.T) {
+	int64Ptr := func(v int64) *int64 { return &v }
+
+	tests := map[string]struct {
+		format        string
+		output        string
+		expectedSteps []OutputStep
+		expectedUsage *tokens.Usage
+		expectedErr   string
+	}{
+		"claude stream-json": {
+			format: OutputFormatClaudeStreamJSON,
+			output: claudeStreamJSON,
+			expectedSteps: []OutputStep{
+				{Type: "thinking", Content: "I should list the pods."},
+				{Type: "tool_call", ToolCall: &ToolCallSummary{
+					Title:     "mcp__kubernetes__pods_list",
+					Status:    "completed",
+					RawInput:  map[string]any{"namespace": "default"},
+					RawOutput: []any{map[string]any{"type": "text", "text": "nginx"}},
+				}},
+				{Type: "message", Content: "There is one pod: nginx."},
+			},
+			expectedUsage: &tokens.Usage{
+				InputTokens:       115,
+				OutputTokens:      20,
+				TotalTokens:       135,
+				CachedReadTokens:  int64Ptr(5),
+				CachedWriteTokens: int64Ptr(10),
+			},
+		},
+		"claude stream-json with only a result": {
+			format:        OutputFormatClaudeStreamJSON,
+			output:        `{"type":"result","subtype":"success","result":"done"}`,
+			expectedSteps: []OutputStep{{Type: "message", Content: "done"}},
+		},
+		"codex json": {
+			format: OutputFormatCodexJSON,
+			output: codexJSON,
+			expectedSteps: []OutputStep{
+				{Type: "thinking", Content: "Listing pods"},
+				{Type: "tool_call", ToolCall: &ToolCallSummary{
+					Title:     "kubernetes.pods_list",
+					Status:    "completed",
+					RawInput:  map[string]any{"namespace": "default"},
+					RawOutput: map[string]any{"content": []any{map[string]any{"type": "text", "text": "nginx"}}},
+				}},
+				{Type: "tool_call", ToolCall: &ToolCallSummary{
+					Title:     "bash -lc 'kubectl get pods'",
+					Kind:      "execute",
+					Status:    "failed",
+					RawInput:  map[string]any{"command": "bash -lc 'kubectl get pods'"},
+					RawOutput: "denied",
+				}},
+				{Type: "message", Content: "There is one pod: nginx."},
+			},
+			expectedUsage: &tokens.Usage{
+				InputTokens:      200,
+				OutputTokens:     30,
+				TotalTokens:      230,
+				CachedReadTokens: int64Ptr(50),
+				ThoughtTokens:    int64Ptr(12),
+			},
+		},
+		"plain text is not claude stream-json": {
+			format:      OutputFormatClaudeStreamJSON,
+			output:      "There is one pod: nginx.\n",
+			expectedErr: "no claude-stream-json events found",
+		},
+		"plain text is not codex json": {
+			format:      OutputFormatCodexJSON,
+			output:      "There is one pod: nginx.\n",
+			expectedErr: "no codex-json events found",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser, ok := GetOutputParser(tc.format)
+			require.True(t, ok)
+
+			parsed, err := parser.Parse(tc.output)
+			if tc.expectedErr != "" {
+				assert.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSteps, parsed.Steps)
+			assert.Equal(t, tc.expectedUsage, parsed.Usage)
+		})
+	}
+}
+
+func TestTurnsFromSteps(t *testing.T) {
+	steps := []OutputStep{
+		{Type: "thinking", Content: "a"},
+		{Type: "tool_call", ToolCall: &ToolCallSummary{}},
+		{Type: "tool_call", ToolCall: &ToolCallSummary{}},
+		{Type: "message", Content: "b"},
+	}
+
+	turns := turnsFromSteps(steps)
+	require.Len(t, turns, 2)
+	assert.Equal(t, 2, turns[0].NumToolCalls)
+	assert.Equal(t, 0, turns[1].NumToolCalls)
+}
+
+func TestShellRunnerParsesOutput(t *testing.T) {
+	fakeBinary(t, "claude", "cat <<'EOF'\n"+claudeStreamJSON+"EOF")
+
+	spec := &AgentSpec{
+		Metadata: AgentMetadata{Name: "claude-cli"},
+		Commands: AgentCommands{
+			RunPrompt:    "claude -p --output-format stream-json --verbose {{ shellQuote .Prompt }}",
+			OutputFormat: OutputFormatClaudeStreamJSON,
+		},
+	}
+
+	runner, err := NewRunnerForSpec(spec)
+	require.NoError(t, err)
+	runner = runner.WithMcpServerInfo(&mockServerManager{})
+
+	result, err := runner.RunTask(context.Background(), "list the pods")
+	require.NoError(t, err)
+
+	assert.Equal(t, "There is one pod: nginx.", FinalMessageFromSteps(result.GetOutput()))
+	require.Len(t, result.GetToolCalls(), 1)
+	assert.Equal(t, "mcp__kubernetes__pods_list", result.GetToolCalls()[0].Title)
+
+	estimate := result.GetTokenEstimate()
+	assert.Equal(t, tokens.SourceActual, estimate.Source)
+	assert.Equal(t, int64(135), estimate.TotalTokens)
+}
+
+func TestNewRunnerForSpecUnknownOutputFormat(t *testing.T) {
+	_, err := NewRunnerForSpec(&AgentSpec{Commands: AgentCommands{OutputFormat: "xml"}})
+	assert.ErrorContains(t, err, `unknown output format "xml"`)
+}
@@ -14,6 +14,19 @@ func ResolveAgentRef(ref *AgentRef) (*AgentSpec, error) {
 		return nil, fmt.Errorf("agent ref must not be nil")
 	}
 
+	spec, err := resolveAgentRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref.Session != nil {
+		spec.Session = ref.Session
+	}
+
+	return spec, nil
+}
+
+func resolveAgentRef(ref *AgentRef) (*AgentSpec, error) {
 	if ref.Type == "file" {
 		if ref.Path == "" {
 			return nil, fmt.Errorf("path must be specified when agent type is 'file'")
@@ -0,0 +1,267 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
+)
+
+const (
+	defaultRetryBackoff    = 5 * time.Second
+	defaultRetryMaxBackoff = time.Minute
+)
+
+// DefaultInfraErrorPatterns match errors of the model API or the network rather than
+// the agent: rate limits, server errors, overloaded models and broken connections
+var DefaultInfraErrorPatterns = []string{
+	`(?i)\b(status|code|http)\D{0,3}(429|5\d\d)\b`,
+	`(?i)too many requests`,
+	`(?i)rate.?limit`,
+	`(?i)overloaded`,
+	`(?i)internal server error`,
+	`(?i)bad gateway`,
+	`(?i)service unavailable`,
+	`(?i)gateway timeout`,
+	`(?i)connection (refused|reset)`,
+	`(?i)i/o timeout`,
+	`(?i)tls handshake timeout`,
+	`(?i)no such host`,
+	`(?i)unexpected EOF`,
+}
+
+// RetryPolicy retries agent runs that fail with an infra error, e.g. when the model API
+// is rate limited or unavailable. The agent runs again in the same task, setup is not
+// repeated and resets added with WithRetryReset run before every retry.
+type RetryPolicy struct {
+	// Attempts is the maximum number of runs of the agent, including the first
+	Attempts int `json:"attempts"`
+	// Backoff is the delay before the first retry, doubled after every retry (default 5s)
+	Backoff string `json:"backoff,omitempty"`
+	// MaxBackoff caps the delay between retries (default 1m)
+	MaxBackoff string `json:"maxBackoff,omitempty"`
+	// Errors are regexes matched against the error of a failed run, runs failing with a
+	// matching error are infra errors and are retried. The output of shell agents is not
+	// matched. Defaults to DefaultInfraErrorPatterns.
+	Errors []string `json:"errors,omitempty"`
+}
+
+func (p *RetryPolicy) Validate() error {
+	if p.Attempts < 0 {
+		return fmt.Errorf("attempts must be >= 0, got %d", p.Attempts)
+	}
+	if _, err := parseBackoff(p.Backoff, defaultRetryBackoff); err != nil {
+		return fmt.Errorf("invalid backoff: %w", err)
+	}
+	if _, err := parseBackoff(p.MaxBackoff, defaultRetryMaxBackoff); err != nil {
+		return fmt.Errorf("invalid maxBackoff: %w", err)
+	}
+	for i, pattern := range p.Errors {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid errors[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// IsInfraError reports whether err matches the error patterns of the policy. A nil
+// policy uses DefaultInfraErrorPatterns. For a *CommandError only the error of running
+// the command is matched, what the agent printed is not an infra error.
+func (p *RetryPolicy) IsInfraError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var infraErr *InfraError
+	if errors.As(err, &infraErr) {
+		return true
+	}
+
+	patterns := DefaultInfraErrorPatterns
+	if p != nil && len(p.Errors) > 0 {
+		patterns = p.Errors
+	}
+
+	msg := err.Error()
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		msg = cmdErr.Err.Error()
+	}
+
+	for _, pattern := range patterns {
+		if matched, _ := regexp.MatchString(pattern, msg); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delay returns how long to wait before the retry that follows attempt, starting at 1
+func (p *RetryPolicy) Delay(attempt int) time.Duration {
+	var backoff, maxBackoff time.Duration = defaultRetryBackoff, defaultRetryMaxBackoff
+	if p != nil {
+		backoff, _ = parseBackoff(p.Backoff, defaultRetryBackoff)
+		maxBackoff, _ = parseBackoff(p.MaxBackoff, defaultRetryMaxBackoff)
+	}
+
+	delay := backoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxBackoff)
+}
+
+func (p *RetryPolicy) attempts() int {
+	if p == nil || p.Attempts < 1 {
+		return 1
+	}
+	return p.Attempts
+}
+
+func parseBackoff(s string, def time.Duration) (time.Duration, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration must be >= 0, got %q", s)
+	}
+
+	return d, nil
+}
+
+// InfraError is the error of an agent run that failed because of the infrastructure
+// it runs on, rather than because of the agent
+type InfraError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *InfraError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("%v (after %d attempts)", e.Err, e.Attempts)
+	}
+	return e.Err.Error()
+}
+
+func (e *InfraError) Unwrap() error {
+	return e.Err
+}
+
+// CommandError is the error of a shell agent whose command failed. The output of the
+// agent is kept apart from the error of running the command, so that it is not taken
+// for an infra error.
+type CommandError struct {
+	Shell  string
+	Script string
+	Err    error
+	Output []byte
+	// Details are appended to the message, e.g. where debug artifacts were preserved
+	Details string
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("failed to run command: %s -c %q: %v.\n\noutput: %s%s", e.Shell, e.Script, e.Err, e.Output, e.Details)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// RetryFunc is called before an agent run is retried
+type RetryFunc func(attempt int, delay time.Duration, err error)
+
+type retryResetKey struct{}
+
+// WithRetryReset adds reset to the functions that run before an agent run is retried,
+// so that state the failed attempt left behind, such as files in the workspace of the
+// agent, does not carry over to the next attempt
+func WithRetryReset(ctx context.Context, reset func() error) context.Context {
+	resets, _ := ctx.Value(retryResetKey{}).([]func() error)
+	return context.WithValue(ctx, retryResetKey{}, append(slices.Clone(resets), reset))
+}
+
+// resetForRetry runs the reset functions of ctx in the order they were added
+func resetForRetry(ctx context.Context) error {
+	resets, _ := ctx.Value(retryResetKey{}).([]func() error)
+	for _, reset := range resets {
+		if err := reset(); err != nil {
+			return fmt.Errorf("failed to reset task for retry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+type retryRunner struct {
+	runner  Runner
+	policy  *RetryPolicy
+	onRetry RetryFunc
+}
+
+var _ Runner = &retryRunner{}
+
+// NewRetryRunner returns a runner that retries runs of runner failing with an infra
+// error according to policy, and returns infra errors as *InfraError. A nil policy
+// does not retry, but still classifies infra errors.
+func NewRetryRunner(runner Runner, policy *RetryPolicy, onRetry RetryFunc) Runner {
+	return &retryRunner{
+		runner:  runner,
+		policy:  policy,
+		onRetry: onRetry,
+	}
+}
+
+func (r *retryRunner) RunTask(ctx context.Context, prompt string) (AgentResult, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := r.runner.RunTask(ctx, prompt)
+		// errors after the task timed out or was cancelled are not infra errors
+		if err == nil || ctx.Err() != nil || !r.policy.IsInfraError(err) {
+			return result, err
+		}
+
+		if attempt >= r.policy.attempts() {
+			return result, &InfraError{Attempts: attempt, Err: err}
+		}
+
+		delay := r.policy.Delay(attempt)
+		if r.onRetry != nil {
+			r.onRetry(attempt, delay, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, &InfraError{Attempts: attempt, Err: err}
+		case <-timer.C:
+		}
+
+		if resetErr := resetForRetry(ctx); resetErr != nil {
+			return result, errors.Join(resetErr, &InfraError{Attempts: attempt, Err: err})
+		}
+	}
+}
+
+func (r *retryRunner) WithMcpServerInfo(mcpServers mcpproxy.ServerManager) Runner {
+	return &retryRunner{
+		runner:  r.runner.WithMcpServerInfo(mcpServers),
+		policy:  r.policy,
+		onRetry: r.onRetry,
+	}
+}
+
+func (r *retryRunner) AgentName() string {
+	return r.runner.AgentName()
+}
@@ -0,0 +1,264 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type scriptedRunner struct {
+	errs  []error
+	calls int
+}
+
+func (r *scriptedRunner) RunTask(ctx context.Context, prompt string) (AgentResult, error) {
+	r.calls++
+	if r.calls <= len(r.errs) && r.errs[r.calls-1] != nil {
+		return nil, r.errs[r.calls-1]
+	}
+	return &acpResult{}, nil
+}
+
+func (r *scriptedRunner) WithMcpServerInfo(mcpServers mcpproxy.ServerManager) Runner {
+	return r
+}
+
+func (r *scriptedRunner) AgentName() string {
+	return "scripted"
+}
+
+func TestRetryPolicyValidate(t *testing.T) {
+	tests := map[string]struct {
+		policy      RetryPolicy
+		expectedErr string
+	}{
+		"empty": {},
+		"valid": {
+			policy: RetryPolicy{Attempts: 3, Backoff: "1s", MaxBackoff: "10s", Errors: []string{`quota exceeded`}},
+		},
+		"negative attempts": {
+			policy:      RetryPolicy{Attempts: -1},
+			expectedErr: "attempts must be >= 0",
+		},
+		"invalid backoff": {
+			policy:      RetryPolicy{Backoff: "soon"},
+			expectedErr: "invalid backoff",
+		},
+		"negative max backoff": {
+			policy:      RetryPolicy{MaxBackoff: "-1s"},
+			expectedErr: "invalid maxBackoff",
+		},
+		"invalid error pattern": {
+			policy:      RetryPolicy{Errors: []string{`(`}},
+			expectedErr: "invalid errors[0]",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.policy.Validate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestRetryPolicyIsInfraError(t *testing.T) {
+	tests := map[string]struct {
+		policy   *RetryPolicy
+		err      error
+		expected bool
+	}{
+		"nil error": {
+			err: nil,
+		},
+		"rate limited": {
+			err:      errors.New("Too Many Requests: rate limit exceeded"),
+			expected: true,
+		},
+		"status code": {
+			err:      errors.New("POST https://api.example.com/v1/messages: status 503"),
+			expected: true,
+		},
+		"overloaded": {
+			err:      errors.New("Overloaded: the model is overloaded"),
+			expected: true,
+		},
+		"connection refused": {
+			err:      errors.New("dial tcp 127.0.0.1:443: connect: connection refused"),
+			expected: true,
+		},
+		"agent error": {
+			err: errors.New("agent exited with status 1"),
+		},
+		"numbers are not status codes": {
+			err: errors.New("wrote 500 lines"),
+		},
+		"output of a shell agent": {
+			err: fmt.Errorf("failed to run agent: %w", &CommandError{
+				Shell:  "/bin/sh",
+				Script: "agent --prompt 'handle the 429 rate limit'",
+				Err:    errors.New("exit status 1"),
+				Output: []byte("HTTP 429 Too Many Requests"),
+			}),
+		},
+		"command of a shell agent was killed": {
+			policy:   &RetryPolicy{Errors: []string{`signal: killed`}},
+			err:      &CommandError{Shell: "/bin/sh", Script: "agent", Err: errors.New("signal: killed")},
+			expected: true,
+		},
+		"already classified": {
+			err:      &InfraError{Attempts: 1, Err: errors.New("quota exceeded")},
+			expected: true,
+		},
+		"custom patterns replace the defaults": {
+			policy:   &RetryPolicy{Errors: []string{`quota exceeded`}},
+			err:      errors.New("quota exceeded"),
+			expected: true,
+		},
+		"defaults are not used with custom patterns": {
+			policy: &RetryPolicy{Errors: []string{`quota exceeded`}},
+			err:    errors.New("Too Many Requests"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.policy.IsInfraError(tc.err))
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := &RetryPolicy{Backoff: "1s", MaxBackoff: "5s"}
+
+	assert.Equal(t, time.Second, policy.Delay(1))
+	assert.Equal(t, 2*time.Second, policy.Delay(2))
+	assert.Equal(t, 4*time.Second, policy.Delay(3))
+	assert.Equal(t, 5*time.Second, policy.Delay(4))
+	assert.Equal(t, 5*time.Second, policy.Delay(10))
+
+	var nilPolicy *RetryPolicy
+	assert.Equal(t, defaultRetryBackoff, nilPolicy.Delay(1))
+}
+
+func TestRetryRunner(t *testing.T) {
+	rateLimited := errors.New("429 Too Many Requests")
+	policy := &RetryPolicy{Attempts: 3, Backoff: "1ms"}
+
+	t.Run("retries infra errors until the run succeeds", func(t *testing.T) {
+		inner := &scriptedRunner{errs: []error{rateLimited, rateLimited}}
+		var retries []int
+		runner := NewRetryRunner(inner, policy, func(attempt int, delay time.Duration, err error) {
+			retries = append(retries, attempt)
+			assert.Equal(t, rateLimited, err)
+		})
+
+		result, err := runner.RunTask(context.Background(), "prompt")
+		require.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, 3, inner.calls)
+		assert.Equal(t, []int{1, 2}, retries)
+	})
+
+	t.Run("returns an infra error after the last attempt", func(t *testing.T) {
+		inner := &scriptedRunner{errs: []error{rateLimited, rateLimited, rateLimited}}
+		runner := NewRetryRunner(inner, policy, nil)
+
+		_, err := runner.RunTask(context.Background(), "prompt")
+		var infraErr *InfraError
+		require.ErrorAs(t, err, &infraErr)
+		assert.Equal(t, 3, infraErr.Attempts)
+		assert.ErrorIs(t, err, rateLimited)
+		assert.Equal(t, 3, inner.calls)
+		assert.Contains(t, err.Error(), "after 3 attempts")
+	})
+
+	t.Run("does not retry agent errors", func(t *testing.T) {
+		agentErr := errors.New("agent exited with status 1")
+		inner := &scriptedRunner{errs: []error{agentErr}}
+		runner := NewRetryRunner(inner, policy, nil)
+
+		_, err := runner.RunTask(context.Background(), "prompt")
+		assert.Equal(t, agentErr, err)
+		assert.Equal(t, 1, inner.calls)
+	})
+
+	t.Run("classifies infra errors without a policy", func(t *testing.T) {
+		inner := &scriptedRunner{errs: []error{rateLimited}}
+		runner := NewRetryRunner(inner, nil, nil)
+
+		_, err := runner.RunTask(context.Background(), "prompt")
+		var infraErr *InfraError
+		require.ErrorAs(t, err, &infraErr)
+		assert.Equal(t, 1, infraErr.Attempts)
+		assert.Equal(t, 1, inner.calls)
+	})
+
+	t.Run("errors after the context is done are not infra errors", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		inner := &scriptedRunner{errs: []error{rateLimited}}
+		runner := NewRetryRunner(inner, policy, nil)
+
+		_, err := runner.RunTask(ctx, "prompt")
+		var infraErr *InfraError
+		assert.False(t, errors.As(err, &infraErr))
+		assert.Equal(t, 1, inner.calls)
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		inner := &scriptedRunner{errs: []error{rateLimited, rateLimited}}
+		runner := NewRetryRunner(inner, &RetryPolicy{Attempts: 3, Backoff: "1h"}, func(int, time.Duration, error) {
+			cancel()
+		})
+
+		_, err := runner.RunTask(ctx, "prompt")
+		var infraErr *InfraError
+		require.ErrorAs(t, err, &infraErr)
+		assert.Equal(t, 1, infraErr.Attempts)
+		assert.Equal(t, 1, inner.calls)
+	})
+
+	t.Run("resets the task before every retry", func(t *testing.T) {
+		inner := &scriptedRunner{errs: []error{rateLimited, rateLimited}}
+		var resets []int
+		ctx := WithRetryReset(context.Background(), func() error {
+			resets = append(resets, inner.calls)
+			return nil
+		})
+
+		_, err := NewRetryRunner(inner, policy, nil).RunTask(ctx, "prompt")
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, resets, "resets should run between attempts only")
+	})
+
+	t.Run("stops when the task can't be reset", func(t *testing.T) {
+		inner := &scriptedRunner{errs: []error{rateLimited}}
+		ctx := WithRetryReset(context.Background(), func() error {
+			return errors.New("disk full")
+		})
+
+		_, err := NewRetryRunner(inner, policy, nil).RunTask(ctx, "prompt")
+		var infraErr *InfraError
+		require.ErrorAs(t, err, &infraErr)
+		assert.ErrorContains(t, err, "disk full")
+		assert.Equal(t, 1, inner.calls)
+	})
+
+	t.Run("keeps the agent name", func(t *testing.T) {
+		runner := NewRetryRunner(&scriptedRunner{}, policy, nil)
+		assert.Equal(t, "scripted", runner.AgentName())
+		assert.Equal(t, "scripted", runner.WithMcpServerInfo(nil).AgentName())
+	})
+}
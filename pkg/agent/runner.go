@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"text/template"
 
 	"github.com/coder/acp-go-sdk"
+	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
 	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
 	"github.com/mcpchecker/mcpchecker/pkg/tokenizer"
 	"github.com/mcpchecker/mcpchecker/pkg/tokens"
+	"github.com/mcpchecker/mcpchecker/pkg/util"
 )
 
 type Runner interface {
@@ -336,6 +339,15 @@ type AgentResult interface {
 	GetTokenEstimate() tokens.Estimate
 }
 
+// ClientActivityResult is implemented by the results of agents that access files,
+// run commands, request permissions and switch modes through the client, such as ACP agents
+type ClientActivityResult interface {
+	GetFileOperations() []acpclient.FileOperation
+	GetTerminalCommands() []acpclient.TerminalCommand
+	GetPermissionRequests() []acpclient.PermissionRequest
+	GetModeChanges() []acpclient.ModeChange
+}
+
 type agentSpecRunner struct {
 	*AgentSpec
 	mcpInfo McpServerInfo
@@ -343,14 +355,25 @@ type agentSpecRunner struct {
 
 type agentSpecRunnerResult struct {
 	commandOutput string
+	prompt        string
+
+	// parsed is set when the agent has an output format and its output could be parsed
+	parsed   *ParsedOutput
+	parseErr error
 }
 
 func (a *agentSpecRunnerResult) GetOutput() []OutputStep {
+	if a.parsed != nil {
+		return a.parsed.Steps
+	}
 	return []OutputStep{{Type: "message", Content: a.commandOutput}}
 }
 
 func (a *agentSpecRunnerResult) GetToolCalls() []ToolCallSummary {
-	return nil // Shell runner doesn't have structured tool call data
+	if a.parsed != nil {
+		return toolCallsFromSteps(a.parsed.Steps)
+	}
+	return nil // Plain text output doesn't have structured tool call data
 }
 
 func (a *agentSpecRunnerResult) GetRawUpdates() any {
@@ -358,7 +381,32 @@ func (a *agentSpecRunnerResult) GetRawUpdates() any {
 }
 
 func (a *agentSpecRunnerResult) GetTokenEstimate() tokens.Estimate {
-	return tokens.Estimate{Error: "token estimation not supported for shell runner"}
+	if a.parsed == nil {
+		if a.parseErr != nil {
+			return tokens.Estimate{Error: fmt.Sprintf("failed to parse agent output: %v", a.parseErr)}
+		}
+		return tokens.Estimate{Error: "token estimation not supported for shell runner without an output format"}
+	}
+
+	steps := a.parsed.Steps
+	estimate := tokens.ComputeEstimate(
+		a.prompt,
+		FinalMessageFromSteps(steps),
+		thinkingFromSteps(steps),
+		toolCallSummaryToToolCallData(toolCallsFromSteps(steps)),
+	)
+	estimate.Source = tokens.SourceEstimated
+	estimate.Turns = turnsFromSteps(steps)
+
+	if usage := a.parsed.Usage; usage != nil {
+		estimate.Source = tokens.SourceActual
+		estimate.Actual = usage
+		estimate.InputTokens = usage.InputTokens
+		estimate.OutputTokens = usage.OutputTokens
+		estimate.TotalTokens = usage.TotalTokens
+	}
+
+	return estimate
 }
 
 func NewRunnerForSpec(spec *AgentSpec) (Runner, error) {
@@ -368,7 +416,9 @@ func NewRunnerForSpec(spec *AgentSpec) (Runner, error) {
 
 	// check first for acp config
 	if spec.AcpConfig != nil {
-		return NewAcpRunner(spec.AcpConfig, spec.Metadata.Name), nil
+		cfg := *spec.AcpConfig
+		cfg.Session = spec.Session
+		return NewAcpRunner(&cfg, spec.Metadata.Name), nil
 	}
 
 	// Check if this is an LLM agent (or a deprecated alias)
@@ -392,7 +442,24 @@ func NewRunnerForSpec(spec *AgentSpec) (Runner, error) {
 			}
 
 			migrateLegacyEnvVars(spec.Builtin)
-			return NewLLMACPRunner(model)
+			return NewLLMACPRunner(model, spec.Session)
+		}
+	}
+
+	if spec.Session != nil {
+		return nil, fmt.Errorf("session is only supported for ACP agents, agent %q is a shell agent", spec.Metadata.Name)
+	}
+
+	if spec.Sandbox != nil {
+		if err := spec.Sandbox.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid sandbox for agent %q: %w", spec.Metadata.Name, err)
+		}
+	}
+
+	format := spec.Commands.OutputFormat
+	if format != "" && format != OutputFormatText {
+		if _, ok := GetOutputParser(format); !ok {
+			return nil, fmt.Errorf("unknown output format %q for agent %q", format, spec.Metadata.Name)
 		}
 	}
 
@@ -447,6 +514,12 @@ func (a *agentSpecRunner) RunTask(ctx context.Context, prompt string) (AgentResu
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temporary directory for agent execution: %w", err)
 	}
+	// The agent runs in the work directory of the task when there is one, which the
+	// task owns and removes. The temporary directory still holds the virtual home.
+	workDir, ok := util.WorkDirFromContext(ctx)
+	if !ok {
+		workDir = tempDir
+	}
 	executionSucceeded := false
 	defer func() {
 		// Clean up temp directory unless execution failed OR MCPCHECKER_DEBUG is set
@@ -467,17 +540,17 @@ func (a *agentSpecRunner) RunTask(ctx context.Context, prompt string) (AgentResu
 		}
 	}()
 
-	argTemplateMcpServer, err := template.New("argTemplateMcpServer").Parse(a.Commands.ArgTemplateMcpServer)
+	argTemplateMcpServer, err := template.New("argTemplateMcpServer").Funcs(templateFuncs).Parse(a.Commands.ArgTemplateMcpServer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse argTemplateMcpServer: %w", err)
 	}
 
-	argTemplateAllowedTools, err := template.New("argTemplateAllowedTools").Parse(a.Commands.ArgTemplateAllowedTools)
+	argTemplateAllowedTools, err := template.New("argTemplateAllowedTools").Funcs(templateFuncs).Parse(a.Commands.ArgTemplateAllowedTools)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse argTemplateAllowedTools: %w", err)
 	}
 
-	runPrompt, err := template.New("runPrompt").Parse(a.Commands.RunPrompt)
+	runPrompt, err := template.New("runPrompt").Funcs(templateFuncs).Parse(a.Commands.RunPrompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse runPrompt: %w", err)
 	}
@@ -501,9 +574,11 @@ func (a *agentSpecRunner) RunTask(ctx context.Context, prompt string) (AgentResu
 		}
 
 		tmp := struct {
+			Name string
 			File string
 			URL  string
 		}{
+			Name: servers[i].GetName(),
 			File: f,
 			URL:  serverCfg.URL,
 		}
@@ -565,13 +640,26 @@ func (a *agentSpecRunner) RunTask(ctx context.Context, prompt string) (AgentResu
 		shell = "/usr/bin/bash"
 	}
 
-	cmd := exec.CommandContext(ctx, shell, "-c", formatted.String())
-	cmd.Dir = tempDir
+	name, args := a.Sandbox.command(shell, formatted.String())
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = workDir
 	envVars := os.Environ()
+	if a.Sandbox != nil {
+		envVars = a.Sandbox.filterEnv(envVars)
+	}
 	if debugDir != "" {
 		envVars = append(envVars, fmt.Sprintf("MCPCHECKER_DEBUG_DIR=%s", debugDir))
 		envVars = append(envVars, "MCPCHECKER_DEBUG=1")
 	}
+	if a.Sandbox != nil || a.Commands.UseVirtualHome == nil || *a.Commands.UseVirtualHome {
+		// Run the agent without the user's config, such as MCP servers configured globally.
+		// Only unsandboxed agents can opt out, e.g. to use the login of the user.
+		home := filepath.Join(tempDir, "home")
+		if err := os.MkdirAll(home, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create virtual home directory: %w", err)
+		}
+		envVars = append(envVars, fmt.Sprintf("HOME=%s", home))
+	}
 	cmd.Env = envVars
 
 	res, err := cmd.CombinedOutput()
@@ -582,7 +670,13 @@ func (a *agentSpecRunner) RunTask(ctx context.Context, prompt string) (AgentResu
 		}
 		// executionSucceeded remains false, so tempDir will be preserved
 		tempDirSuffix := fmt.Sprintf("\n\ntemporary directory preserved at: %s", tempDir)
-		return nil, fmt.Errorf("failed to run command: %s -c %q: %w.\n\noutput: %s%s%s", shell, formatted.String(), err, res, debugSuffix, tempDirSuffix)
+		return nil, &CommandError{
+			Shell:   shell,
+			Script:  formatted.String(),
+			Err:     err,
+			Output:  res,
+			Details: debugSuffix + tempDirSuffix,
+		}
 	}
 
 	executionSucceeded = true
@@ -597,9 +691,25 @@ func (a *agentSpecRunner) RunTask(ctx context.Context, prompt string) (AgentResu
 		output += fmt.Sprintf("\n\ntemporary directory preserved at: %s", tempDir)
 	}
 
-	return &agentSpecRunnerResult{
+	result := &agentSpecRunnerResult{
 		commandOutput: output,
-	}, nil
+		prompt:        prompt,
+	}
+	if parser, ok := GetOutputParser(a.Commands.OutputFormat); ok {
+		result.parsed, result.parseErr = parser.Parse(string(res))
+	}
+
+	return result, nil
+}
+
+// templateFuncs are available in the command templates of an agent
+var templateFuncs = template.FuncMap{
+	"shellQuote": shellQuote,
+}
+
+// shellQuote quotes s as a single shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 func (a *agentSpecRunner) WithMcpServerInfo(mcpServers mcpproxy.ServerManager) Runner {
@@ -1,11 +1,16 @@
 package agent
 
 import (
+	"context"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
 	"github.com/mcpchecker/mcpchecker/pkg/tokens"
+	"github.com/mcpchecker/mcpchecker/pkg/util"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestComputeTokenEstimate_NilRawInputOutput(t *testing.T) {
@@ -99,3 +104,24 @@ func TestMergeCallHistory_RealRawInputSkipsMerge(t *testing.T) {
 	assert.Equal(t, originalInput, estimate.ToolInputTokens, "should preserve ACP-derived input tokens")
 	assert.Equal(t, originalOutput, estimate.ToolOutputTokens, "should preserve ACP-derived output tokens")
 }
+
+func TestShellRunnerWorkDir(t *testing.T) {
+	spec := &AgentSpec{
+		Metadata: AgentMetadata{Name: "writer"},
+		Commands: AgentCommands{RunPrompt: "pwd; echo done > out.txt"},
+		Sandbox:  &SandboxConfig{},
+	}
+
+	runner, err := NewRunnerForSpec(spec)
+	require.NoError(t, err)
+	runner = runner.WithMcpServerInfo(&mockServerManager{})
+
+	workDir := t.TempDir()
+	result, err := runner.RunTask(util.WithWorkDir(context.Background(), workDir), "hello")
+	require.NoError(t, err)
+
+	// the agent runs in the work directory of the task, which is kept after the run
+	assert.Equal(t, workDir, strings.TrimSpace(result.GetOutput()[0].Content))
+	assert.FileExists(t, filepath.Join(workDir, "out.txt"))
+	assert.NoDirExists(t, filepath.Join(workDir, "home"), "the virtual home is not part of the workspace")
+}
@@ -0,0 +1,68 @@
+package agent
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/mcpchecker/mcpchecker/pkg/util"
+)
+
+const (
+	// SandboxNetworkHost shares the network of the host with the agent (default)
+	SandboxNetworkHost = "host"
+	// SandboxNetworkNone runs the agent in a new network namespace without network access
+	SandboxNetworkNone = "none"
+)
+
+// SandboxConfig isolates a shell agent from the host it runs on. A sandboxed agent
+// always runs with a virtual $HOME.
+type SandboxConfig struct {
+	// Env lists the environment variables passed to the agent in addition to PATH, locale
+	// and terminal settings. Entries ending in "*" match by prefix, e.g. "OPENAI_*".
+	Env []string `json:"env,omitempty"`
+
+	// Network is "host" (default) or "none". "none" runs the agent in a new network
+	// namespace with unshare, and is only supported on Linux.
+	Network string `json:"network,omitempty"`
+}
+
+func (c *SandboxConfig) Validate() error {
+	switch c.Network {
+	case "", SandboxNetworkHost:
+	case SandboxNetworkNone:
+		if runtime.GOOS != "linux" {
+			return fmt.Errorf("sandbox network %q is only supported on linux", SandboxNetworkNone)
+		}
+		if _, err := exec.LookPath("unshare"); err != nil {
+			return fmt.Errorf("sandbox network %q requires unshare: %w", SandboxNetworkNone, err)
+		}
+	default:
+		return fmt.Errorf("invalid sandbox network %q: must be %q or %q", c.Network, SandboxNetworkHost, SandboxNetworkNone)
+	}
+
+	for _, name := range c.Env {
+		if name == "" || strings.Contains(name, "=") {
+			return fmt.Errorf("invalid sandbox env entry %q: must be a variable name", name)
+		}
+	}
+
+	return nil
+}
+
+// filterEnv returns the entries of environ the agent is allowed to see
+func (c *SandboxConfig) filterEnv(environ []string) []string {
+	return util.FilterEnv(environ, c.Env...)
+}
+
+// command returns the command that runs script with shell, inside a new network
+// namespace if the sandbox has no network
+func (c *SandboxConfig) command(shell, script string) (string, []string) {
+	if c == nil || c.Network != SandboxNetworkNone {
+		return shell, []string{"-c", script}
+	}
+
+	// --map-root-user creates a user namespace, so that no privileges are needed
+	return "unshare", []string{"--net", "--map-root-user", shell, "-c", script}
+}
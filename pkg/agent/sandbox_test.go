@@ -0,0 +1,175 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mcpchecker/mcpchecker/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSandboxConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		config      SandboxConfig
+		expectedErr string
+	}{
+		"empty": {},
+		"valid": {
+			config: SandboxConfig{Env: []string{"OPENAI_API_KEY", "KUBE*"}},
+		},
+		"env with value": {
+			config:      SandboxConfig{Env: []string{"HOME=/root"}},
+			expectedErr: "must be a variable name",
+		},
+		"host network": {
+			config: SandboxConfig{Network: SandboxNetworkHost},
+		},
+		"unknown network": {
+			config:      SandboxConfig{Network: "bridge"},
+			expectedErr: "invalid sandbox network",
+		},
+		"empty env entry": {
+			config:      SandboxConfig{Env: []string{""}},
+			expectedErr: "must be a variable name",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestSandboxConfigValidateNoNetwork(t *testing.T) {
+	config := SandboxConfig{Network: SandboxNetworkNone}
+	if runtime.GOOS != "linux" {
+		assert.ErrorContains(t, config.Validate(), "only supported on linux")
+		return
+	}
+
+	t.Setenv("PATH", t.TempDir())
+	assert.ErrorContains(t, config.Validate(), "requires unshare")
+}
+
+func TestSandboxConfigFilterEnv(t *testing.T) {
+	environ := []string{
+		"PATH=/usr/bin",
+		"HOME=/home/dev",
+		"KUBECONFIG=/home/dev/.kube/config",
+		"OPENAI_API_KEY=sk-test",
+		"OPENAI_BASE_URL=http://localhost",
+		"AWS_SECRET_ACCESS_KEY=secret",
+		"LC_ALL=C",
+	}
+
+	config := &SandboxConfig{Env: []string{"OPENAI_*"}}
+	assert.Equal(t, []string{
+		"PATH=/usr/bin",
+		"OPENAI_API_KEY=sk-test",
+		"OPENAI_BASE_URL=http://localhost",
+		"LC_ALL=C",
+	}, config.filterEnv(environ))
+}
+
+func TestShellRunnerSandbox(t *testing.T) {
+	fakeBinary(t, "agent", `echo "HOME=$HOME"; echo "KEY=${OPENAI_API_KEY:-}"; echo "KUBE=${KUBECONFIG:-}"; ls`)
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+	t.Setenv("KUBECONFIG", "/home/dev/.kube/config")
+
+	workDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workDir, "pod.yaml"), []byte("kind: Pod"), 0o644))
+
+	useVirtualHome := false
+	spec := &AgentSpec{
+		Metadata: AgentMetadata{Name: "sandboxed"},
+		Commands: AgentCommands{RunPrompt: "agent", UseVirtualHome: &useVirtualHome},
+		Sandbox:  &SandboxConfig{Env: []string{"OPENAI_API_KEY"}},
+	}
+
+	runner, err := NewRunnerForSpec(spec)
+	require.NoError(t, err)
+	runner = runner.WithMcpServerInfo(&mockServerManager{})
+
+	result, err := runner.RunTask(util.WithWorkDir(context.Background(), workDir), "hello")
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(result.GetOutput()[0].Content), "\n")
+	require.Len(t, lines, 4)
+	assert.True(t, strings.HasSuffix(lines[0], "/home"), "sandboxed agents always get a virtual home: %s", lines[0])
+	assert.Equal(t, "KEY=sk-test", lines[1])
+	assert.Equal(t, "KUBE=", lines[2], "variables that are not allowed should not be passed")
+	assert.Equal(t, "pod.yaml", lines[3], "the agent should run in the task workspace")
+}
+
+func TestShellRunnerVirtualHome(t *testing.T) {
+	t.Setenv("HOME", "/home/dev")
+
+	disabled := false
+	tests := map[string]struct {
+		useVirtualHome *bool
+		virtual        bool
+	}{
+		"default":  {virtual: true},
+		"disabled": {useVirtualHome: &disabled, virtual: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			spec := &AgentSpec{
+				Metadata: AgentMetadata{Name: "home"},
+				Commands: AgentCommands{RunPrompt: "echo $HOME", UseVirtualHome: tc.useVirtualHome},
+			}
+
+			runner, err := NewRunnerForSpec(spec)
+			require.NoError(t, err)
+			runner = runner.WithMcpServerInfo(&mockServerManager{})
+
+			result, err := runner.RunTask(context.Background(), "hello")
+			require.NoError(t, err)
+
+			home := strings.TrimSpace(result.GetOutput()[0].Content)
+			if tc.virtual {
+				assert.NotEqual(t, "/home/dev", home)
+				assert.True(t, strings.HasSuffix(home, "/home"), home)
+			} else {
+				assert.Equal(t, "/home/dev", home)
+			}
+		})
+	}
+}
+
+func TestShellRunnerSandboxNoNetwork(t *testing.T) {
+	if err := exec.Command("unshare", "--net", "--map-root-user", "true").Run(); err != nil {
+		t.Skipf("unshare is not usable here: %v", err)
+	}
+
+	spec := &AgentSpec{
+		Metadata: AgentMetadata{Name: "offline"},
+		Commands: AgentCommands{RunPrompt: "cat /proc/net/dev"},
+		Sandbox:  &SandboxConfig{Network: SandboxNetworkNone},
+	}
+
+	runner, err := NewRunnerForSpec(spec)
+	require.NoError(t, err)
+	runner = runner.WithMcpServerInfo(&mockServerManager{})
+
+	result, err := runner.RunTask(context.Background(), "hello")
+	require.NoError(t, err)
+
+	// only the loopback interface exists in the new network namespace
+	output := result.GetOutput()[0].Content
+	assert.Contains(t, output, "lo:")
+	assert.Equal(t, 3, strings.Count(strings.TrimSpace(output), "\n")+1, output)
+}
@@ -109,19 +109,19 @@ func calculateDiff(baseFile, currentFile string, baseResults, currentResults []*
 
 	baseMap := make(map[string]*eval.EvalResult)
 	for _, r := range baseResults {
-		baseMap[r.TaskName] = r
+		baseMap[results.TaskLabel(r)] = r
 	}
 
 	currentMap := make(map[string]*eval.EvalResult)
 	for _, r := range currentResults {
-		currentMap[r.TaskName] = r
+		currentMap[results.TaskLabel(r)] = r
 	}
 
 	for _, current := range currentResults {
-		base, exists := baseMap[current.TaskName]
+		base, exists := baseMap[results.TaskLabel(current)]
 		if !exists {
 			diff.New = append(diff.New, TaskDiff{
-				TaskName:           current.TaskName,
+				TaskName:           results.TaskLabel(current),
 				HeadPassed:         current.TaskPassed && current.AllAssertionsPassed,
 				HeadAssertions:     results.PassedAssertions(current),
 				HeadAssertionTotal: results.TotalAssertions(current),
@@ -133,7 +133,7 @@ func calculateDiff(baseFile, currentFile string, baseResults, currentResults []*
 		currentPassed := current.TaskPassed && current.AllAssertionsPassed
 
 		taskDiff := TaskDiff{
-			TaskName:           current.TaskName,
+			TaskName:           results.TaskLabel(current),
 			BasePassed:         basePassed,
 			HeadPassed:         currentPassed,
 			BaseAssertions:     results.PassedAssertions(base),
@@ -151,9 +151,9 @@ func calculateDiff(baseFile, currentFile string, baseResults, currentResults []*
 	}
 
 	for _, base := range baseResults {
-		if _, exists := currentMap[base.TaskName]; !exists {
+		if _, exists := currentMap[results.TaskLabel(base)]; !exists {
 			diff.Removed = append(diff.Removed, TaskDiff{
-				TaskName:           base.TaskName,
+				TaskName:           results.TaskLabel(base),
 				BasePassed:         base.TaskPassed && base.AllAssertionsPassed,
 				BaseAssertions:     results.PassedAssertions(base),
 				BaseAssertionTotal: results.TotalAssertions(base),
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/mcpchecker/mcpchecker/pkg/eval"
+	"github.com/mcpchecker/mcpchecker/pkg/llmagent"
+	"github.com/mcpchecker/mcpchecker/pkg/llmjudge"
+	"github.com/mcpchecker/mcpchecker/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// NewJudgeCmd creates the judge parent command
+func NewJudgeCmd() *cobra.Command {
+	judgeCmd := &cobra.Command{
+		Use:   "judge",
+		Short: "Commands for working with the LLM judge",
+		Long:  `Commands for working with the LLM judge configured in an eval's llmJudge section.`,
+	}
+
+	judgeCmd.AddCommand(NewJudgeCalibrateCmd())
+
+	return judgeCmd
+}
+
+// NewJudgeCalibrateCmd creates the judge calibrate command
+func NewJudgeCalibrateCmd() *cobra.Command {
+	var evalFile string
+	var model string
+	var outputFormat string
+	var parallel int
+	var verbose bool
+
+	cmd := &cobra.Command{
+		Use:   "calibrate <dataset-file>",
+		Short: "Measure LLM judge agreement with human-labeled verdicts",
+		Long: `Run the LLM judge configured in an eval file over a dataset of human-labeled
+examples and report precision, recall and accuracy, treating "pass" as the
+positive class. Every example where the judge disagrees with the human label
+is listed.
+
+The dataset is a YAML or JSON file:
+
+  kind: JudgeCalibrationDataset
+  metadata:
+    name: k8s-answers
+  examples:
+    - id: image-version
+      prompt: What image is the web pod running?
+      output: The web pod runs mysql:8.0.36.
+      reference: mysql:8.0.36
+      mode: contains   # or exact
+      verdict: pass    # human verdict: pass or fail
+
+Examples:
+  mcpchecker judge calibrate dataset.yaml --eval eval.yaml
+  mcpchecker judge calibrate dataset.yaml --eval eval.yaml --model anthropic:claude-sonnet-4-20250514 -o json`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ds, err := llmjudge.CalibrationDatasetFromFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to load calibration dataset: %w", err)
+			}
+
+			spec, err := eval.FromFile(evalFile)
+			if err != nil {
+				return fmt.Errorf("failed to load eval config: %w", err)
+			}
+
+			judgeCfg := spec.Config.LLMJudge
+			if judgeCfg == nil {
+				return fmt.Errorf("eval config %s has no llmJudge configured", evalFile)
+			}
+			if model != "" {
+				judgeCfg, err = withJudgeModel(judgeCfg, model)
+				if err != nil {
+					return err
+				}
+			}
+
+			judge, err := llmjudge.NewLLMJudge(judgeCfg)
+			if err != nil {
+				return fmt.Errorf("failed to create llm judge: %w", err)
+			}
+			defer judge.Close()
+
+			ctx := util.WithVerbose(context.Background(), verbose)
+			ctx = llmagent.WithRateLimiters(ctx, llmagent.NewRateLimiters(spec.Config.RateLimits))
+			report := llmjudge.Calibrate(ctx, judge, ds, parallel)
+
+			switch outputFormat {
+			case "json":
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			case "text":
+				outputCalibrationReport(report)
+				return nil
+			default:
+				return fmt.Errorf("unknown output format: %s", outputFormat)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&evalFile, "eval", "", "Path to the eval config providing the llmJudge config")
+	cmd.Flags().StringVar(&model, "model", "", "Override the model of the judge agent ref (e.g. openai:gpt-4o)")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format (text, json)")
+	cmd.Flags().IntVarP(&parallel, "parallel", "p", 1, "Number of examples to judge concurrently")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	_ = cmd.MarkFlagRequired("eval")
+
+	return cmd
+}
+
+func outputCalibrationReport(report *llmjudge.CalibrationReport) {
+	red := color.New(color.FgRed)
+	yellow := color.New(color.FgYellow)
+	bold := color.New(color.Bold)
+
+	verdict := func(passed bool) string {
+		if passed {
+			return "pass"
+		}
+		return "fail"
+	}
+
+	_, _ = bold.Println("=== Judge Calibration ===")
+	fmt.Printf("Judge:    %s\n", report.Judge)
+	fmt.Printf("Examples: %d\n", report.Total)
+	fmt.Println()
+
+	fmt.Printf("%-16s %-12s %-12s\n", "", "judge pass", "judge fail")
+	fmt.Printf("%-16s %-12d %-12d\n", "human pass", report.TruePositives, report.FalseNegatives)
+	fmt.Printf("%-16s %-12d %-12d\n", "human fail", report.FalsePositives, report.TrueNegatives)
+	if report.Errors > 0 {
+		_, _ = red.Printf("Errors:   %d (excluded from metrics)\n", report.Errors)
+	}
+	fmt.Println()
+
+	fmt.Printf("Precision: %.2f%%\n", report.Precision*100)
+	fmt.Printf("Recall:    %.2f%%\n", report.Recall*100)
+	fmt.Printf("Accuracy:  %.2f%%\n", report.Accuracy*100)
+
+	if report.Usage != nil {
+		fmt.Printf("Judge used tokens:\n")
+		fmt.Printf("  Input:  %d tokens\n", report.Usage.InputTokens)
+		fmt.Printf("  Output: %d tokens\n", report.Usage.OutputTokens)
+	}
+
+	if len(report.Disagreements) == 0 {
+		return
+	}
+
+	fmt.Println()
+	_, _ = bold.Println("=== Disagreements ===")
+	for _, d := range report.Disagreements {
+		if d.Error != "" {
+			_, _ = red.Printf("  ✗ %s: judge error: %s\n", d.ID, d.Error)
+			continue
+		}
+		_, _ = yellow.Printf("  ≠ %s (%s): human=%s judge=%s\n", d.ID, d.Mode, verdict(d.HumanPassed), verdict(d.JudgePassed))
+		if d.FailureCategory != "" && d.FailureCategory != "n/a" {
+			fmt.Printf("      category: %s\n", d.FailureCategory)
+		}
+		if d.Reason != "" {
+			fmt.Printf("      reason: %s\n", d.Reason)
+		}
+	}
+}
+
+// withJudgeModel returns a copy of cfg whose agent uses model, keeping all other judge settings
+func withJudgeModel(cfg *llmjudge.LLMJudgeEvalConfig, model string) (*llmjudge.LLMJudgeEvalConfig, error) {
+	if cfg.AgentRef == nil {
+		return nil, fmt.Errorf("--model requires llmJudge.ref to be set in the eval config")
+	}
+
+	ref := *cfg.AgentRef
+	ref.Model = model
+	out := *cfg
+	out.AgentRef = &ref
+
+	return &out, nil
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/mcpchecker/mcpchecker/pkg/agent"
+	"github.com/mcpchecker/mcpchecker/pkg/llmagent"
+	"github.com/mcpchecker/mcpchecker/pkg/llmjudge"
+)
+
+func TestWithJudgeModel(t *testing.T) {
+	cfg := &llmjudge.LLMJudgeEvalConfig{
+		Env:       &llmjudge.LLMJudgeEnvConfig{BaseUrlKey: "JUDGE_BASE_URL", ApiKeyKey: "JUDGE_API_KEY", ModelNameKey: "JUDGE_MODEL"},
+		AgentRef:  &agent.AgentRef{Type: "builtin.llm-agent", Model: "openai:gpt-4o"},
+		RateLimit: &llmagent.RateLimit{RequestsPerMinute: 10, TokensPerMinute: 1000},
+	}
+
+	got, err := withJudgeModel(cfg, "anthropic:claude-sonnet-4-20250514")
+	if err != nil {
+		t.Fatalf("withJudgeModel() error = %v", err)
+	}
+
+	if got.AgentRef.Model != "anthropic:claude-sonnet-4-20250514" {
+		t.Errorf("AgentRef.Model = %q, want the overridden model", got.AgentRef.Model)
+	}
+	if got.AgentRef.Type != "builtin.llm-agent" {
+		t.Errorf("AgentRef.Type = %q, want builtin.llm-agent", got.AgentRef.Type)
+	}
+	if got.Env != cfg.Env {
+		t.Errorf("Env = %+v, want the env of the eval config", got.Env)
+	}
+	if got.RateLimit != cfg.RateLimit {
+		t.Errorf("RateLimit = %+v, want the rate limit of the eval config", got.RateLimit)
+	}
+	if cfg.AgentRef.Model != "openai:gpt-4o" {
+		t.Errorf("the eval config was changed, AgentRef.Model = %q", cfg.AgentRef.Model)
+	}
+
+	if _, err := withJudgeModel(&llmjudge.LLMJudgeEvalConfig{Env: cfg.Env}, "openai:gpt-4o"); err == nil {
+		t.Error("withJudgeModel() without ref should fail")
+	}
+}
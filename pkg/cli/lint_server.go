@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/mcpchecker/mcpchecker/pkg/serverlint"
+	"github.com/spf13/cobra"
+)
+
+// NewLintServerCmd creates the lint-server command
+func NewLintServerCmd() *cobra.Command {
+	var mcpConfigFile string
+	var outputFormat string
+	var maxDescriptionTokens int
+	var strict bool
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "lint-server",
+		Short: "Check MCP servers for schema and description problems",
+		Long: `Connect to the MCP servers of a config file, list their tools, prompts and
+resources, and report problems that commonly make agents fail: invalid or
+non-object schemas, missing or overly long descriptions and names used by
+more than one server.
+
+Without --mcp-config-file, the server is read from the MCP_URL/MCP_COMMAND
+environment variables. Exits with code 1 if errors are found, or warnings
+with --strict.`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config, err := loadLintConfig(mcpConfigFile)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			report, err := serverlint.Lint(ctx, config, serverlint.Options{MaxDescriptionTokens: maxDescriptionTokens})
+			if err != nil {
+				return fmt.Errorf("failed to check servers: %w", err)
+			}
+
+			switch outputFormat {
+			case "json":
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(report); err != nil {
+					return fmt.Errorf("failed to encode report: %w", err)
+				}
+			case "text":
+				printLintReport(report)
+			default:
+				return fmt.Errorf("unknown output format: %s", outputFormat)
+			}
+
+			if report.Errors() > 0 || (strict && report.Warnings() > 0) {
+				// silent error (SilenceErrors: true), sets exit code 1
+				return fmt.Errorf("server checks failed")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&mcpConfigFile, "mcp-config-file", "", "Path to MCP config file")
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format (text, json)")
+	cmd.Flags().IntVar(&maxDescriptionTokens, "max-description-tokens", serverlint.DefaultMaxDescriptionTokens, "Longest description in tokens before a warning is reported")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail on warnings as well as errors")
+	cmd.Flags().DurationVar(&timeout, "timeout", 2*time.Minute, "Timeout for connecting to and listing the servers")
+
+	return cmd
+}
+
+func loadLintConfig(mcpConfigFile string) (*mcpclient.MCPConfig, error) {
+	if mcpConfigFile != "" {
+		config, err := mcpclient.ParseConfigFile(mcpConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load MCP config from file: %w", err)
+		}
+		return config, nil
+	}
+
+	config, err := mcpclient.ConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load MCP config from environment: %w", err)
+	}
+	if config == nil {
+		return nil, fmt.Errorf("no MCP configuration found: specify --mcp-config-file or set MCP_URL/MCP_COMMAND environment variables")
+	}
+
+	return config, nil
+}
+
+// printLintReport prints the issues of a server checks report grouped by server
+func printLintReport(report *serverlint.Report) {
+	red := color.New(color.FgRed)
+	yellow := color.New(color.FgYellow)
+	green := color.New(color.FgGreen)
+	bold := color.New(color.Bold)
+
+	_, _ = bold.Println("=== Server Checks ===")
+	for _, srv := range report.Servers {
+		fmt.Printf("%s: %d tools, %d prompts, %d resources, %d resource templates\n",
+			srv.Name, srv.Tools, srv.Prompts, srv.Resources, srv.ResourceTemplates)
+
+		for _, issue := range report.Issues {
+			if issue.Server != srv.Name {
+				continue
+			}
+
+			c := yellow
+			if issue.Severity == serverlint.SeverityError {
+				c = red
+			}
+			subject := strings.TrimSpace(issue.Kind + " " + issue.Name)
+			_, _ = c.Printf("  %-7s %s [%s]: %s\n", issue.Severity, subject, issue.Rule, issue.Message)
+		}
+	}
+
+	fmt.Println()
+	if len(report.Issues) == 0 {
+		_, _ = green.Println("No problems found")
+		return
+	}
+	fmt.Printf("%d errors, %d warnings\n", report.Errors(), report.Warnings())
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+)
+
+// NewProxyShimCmd creates the hidden proxy-shim command, which agents launch as a
+// stdio MCP server to reach a recording proxy. Headers for the proxy are read from
+// the environment, see mcpproxy.ShimHeadersEnv.
+func NewProxyShimCmd() *cobra.Command {
+	var url string
+
+	cmd := &cobra.Command{
+		Use:    mcpproxy.ShimCommand,
+		Short:  "Relay a stdio MCP connection to a recording proxy",
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hdrs, err := mcpproxy.ParseShimHeaders(os.Getenv(mcpproxy.ShimHeadersEnv))
+			if err != nil {
+				return err
+			}
+
+			if err := mcpproxy.RunShim(cmd.Context(), url, hdrs, &mcp.StdioTransport{}); err != nil {
+				return fmt.Errorf("proxy shim failed: %w", err)
+			}
+
+			return nil
+		},
+		SilenceUsage: true,
+	}
+
+	cmd.Flags().StringVar(&url, "url", "", "SSE endpoint of the recording proxy")
+	_ = cmd.MarkFlagRequired("url")
+
+	return cmd
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mcpchecker/mcpchecker/pkg/eval"
+	"github.com/mcpchecker/mcpchecker/pkg/results"
+	"github.com/mcpchecker/mcpchecker/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// NewRejudgeCmd creates the rejudge command
+func NewRejudgeCmd() *cobra.Command {
+	var evalFile string
+	var outputFile string
+	var verbose bool
+
+	cmd := &cobra.Command{
+		Use:   "rejudge <results-file>",
+		Short: "Re-run the LLM judge on an existing results file",
+		Long: `Re-run only the llmJudge verify steps of an existing results file, using the
+judge configured in the given eval file and the agent output stored in the results.
+
+The agent, setup, cleanup and all other verify steps are not run again; their
+recorded outputs are reused. This is useful after tuning a judge prompt or
+switching judge models. Tasks without llmJudge steps, or whose agent failed or
+timed out, are copied to the new results file unchanged.
+
+Examples:
+  mcpchecker result rejudge mcpchecker-my-eval-out.json --eval eval.yaml
+  mcpchecker result rejudge results.json --eval eval-new-judge.yaml --output-file rejudged.json`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resultsFile := args[0]
+
+			prev, err := results.LoadOutput(resultsFile)
+			if err != nil {
+				return fmt.Errorf("failed to load results file: %w", err)
+			}
+
+			spec, err := eval.FromFile(evalFile)
+			if err != nil {
+				return fmt.Errorf("failed to load eval config: %w", err)
+			}
+
+			ctx := util.WithVerbose(context.Background(), verbose)
+			output, report, err := eval.Rejudge(ctx, spec, prev)
+			if err != nil {
+				return fmt.Errorf("rejudge failed: %w", err)
+			}
+
+			if outputFile == "" {
+				outputFile = defaultRejudgeOutputFile(resultsFile)
+			}
+			if err := saveOutputToFile(output, outputFile); err != nil {
+				return fmt.Errorf("failed to save results to file: %w", err)
+			}
+
+			outputRejudgeReport(report)
+			fmt.Printf("\n📄 Results saved to: %s\n", outputFile)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&evalFile, "eval", "", "Path to the eval config providing the tasks and llmJudge config")
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "Path to write the new results file (default: <results-file>-rejudged.json)")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	_ = cmd.MarkFlagRequired("eval")
+
+	return cmd
+}
+
+// defaultRejudgeOutputFile derives the output path from the input results file
+func defaultRejudgeOutputFile(resultsFile string) string {
+	ext := filepath.Ext(resultsFile)
+	return strings.TrimSuffix(resultsFile, ext) + "-rejudged.json"
+}
+
+func outputRejudgeReport(report []eval.RejudgeResult) {
+	green := color.New(color.FgGreen)
+	red := color.New(color.FgRed)
+	bold := color.New(color.Bold)
+
+	_, _ = bold.Println("=== Rejudge Summary ===")
+	fmt.Println()
+
+	rejudged := 0
+	changed := 0
+	for _, entry := range report {
+		if !entry.Rejudged {
+			fmt.Printf("  - %s (skipped: %s)\n", entry.TaskName, entry.Skipped)
+			continue
+		}
+
+		rejudged++
+		verdict := func(passed bool) string {
+			if passed {
+				return "PASSED"
+			}
+			return "FAILED"
+		}
+
+		if entry.Passed == entry.PreviouslyPassed {
+			fmt.Printf("  = %s: %s\n", entry.TaskName, verdict(entry.Passed))
+			continue
+		}
+
+		changed++
+		c := red
+		if entry.Passed {
+			c = green
+		}
+		_, _ = c.Printf("  ≠ %s: %s -> %s\n", entry.TaskName, verdict(entry.PreviouslyPassed), verdict(entry.Passed))
+	}
+
+	fmt.Println()
+	fmt.Printf("Rejudged: %d/%d tasks\n", rejudged, len(report))
+	fmt.Printf("Changed:  %d verdicts\n", changed)
+}
@@ -17,7 +17,10 @@ It runs agents through defined tasks and validates their behavior using assertio
 	// Add subcommands
 	rootCmd.AddCommand(NewEvalCmd())
 	rootCmd.AddCommand(NewResultCmd())
+	rootCmd.AddCommand(NewJudgeCmd())
 	rootCmd.AddCommand(NewVersionCmd())
+	rootCmd.AddCommand(NewLintServerCmd())
+	rootCmd.AddCommand(NewProxyShimCmd())
 
 	return rootCmd
 }
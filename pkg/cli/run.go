@@ -12,6 +12,7 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/mcpchecker/mcpchecker/pkg/eval"
+	"github.com/mcpchecker/mcpchecker/pkg/results"
 	"github.com/mcpchecker/mcpchecker/pkg/util"
 	"github.com/spf13/cobra"
 )
@@ -162,7 +163,7 @@ func newProgressDisplay(verbose bool) *progressDisplay {
 // taskPrefix returns a prefix for progress output. For parallel tasks, includes task name.
 func taskPrefix(task *eval.EvalResult) string {
 	if task != nil && task.Parallel {
-		return fmt.Sprintf("[%s] ", task.TaskName)
+		return fmt.Sprintf("[%s] ", results.TaskLabel(task))
 	}
 	return "  "
 }
@@ -188,12 +189,12 @@ func (d *progressDisplay) handleProgress(event eval.ProgressEvent) {
 		}
 		if event.Task.Parallel {
 			if event.Task.Difficulty != "" {
-				d.cyan.Printf("[%s]%s Starting (parallel, %s)\n", event.Task.TaskName, runInfo, event.Task.Difficulty)
+				d.cyan.Printf("[%s]%s Starting (parallel, %s)\n", results.TaskLabel(event.Task), runInfo, event.Task.Difficulty)
 			} else {
-				d.cyan.Printf("[%s]%s Starting (parallel)\n", event.Task.TaskName, runInfo)
+				d.cyan.Printf("[%s]%s Starting (parallel)\n", results.TaskLabel(event.Task), runInfo)
 			}
 		} else {
-			d.cyan.Printf("Task: %s%s\n", event.Task.TaskName, runInfo)
+			d.cyan.Printf("Task: %s%s\n", results.TaskLabel(event.Task), runInfo)
 			if event.Task.Difficulty != "" {
 				fmt.Printf("  Difficulty: %s\n", event.Task.Difficulty)
 			}
@@ -207,6 +208,12 @@ func (d *progressDisplay) handleProgress(event eval.ProgressEvent) {
 	case eval.EventTaskRunning:
 		fmt.Printf("%s→ Running agent...\n", prefix)
 
+	case eval.EventTaskRetry:
+		d.yellow.Printf("%s↻ %s\n", prefix, event.Message)
+
+	case eval.EventRateLimited:
+		d.yellow.Printf("%s⏳ %s\n", prefix, event.Message)
+
 	case eval.EventTaskVerifying:
 		fmt.Printf("%s→ Verifying results...\n", prefix)
 
@@ -236,9 +243,13 @@ func (d *progressDisplay) handleProgress(event eval.ProgressEvent) {
 			d.yellow.Printf("%s~ Task passed but assertions failed\n", prefix)
 		} else {
 			if task.AgentExecutionError {
-				d.red.Printf("%s✗ Agent failed to run\n", prefix)
+				if task.FailureKind == eval.FailureInfra {
+					d.red.Printf("%s✗ Agent failed with an infra error\n", prefix)
+				} else {
+					d.red.Printf("%s✗ Agent failed to run\n", prefix)
+				}
 				if task.TaskError != "" || task.TaskOutput != "" {
-					errorFile, err := saveErrorToFile(task.TaskName, task.TaskError, task.TaskOutput)
+					errorFile, err := saveErrorToFile(results.TaskLabel(task), task.TaskError, task.TaskOutput)
 					if err != nil {
 						fmt.Printf("%s  Error: %s\n", prefix, task.TaskError)
 					} else {
@@ -268,6 +279,9 @@ func (d *progressDisplay) printSummary(s *eval.EvalSummary) {
 		if s.Agent.Name != "" {
 			fmt.Printf("Agent Name:     %s\n", s.Agent.Name)
 		}
+		if s.Agent.Version != "" {
+			fmt.Printf("Agent Version:  %s\n", s.Agent.Version)
+		}
 		if s.Agent.Model != "" {
 			fmt.Printf("Agent Model:    %s\n", s.Agent.Model)
 		}
@@ -298,7 +312,9 @@ func (d *progressDisplay) printSummary(s *eval.EvalSummary) {
 	}
 
 	for _, srv := range s.MCPServers {
-		if srv.URL != "" {
+		if srv.URL != "" && srv.Auth != "" {
+			fmt.Printf("MCP Server:     %s: %s (auth: %s)\n", srv.Name, srv.URL, srv.Auth)
+		} else if srv.URL != "" {
 			fmt.Printf("MCP Server:     %s: %s\n", srv.Name, srv.URL)
 		} else if srv.Command != "" {
 			fmt.Printf("MCP Server:     %s: %s (stdio)\n", srv.Name, srv.Command)
@@ -322,6 +338,10 @@ func (d *progressDisplay) printSummary(s *eval.EvalSummary) {
 				fmt.Printf("  Label Selector: %s=%s\n", k, v)
 			}
 		}
+
+		if len(s.Evals.Variants) > 0 {
+			fmt.Printf("Variants:       %s\n", strings.Join(s.Evals.Variants, ", "))
+		}
 	}
 
 	if s.Timeout != nil {
@@ -355,6 +375,10 @@ func displayResults(output *eval.EvalOutput, format string) error {
 		return encoder.Encode(output)
 
 	case "text":
+		if output.ServerChecks != nil {
+			fmt.Println()
+			printLintReport(output.ServerChecks)
+		}
 		return displayTextResults(output.Results)
 
 	default:
@@ -362,7 +386,7 @@ func displayResults(output *eval.EvalOutput, format string) error {
 	}
 }
 
-func displayTextResults(results []*eval.EvalResult) error {
+func displayTextResults(evalResults []*eval.EvalResult) error {
 	green := color.New(color.FgGreen)
 	red := color.New(color.FgRed)
 	yellow := color.New(color.FgYellow)
@@ -372,7 +396,7 @@ func displayTextResults(results []*eval.EvalResult) error {
 	bold.Println("=== Results Summary ===")
 	fmt.Println()
 
-	totalTasks := len(results)
+	totalTasks := len(evalResults)
 	tasksPassed := 0
 	totalAssertions := 0
 	passedAssertions := 0
@@ -380,7 +404,7 @@ func displayTextResults(results []*eval.EvalResult) error {
 	verificationFailedButAssertionsPassedTotal := 0
 	verificationFailedButAssertionsPassedCount := 0
 
-	for _, result := range results {
+	for _, result := range evalResults {
 		if result.TaskPassed {
 			tasksPassed++
 		}
@@ -403,7 +427,7 @@ func displayTextResults(results []*eval.EvalResult) error {
 		}
 
 		// Display individual result
-		fmt.Printf("Task: %s\n", result.TaskName)
+		fmt.Printf("Task: %s\n", results.TaskLabel(result))
 		fmt.Printf("  Path: %s\n", result.TaskPath)
 		if result.Difficulty != "" {
 			fmt.Printf("  Difficulty: %s\n", result.Difficulty)
@@ -418,9 +442,13 @@ func displayTextResults(results []*eval.EvalResult) error {
 					fmt.Printf("  Error: %s\n", result.TaskError)
 				}
 			} else if result.AgentExecutionError {
-				red.Printf("  Task Status: FAILED (Agent execution error)\n")
+				if result.FailureKind == eval.FailureInfra {
+					red.Printf("  Task Status: FAILED (Infra error)\n")
+				} else {
+					red.Printf("  Task Status: FAILED (Agent execution error)\n")
+				}
 				if result.TaskError != "" || result.TaskOutput != "" {
-					errorFile, err := saveErrorToFile(result.TaskName, result.TaskError, result.TaskOutput)
+					errorFile, err := saveErrorToFile(results.TaskLabel(result), result.TaskError, result.TaskOutput)
 					if err != nil {
 						// If we can't save to file, fall back to printing inline
 						fmt.Printf("  Error: %s\n", result.TaskError)
@@ -452,6 +480,17 @@ func displayTextResults(results []*eval.EvalResult) error {
 			}
 		}
 
+		if len(result.ServerEvents) > 0 {
+			yellow.Printf("  Server Events:\n")
+			for _, ev := range result.ServerEvents {
+				if ev.Error != "" {
+					fmt.Printf("    - %s %s: %s\n", ev.Server, ev.Type, ev.Error)
+				} else {
+					fmt.Printf("    - %s %s\n", ev.Server, ev.Type)
+				}
+			}
+		}
+
 		fmt.Println()
 	}
 
@@ -487,7 +526,7 @@ func displayTextResults(results []*eval.EvalResult) error {
 	var totalTokens int64
 	var totalMcpSchemaTokens int64
 	hasTokenErrors := false
-	for _, result := range results {
+	for _, result := range evalResults {
 		if result.TokenEstimate != nil {
 			totalTokens += result.TokenEstimate.TotalTokens
 			totalMcpSchemaTokens += result.TokenEstimate.McpSchemaTokens
@@ -501,10 +540,13 @@ func displayTextResults(results []*eval.EvalResult) error {
 	// Group by difficulty
 	fmt.Println()
 	bold.Println("=== Statistics by Difficulty ===")
-	displayStatsByDifficulty(results, green, yellow)
+	displayStatsByDifficulty(evalResults, green, yellow)
 
 	// Show consistency summary for multi-run
-	displayConsistencySummary(results)
+	displayConsistencySummary(evalResults)
+
+	// Compare toolOverrides variants side by side
+	displayVariantSummary(evalResults)
 
 	return nil
 }
@@ -612,6 +654,20 @@ func printFailedAssertions(results *eval.CompositeAssertionResult) {
 	printSingleAssertion("PromptsNotUsed", results.PromptsNotUsed)
 	printSingleAssertion("CallOrder", results.CallOrder)
 	printSingleAssertion("NoDuplicateCalls", results.NoDuplicateCalls)
+	printSingleAssertion("SamplingUsed", results.SamplingUsed)
+	printSingleAssertion("SamplingNotUsed", results.SamplingNotUsed)
+	printSingleAssertion("ElicitationUsed", results.ElicitationUsed)
+	printSingleAssertion("ElicitationNotUsed", results.ElicitationNotUsed)
+	printSingleAssertion("RootsListed", results.RootsListed)
+	printSingleAssertion("ListsCalled", results.ListsCalled)
+	printSingleAssertion("ListedBeforeUse", results.ListedBeforeUse)
+	printSingleAssertion("ResourcesSubscribed", results.ResourcesSubscribed)
+	printSingleAssertion("ResourcesNotSubscribed", results.ResourcesNotSubscribed)
+	printSingleAssertion("ToolOutputSchemaValid", results.ToolOutputSchemaValid)
+	printSingleAssertion("CommandsRun", results.CommandsRun)
+	printSingleAssertion("CommandsNotRun", results.CommandsNotRun)
+	printSingleAssertion("NoDeniedPermissions", results.NoDeniedPermissions)
+	printSingleAssertion("PermissionsNotRequested", results.PermissionsNotRequested)
 }
 
 func printSingleAssertion(name string, result *eval.SingleAssertionResult) {
@@ -684,11 +740,33 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%dh%dm%ds", hours, minutes, seconds)
 }
 
+// displayVariantSummary shows pass rates per toolOverrides variant
+func displayVariantSummary(evalResults []*eval.EvalResult) {
+	variants := results.CalculateVariantStats("", evalResults)
+	if len(variants) == 0 {
+		return
+	}
+
+	fmt.Println()
+	color.New(color.Bold).Println("=== Variant Summary ===")
+	fmt.Printf("%-30s %-20s %-20s %s\n", "Variant", "Tasks", "Assertions", "MCP Schema Tokens")
+	fmt.Println(strings.Repeat("-", 90))
+
+	for _, v := range variants {
+		fmt.Printf("%-30s %-20s %-20s ~%d\n",
+			v.Variant,
+			fmt.Sprintf("%d/%d (%.1f%%)", v.TasksPassed, v.TasksTotal, v.TaskPassRate*100),
+			fmt.Sprintf("%d/%d (%.1f%%)", v.AssertionsPassed, v.AssertionsTotal, v.AssertionPassRate*100),
+			v.McpSchemaTokens,
+		)
+	}
+}
+
 // displayConsistencySummary shows pass rates when tasks are run multiple times
-func displayConsistencySummary(results []*eval.EvalResult) {
+func displayConsistencySummary(evalResults []*eval.EvalResult) {
 	// Check if any task has multiple runs
 	hasMultiRun := false
-	for _, r := range results {
+	for _, r := range evalResults {
 		if r.TotalRuns > 1 {
 			hasMultiRun = true
 			break
@@ -711,10 +789,10 @@ func displayConsistencySummary(results []*eval.EvalResult) {
 	}
 	agg := make(map[string]*taskAgg)
 
-	for _, r := range results {
-		key := r.TaskPath
+	for _, r := range evalResults {
+		key := r.TaskPath + "\x00" + r.Variant
 		if agg[key] == nil {
-			agg[key] = &taskAgg{taskName: r.TaskName}
+			agg[key] = &taskAgg{taskName: results.TaskLabel(r)}
 		}
 		a := agg[key]
 		a.totalRuns++
@@ -26,13 +26,19 @@ type SummaryOutput struct {
 	AgentTotalOutputTokens int64         `json:"agentTotalOutputTokens"`
 	JudgeTotalInputTokens  int64         `json:"judgeTotalInputTokens"`
 	JudgeTotalOutputTokens int64         `json:"judgeTotalOutputTokens"`
+	InfraFailures          int           `json:"infraFailures"`                   // tasks that failed with an infra failure
+	InfraFailuresExcluded  int           `json:"infraFailuresExcluded,omitempty"` // infra failures left out with --exclude-infra
+
+	Variants []results.VariantStats `json:"variants,omitempty"` // per toolOverrides variant
 }
 
 type TaskSummary struct {
 	Name              string   `json:"name"`
+	Variant           string   `json:"variant,omitempty"`
 	TaskPassed        bool     `json:"taskPassed"`
 	AssertionsPassed  bool     `json:"assertionsPassed"`
 	TaskError         string   `json:"taskError,omitempty"`
+	FailureKind       string   `json:"failureKind,omitempty"`
 	FailedAssertions  []string `json:"failedAssertions,omitempty"`
 	TokensEstimated   int64    `json:"tokensEstimated,omitempty"`
 	McpSchemaTokens   int64    `json:"mcpSchemaTokens,omitempty"`
@@ -47,6 +53,7 @@ func NewSummaryCmd() *cobra.Command {
 	var taskFilter string
 	var outputFormat string
 	var githubOutput bool
+	var excludeInfra bool
 
 	cmd := &cobra.Command{
 		Use:   "summary <results-file>",
@@ -56,7 +63,10 @@ func NewSummaryCmd() *cobra.Command {
 Supports multiple output formats:
   - text (default): Human-readable summary with colors
   - json: Machine-readable JSON output
-  - --github-output: GitHub Actions format (key=value)`,
+  - --github-output: GitHub Actions format (key=value)
+
+Use --exclude-infra to leave tasks that failed with an infra failure,
+e.g. a rate limited model API, out of the summary and its pass rates.`,
 		Args:         cobra.ExactArgs(1),
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -71,7 +81,13 @@ Supports multiple output formats:
 				evalResults = results.Filter(evalResults, taskFilter)
 			}
 
+			excluded := 0
+			if excludeInfra {
+				evalResults, excluded = results.ExcludeInfraFailures(evalResults)
+			}
+
 			summary := buildSummaryOutput(resultsFile, evalResults)
+			summary.InfraFailuresExcluded = excluded
 
 			if githubOutput {
 				outputGitHubSummary(summary)
@@ -94,6 +110,7 @@ Supports multiple output formats:
 	cmd.Flags().StringVar(&taskFilter, "task", "", "Filter results by task name")
 	cmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Output format (text, json)")
 	cmd.Flags().BoolVar(&githubOutput, "github-output", false, "Output in GitHub Actions format (key=value)")
+	cmd.Flags().BoolVar(&excludeInfra, "exclude-infra", false, "Exclude tasks that failed with an infra failure from the summary and pass rates")
 
 	return cmd
 }
@@ -108,8 +125,10 @@ func buildSummaryOutput(resultsFile string, evalResults []*eval.EvalResult) Summ
 	for _, result := range evalResults {
 		taskSummary := TaskSummary{
 			Name:             result.TaskName,
+			Variant:          result.Variant,
 			TaskPassed:       result.TaskPassed,
 			AssertionsPassed: result.AllAssertionsPassed,
+			FailureKind:      string(result.FailureKind),
 		}
 
 		if result.TaskPassed {
@@ -118,7 +137,13 @@ func buildSummaryOutput(resultsFile string, evalResults []*eval.EvalResult) Summ
 
 		// Collect task error
 		if !result.TaskPassed {
-			if result.AgentExecutionError {
+			if result.FailureKind == eval.FailureInfra {
+				summary.InfraFailures++
+			}
+
+			if result.AgentExecutionError && result.FailureKind == eval.FailureInfra {
+				taskSummary.TaskError = "Agent execution failed (infra error)"
+			} else if result.AgentExecutionError {
 				taskSummary.TaskError = "Agent execution failed"
 			} else if result.TaskError != "" {
 				taskSummary.TaskError = result.TaskError
@@ -173,6 +198,8 @@ func buildSummaryOutput(resultsFile string, evalResults []*eval.EvalResult) Summ
 		summary.AssertionPassRate = float64(summary.AssertionsPassed) / float64(summary.AssertionsTotal)
 	}
 
+	summary.Variants = results.CalculateVariantStats(resultsFile, evalResults)
+
 	return summary
 }
 
@@ -200,11 +227,11 @@ func outputTextSummary(evalResults []*eval.EvalResult, summary SummaryOutput) {
 
 		// Print task line
 		if passed {
-			green.Printf("  ✓ %s", result.TaskName)
+			green.Printf("  ✓ %s", results.TaskLabel(result))
 		} else if result.TaskPassed && !result.AllAssertionsPassed {
-			yellow.Printf("  ~ %s", result.TaskName)
+			yellow.Printf("  ~ %s", results.TaskLabel(result))
 		} else {
-			red.Printf("  ✗ %s", result.TaskName)
+			red.Printf("  ✗ %s", results.TaskLabel(result))
 		}
 
 		// Print assertion count if any
@@ -230,6 +257,15 @@ func outputTextSummary(evalResults []*eval.EvalResult, summary SummaryOutput) {
 		summary.TasksPassed, summary.TasksTotal, summary.TaskPassRate*100)
 	fmt.Printf("Assertions: %d/%d passed (%.2f%%)\n",
 		summary.AssertionsPassed, summary.AssertionsTotal, summary.AssertionPassRate*100)
+	if summary.InfraFailuresExcluded > 0 {
+		fmt.Printf("Infra:      %d failures excluded\n", summary.InfraFailuresExcluded)
+	} else if summary.InfraFailures > 0 {
+		yellow.Printf("Infra:      %d failures (use --exclude-infra to exclude them)\n", summary.InfraFailures)
+	}
+	for _, v := range summary.Variants {
+		fmt.Printf("  %s: %d/%d tasks, %d/%d assertions passed\n",
+			v.Variant, v.TasksPassed, v.TasksTotal, v.AssertionsPassed, v.AssertionsTotal)
+	}
 	// Check if any task had token errors
 	hasTokenErrors := false
 	for _, task := range summary.Tasks {
@@ -283,6 +319,8 @@ func outputGitHubSummary(summary SummaryOutput) {
 	fmt.Printf("assertions-total=%d\n", summary.AssertionsTotal)
 	fmt.Printf("assertions-passed=%d\n", summary.AssertionsPassed)
 	fmt.Printf("assertion-pass-rate=%.4f\n", summary.AssertionPassRate)
+	fmt.Printf("infra-failures=%d\n", summary.InfraFailures)
+	fmt.Printf("infra-failures-excluded=%d\n", summary.InfraFailuresExcluded)
 	fmt.Printf("tokens-estimated=%d\n", summary.TotalTokensEstimate)
 	fmt.Printf("mcp-schema-tokens=%d\n", summary.TotalMcpSchemaTokens)
 	fmt.Printf("agent-input-tokens=%d\n", summary.AgentTotalInputTokens)
@@ -132,6 +132,67 @@ func TestBuildSummaryOutput(t *testing.T) {
 	}
 }
 
+func TestBuildSummaryOutputInfraFailures(t *testing.T) {
+	results := append(sampleResults(), &eval.EvalResult{
+		TaskName:            "task-4",
+		TaskPassed:          false,
+		TaskError:           "failed to run agent: 503 Service Unavailable",
+		AgentExecutionError: true,
+		FailureKind:         eval.FailureInfra,
+	})
+
+	summary := buildSummaryOutput("test.json", results)
+
+	if summary.InfraFailures != 1 {
+		t.Errorf("InfraFailures = %d, want 1", summary.InfraFailures)
+	}
+	if summary.Tasks[3].FailureKind != "infra" {
+		t.Errorf("Tasks[3].FailureKind = %s, want infra", summary.Tasks[3].FailureKind)
+	}
+	if summary.Tasks[3].TaskError != "Agent execution failed (infra error)" {
+		t.Errorf("Tasks[3].TaskError = %s, want Agent execution failed (infra error)", summary.Tasks[3].TaskError)
+	}
+	if summary.TaskPassRate != 0.5 {
+		t.Errorf("TaskPassRate = %f, want 0.5", summary.TaskPassRate)
+	}
+}
+
+func TestSummaryCommandExcludeInfra(t *testing.T) {
+	results := append(sampleResults(), &eval.EvalResult{
+		TaskName:            "task-4",
+		TaskPassed:          false,
+		AgentExecutionError: true,
+		FailureKind:         eval.FailureInfra,
+	})
+	filePath := createTestResultsFile(t, results)
+
+	cmd := NewSummaryCmd()
+	cmd.SetArgs([]string{filePath, "--exclude-infra", "--github-output"})
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := cmd.Execute()
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	if err != nil {
+		t.Fatalf("summary command failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.ReadFrom(r)
+	output := buf.String()
+
+	for _, expected := range []string{"tasks-total=3", "tasks-passed=2", "infra-failures=0", "infra-failures-excluded=1"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("output missing expected line %q\nGot:\n%s", expected, output)
+		}
+	}
+}
+
 func TestOutputTextSummary(t *testing.T) {
 	results := sampleResults()
 	summary := buildSummaryOutput("test.json", results)
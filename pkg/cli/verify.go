@@ -13,6 +13,7 @@ import (
 func NewVerifyCmd() *cobra.Command {
 	var taskThreshold float64
 	var assertionThreshold float64
+	var excludeInfra bool
 
 	cmd := &cobra.Command{
 		Use:   "verify <results-file>",
@@ -21,6 +22,8 @@ func NewVerifyCmd() *cobra.Command {
 Useful as a CI gate to enforce quality standards.
 
 Exits with code 0 if all thresholds are met, code 1 otherwise.
+Use --exclude-infra to leave tasks that failed with an infra failure,
+e.g. a rate limited model API, out of the pass rates.
 Use 'mcpchecker result summary' to view detailed results.`,
 		Args:          cobra.ExactArgs(1),
 		SilenceUsage:  true,
@@ -33,6 +36,11 @@ Use 'mcpchecker result summary' to view detailed results.`,
 				return fmt.Errorf("failed to load results file: %w", err)
 			}
 
+			excluded := 0
+			if excludeInfra {
+				evalResults, excluded = results.ExcludeInfraFailures(evalResults)
+			}
+
 			stats := results.CalculateStats(resultsFile, evalResults)
 
 			taskThresholdMet := stats.TaskPassRate >= taskThreshold
@@ -40,7 +48,7 @@ Use 'mcpchecker result summary' to view detailed results.`,
 			assertionThresholdMet := stats.AssertionsTotal == 0 || stats.AssertionPassRate >= assertionThreshold
 			passed := taskThresholdMet && assertionThresholdMet
 
-			outputVerifyResults(stats, taskThreshold, assertionThreshold, taskThresholdMet, assertionThresholdMet, passed)
+			outputVerifyResults(stats, excluded, taskThreshold, assertionThreshold, taskThresholdMet, assertionThresholdMet, passed)
 
 			if !passed {
 				// silent error (SilenceErrors: true), sets exit code 1
@@ -53,11 +61,12 @@ Use 'mcpchecker result summary' to view detailed results.`,
 
 	cmd.Flags().Float64Var(&taskThreshold, "task", 0.0, "Minimum task pass rate (0.0-1.0)")
 	cmd.Flags().Float64Var(&assertionThreshold, "assertion", 0.0, "Minimum assertion pass rate (0.0-1.0)")
+	cmd.Flags().BoolVar(&excludeInfra, "exclude-infra", false, "Exclude tasks that failed with an infra failure from the pass rates")
 
 	return cmd
 }
 
-func outputVerifyResults(stats results.Stats, taskThreshold, assertionThreshold float64, taskMet, assertionMet, passed bool) {
+func outputVerifyResults(stats results.Stats, infraExcluded int, taskThreshold, assertionThreshold float64, taskMet, assertionMet, passed bool) {
 	green := color.New(color.FgGreen)
 	red := color.New(color.FgRed)
 	bold := color.New(color.Bold)
@@ -85,6 +94,12 @@ func outputVerifyResults(stats results.Stats, taskThreshold, assertionThreshold
 			stats.AssertionPassRate*100, assertionThreshold*100)
 	}
 
+	if infraExcluded > 0 {
+		fmt.Printf("Infra Failures:      %d excluded from pass rates\n", infraExcluded)
+	} else if stats.InfraFailures > 0 {
+		fmt.Printf("Infra Failures:      %d (use --exclude-infra to exclude them)\n", stats.InfraFailures)
+	}
+
 	fmt.Println()
 	if passed {
 		_, _ = green.Println("Result: PASSED")
@@ -202,3 +202,28 @@ func TestVerifyCommandAllPassed(t *testing.T) {
 	}
 }
 
+
+func TestVerifyCommandExcludeInfra(t *testing.T) {
+	results := append(sampleResults(), &eval.EvalResult{
+		TaskName:            "task-4",
+		TaskPassed:          false,
+		TaskError:           "failed to run agent: 429 Too Many Requests (after 3 attempts)",
+		AgentExecutionError: true,
+		FailureKind:         eval.FailureInfra,
+	})
+	filePath := createTestResultsFile(t, results)
+
+	// Task pass rate is 2/4 = 0.5 with the infra failure
+	cmd := NewVerifyCmd()
+	cmd.SetArgs([]string{filePath, "--task", "0.6"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("check command should fail when the infra failure counts")
+	}
+
+	// Task pass rate is 2/3 ≈ 0.667 without it
+	cmd = NewVerifyCmd()
+	cmd.SetArgs([]string{filePath, "--task", "0.6", "--exclude-infra"})
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("check command should pass when infra failures are excluded, got error: %v", err)
+	}
+}
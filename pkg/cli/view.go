@@ -268,8 +268,18 @@ func printCallHistory(history *mcpproxy.CallHistory, opts viewOptions) {
 	toolCalls := len(history.ToolCalls)
 	resourceReads := len(history.ResourceReads)
 	promptGets := len(history.PromptGets)
-
-	if toolCalls == 0 && resourceReads == 0 && promptGets == 0 {
+	sampling := len(history.SamplingRequests)
+	elicitations := len(history.Elicitations)
+	listChanges := len(history.ListChanges)
+	listCalls := len(history.ListCalls)
+	completions := len(history.Completions)
+	cancellations := len(history.Cancellations)
+	subscriptions := len(history.Subscriptions)
+	schemaViolations := len(history.OutputSchemaViolations)
+
+	if toolCalls == 0 && resourceReads == 0 && promptGets == 0 && sampling == 0 && elicitations == 0 &&
+		listChanges == 0 && listCalls == 0 && completions == 0 && cancellations == 0 && subscriptions == 0 &&
+		schemaViolations == 0 {
 		return
 	}
 
@@ -286,6 +296,30 @@ func printCallHistory(history *mcpproxy.CallHistory, opts viewOptions) {
 	if promptGets > 0 {
 		fmt.Printf(" prompts=%d", promptGets)
 	}
+	if sampling > 0 {
+		fmt.Printf(" sampling=%d", sampling)
+	}
+	if elicitations > 0 {
+		fmt.Printf(" elicitations=%d", elicitations)
+	}
+	if listChanges > 0 {
+		fmt.Printf(" listChanges=%d", listChanges)
+	}
+	if listCalls > 0 {
+		fmt.Printf(" lists=%d", listCalls)
+	}
+	if completions > 0 {
+		fmt.Printf(" completions=%d", completions)
+	}
+	if cancellations > 0 {
+		fmt.Printf(" cancellations=%d", cancellations)
+	}
+	if subscriptions > 0 {
+		fmt.Printf(" subscriptions=%d", subscriptions)
+	}
+	if schemaViolations > 0 {
+		fmt.Printf(" outputSchemaViolations=%d", schemaViolations)
+	}
 	fmt.Println()
 
 	if toolCalls > 0 {
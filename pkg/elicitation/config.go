@@ -0,0 +1,81 @@
+package elicitation
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	ActionAccept  = "accept"
+	ActionDecline = "decline"
+	ActionCancel  = "cancel"
+)
+
+// Config describes how elicitation requests from MCP servers are answered during a task.
+// Scripted responses are tried first, then the simulated user, then the policy.
+type Config struct {
+	// Responses are scripted answers, the first one whose pattern matches the message is used
+	Responses []Response `json:"responses,omitempty"`
+	// SimulatedUser answers elicitations that no scripted response matches with an LLM
+	SimulatedUser *SimulatedUserConfig `json:"simulatedUser,omitempty"`
+	// Policy is the action for elicitations nothing else answered: accept, decline (default) or cancel
+	Policy string `json:"policy,omitempty"`
+}
+
+// Response is a scripted answer to elicitations whose message matches Match
+type Response struct {
+	// Match is a regex matched against the elicitation message
+	Match string `json:"match"`
+	// Action is accept (default), decline or cancel
+	Action string `json:"action,omitempty"`
+	// Content is returned to the server when the action is accept
+	Content map[string]any `json:"content,omitempty"`
+}
+
+// SimulatedUserConfig configures an LLM playing the user
+type SimulatedUserConfig struct {
+	// Model in "provider:model-id" format, e.g. "openai:gpt-4o-mini"
+	Model string `json:"model"`
+	// Instructions describe how the user behaves, e.g. "you never approve deleting production resources"
+	Instructions string `json:"instructions,omitempty"`
+}
+
+func (c *Config) Validate() error {
+	for i, r := range c.Responses {
+		if r.Match == "" {
+			return fmt.Errorf("responses[%d]: match is required", i)
+		}
+		if _, err := regexp.Compile(r.Match); err != nil {
+			return fmt.Errorf("responses[%d]: invalid regex %q: %w", i, r.Match, err)
+		}
+		if r.Action != "" {
+			if err := validateAction(r.Action); err != nil {
+				return fmt.Errorf("responses[%d]: %w", i, err)
+			}
+		}
+		if len(r.Content) > 0 && r.Action != "" && r.Action != ActionAccept {
+			return fmt.Errorf("responses[%d]: content can only be set when the action is accept", i)
+		}
+	}
+
+	if c.SimulatedUser != nil && c.SimulatedUser.Model == "" {
+		return fmt.Errorf("simulatedUser.model is required")
+	}
+
+	if c.Policy != "" {
+		if err := validateAction(c.Policy); err != nil {
+			return fmt.Errorf("policy: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func validateAction(action string) error {
+	switch action {
+	case ActionAccept, ActionDecline, ActionCancel:
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q: must be one of accept, decline or cancel", action)
+	}
+}
@@ -0,0 +1,151 @@
+package elicitation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"charm.land/fantasy"
+	"github.com/mcpchecker/mcpchecker/pkg/llmagent"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const simulatedUserSystemPrompt = `You are simulating the human user of an AI agent. A tool the agent is using asks you for input.
+Decide how the user responds and reply with only a JSON object of the form:
+{"action": "accept" | "decline" | "cancel", "content": {...}}
+When accepting, content must match the requested schema. Omit content otherwise.`
+
+// Responder answers elicitation requests on behalf of the user
+type Responder struct {
+	responses     []compiledResponse
+	simulatedUser *simulatedUser
+	policy        string
+}
+
+type compiledResponse struct {
+	match   *regexp.Regexp
+	action  string
+	content map[string]any
+}
+
+type simulatedUser struct {
+	model        fantasy.LanguageModel
+	instructions string
+}
+
+// NewResponder creates a responder for cfg, resolving the simulated user model if one is configured
+func NewResponder(ctx context.Context, cfg *Config) (*Responder, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &Responder{policy: cfg.Policy}
+	if r.policy == "" {
+		r.policy = ActionDecline
+	}
+
+	for _, resp := range cfg.Responses {
+		action := resp.Action
+		if action == "" {
+			action = ActionAccept
+		}
+		r.responses = append(r.responses, compiledResponse{
+			match:   regexp.MustCompile(resp.Match),
+			action:  action,
+			content: resp.Content,
+		})
+	}
+
+	if cfg.SimulatedUser != nil {
+		lm, err := llmagent.NewLanguageModel(ctx, cfg.SimulatedUser.Model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create simulated user: %w", err)
+		}
+		r.simulatedUser = &simulatedUser{
+			model:        lm,
+			instructions: cfg.SimulatedUser.Instructions,
+		}
+	}
+
+	return r, nil
+}
+
+// Elicit answers an elicitation request
+func (r *Responder) Elicit(ctx context.Context, params *mcp.ElicitParams) (*mcp.ElicitResult, error) {
+	for _, resp := range r.responses {
+		if !resp.match.MatchString(params.Message) {
+			continue
+		}
+
+		res := &mcp.ElicitResult{Action: resp.action}
+		if resp.action == ActionAccept {
+			res.Content = resp.content
+		}
+		return res, nil
+	}
+
+	if r.simulatedUser != nil {
+		return r.simulatedUser.elicit(ctx, params)
+	}
+
+	return &mcp.ElicitResult{Action: r.policy}, nil
+}
+
+func (u *simulatedUser) elicit(ctx context.Context, params *mcp.ElicitParams) (*mcp.ElicitResult, error) {
+	system := simulatedUserSystemPrompt
+	if u.instructions != "" {
+		system += "\n\n" + u.instructions
+	}
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Message: %s\n", params.Message)
+	if params.RequestedSchema != nil {
+		schema, err := json.Marshal(params.RequestedSchema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal requested schema: %w", err)
+		}
+		fmt.Fprintf(&prompt, "Requested schema: %s\n", schema)
+	}
+
+	res, err := u.model.Generate(ctx, fantasy.Call{
+		Prompt: fantasy.Prompt{
+			fantasy.NewSystemMessage(system),
+			fantasy.NewUserMessage(prompt.String()),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("simulated user failed to respond: %w", err)
+	}
+
+	return parseSimulatedResponse(res.Content.Text())
+}
+
+// parseSimulatedResponse extracts the JSON answer from the model output, tolerating surrounding text
+func parseSimulatedResponse(text string) (*mcp.ElicitResult, error) {
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("simulated user response is not a JSON object: %q", text)
+	}
+
+	var answer struct {
+		Action  string         `json:"action"`
+		Content map[string]any `json:"content,omitempty"`
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), &answer); err != nil {
+		return nil, fmt.Errorf("failed to parse simulated user response: %w", err)
+	}
+
+	if err := validateAction(answer.Action); err != nil {
+		return nil, fmt.Errorf("invalid simulated user response: %w", err)
+	}
+
+	res := &mcp.ElicitResult{Action: answer.Action}
+	if answer.Action == ActionAccept {
+		res.Content = answer.Content
+	}
+
+	return res, nil
+}
@@ -0,0 +1,179 @@
+package elicitation
+
+import (
+	"context"
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeModel struct {
+	fantasy.LanguageModel
+	text string
+	call fantasy.Call
+}
+
+func (m *fakeModel) Generate(ctx context.Context, call fantasy.Call) (*fantasy.Response, error) {
+	m.call = call
+	return &fantasy.Response{Content: fantasy.ResponseContent{fantasy.TextContent{Text: m.text}}}, nil
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		config      Config
+		errContains string
+	}{
+		"empty config is valid": {
+			config: Config{},
+		},
+		"scripted responses and policy": {
+			config: Config{
+				Responses: []Response{{Match: "(?i)delete", Action: ActionAccept, Content: map[string]any{"confirm": true}}},
+				Policy:    ActionCancel,
+			},
+		},
+		"missing match": {
+			config:      Config{Responses: []Response{{Action: ActionAccept}}},
+			errContains: "match is required",
+		},
+		"invalid regex": {
+			config:      Config{Responses: []Response{{Match: "("}}},
+			errContains: "invalid regex",
+		},
+		"unknown action": {
+			config:      Config{Responses: []Response{{Match: ".*", Action: "approve"}}},
+			errContains: `unknown action "approve"`,
+		},
+		"content with decline": {
+			config:      Config{Responses: []Response{{Match: ".*", Action: ActionDecline, Content: map[string]any{"a": 1}}}},
+			errContains: "content can only be set",
+		},
+		"simulated user without model": {
+			config:      Config{SimulatedUser: &SimulatedUserConfig{}},
+			errContains: "simulatedUser.model is required",
+		},
+		"unknown policy": {
+			config:      Config{Policy: "ignore"},
+			errContains: "policy",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errContains)
+		})
+	}
+}
+
+func TestResponderScripted(t *testing.T) {
+	r, err := NewResponder(context.Background(), &Config{
+		Responses: []Response{
+			{Match: "(?i)delete", Content: map[string]any{"confirm": true}},
+			{Match: "production", Action: ActionDecline},
+		},
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		message       string
+		expectAction  string
+		expectContent map[string]any
+	}{
+		"first matching response wins": {
+			message:       "Delete the pod in production?",
+			expectAction:  ActionAccept,
+			expectContent: map[string]any{"confirm": true},
+		},
+		"second response": {
+			message:      "Scale production down?",
+			expectAction: ActionDecline,
+		},
+		"unmatched falls back to decline": {
+			message:      "Restart the node?",
+			expectAction: ActionDecline,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := r.Elicit(context.Background(), &mcp.ElicitParams{Message: tc.message})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectAction, res.Action)
+			assert.Equal(t, tc.expectContent, res.Content)
+		})
+	}
+}
+
+func TestResponderPolicy(t *testing.T) {
+	r, err := NewResponder(context.Background(), &Config{Policy: ActionCancel})
+	require.NoError(t, err)
+
+	res, err := r.Elicit(context.Background(), &mcp.ElicitParams{Message: "continue?"})
+	require.NoError(t, err)
+	assert.Equal(t, ActionCancel, res.Action)
+}
+
+func TestResponderSimulatedUser(t *testing.T) {
+	model := &fakeModel{text: "Sure.\n```json\n{\"action\": \"accept\", \"content\": {\"replicas\": 3}}\n```"}
+	r := &Responder{
+		simulatedUser: &simulatedUser{model: model, instructions: "You want three replicas."},
+		policy:        ActionDecline,
+	}
+
+	res, err := r.Elicit(context.Background(), &mcp.ElicitParams{
+		Message:         "How many replicas?",
+		RequestedSchema: map[string]any{"type": "object"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ActionAccept, res.Action)
+	assert.Equal(t, map[string]any{"replicas": float64(3)}, res.Content)
+
+	require.Len(t, model.call.Prompt, 2)
+	assert.Contains(t, model.call.Prompt[0].Content[0].(fantasy.TextPart).Text, "You want three replicas.")
+	assert.Contains(t, model.call.Prompt[1].Content[0].(fantasy.TextPart).Text, "How many replicas?")
+}
+
+func TestParseSimulatedResponse(t *testing.T) {
+	tests := map[string]struct {
+		text          string
+		expectAction  string
+		expectContent map[string]any
+		errContains   string
+	}{
+		"decline drops content": {
+			text:         `{"action": "decline", "content": {"a": 1}}`,
+			expectAction: ActionDecline,
+		},
+		"no json": {
+			text:        "I would say no",
+			errContains: "not a JSON object",
+		},
+		"invalid action": {
+			text:        `{"action": "maybe"}`,
+			errContains: "invalid simulated user response",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := parseSimulatedResponse(tc.text)
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectAction, res.Action)
+			assert.Equal(t, tc.expectContent, res.Content)
+		})
+	}
+}
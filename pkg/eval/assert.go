@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
 	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
+	"github.com/mcpchecker/mcpchecker/pkg/task"
 )
 
 const (
@@ -21,6 +24,26 @@ const (
 	assertionTypePromptsNotUsed   = "promptsNotUsed"
 	assertionTypeCallOrder        = "callOrder"
 	assertionTypeNoDuplicateCalls = "noDuplicateCalls"
+
+	assertionTypeSamplingUsed       = "samplingUsed"
+	assertionTypeSamplingNotUsed    = "samplingNotUsed"
+	assertionTypeElicitationUsed    = "elicitationUsed"
+	assertionTypeElicitationNotUsed = "elicitationNotUsed"
+	assertionTypeRootsListed        = "rootsListed"
+
+	assertionTypeListsCalled     = "listsCalled"
+	assertionTypeListedBeforeUse = "listedBeforeUse"
+
+	assertionTypeResourcesSubscribed    = "resourcesSubscribed"
+	assertionTypeResourcesNotSubscribed = "resourcesNotSubscribed"
+
+	assertionTypeToolOutputSchemaValid = "toolOutputSchemaValid"
+
+	assertionTypeCommandsRun    = "commandsRun"
+	assertionTypeCommandsNotRun = "commandsNotRun"
+
+	assertionTypeNoDeniedPermissions     = "noDeniedPermissions"
+	assertionTypePermissionsNotRequested = "permissionsNotRequested"
 )
 
 type SingleAssertionResult struct {
@@ -49,13 +72,38 @@ type CompositeAssertionResult struct {
 	PromptsNotUsed   *SingleAssertionResult `json:"promptsNotUsed,omitempty"`
 	CallOrder        *SingleAssertionResult `json:"callOrder,omitempty"`
 	NoDuplicateCalls *SingleAssertionResult `json:"noDuplicateCalls,omitempty"`
+
+	SamplingUsed       *SingleAssertionResult `json:"samplingUsed,omitempty"`
+	SamplingNotUsed    *SingleAssertionResult `json:"samplingNotUsed,omitempty"`
+	ElicitationUsed    *SingleAssertionResult `json:"elicitationUsed,omitempty"`
+	ElicitationNotUsed *SingleAssertionResult `json:"elicitationNotUsed,omitempty"`
+	RootsListed        *SingleAssertionResult `json:"rootsListed,omitempty"`
+
+	ListsCalled     *SingleAssertionResult `json:"listsCalled,omitempty"`
+	ListedBeforeUse *SingleAssertionResult `json:"listedBeforeUse,omitempty"`
+
+	ResourcesSubscribed    *SingleAssertionResult `json:"resourcesSubscribed,omitempty"`
+	ResourcesNotSubscribed *SingleAssertionResult `json:"resourcesNotSubscribed,omitempty"`
+
+	ToolOutputSchemaValid *SingleAssertionResult `json:"toolOutputSchemaValid,omitempty"`
+
+	CommandsRun    *SingleAssertionResult `json:"commandsRun,omitempty"`
+	CommandsNotRun *SingleAssertionResult `json:"commandsNotRun,omitempty"`
+
+	NoDeniedPermissions     *SingleAssertionResult `json:"noDeniedPermissions,omitempty"`
+	PermissionsNotRequested *SingleAssertionResult `json:"permissionsNotRequested,omitempty"`
 }
 
 func (c *CompositeAssertionResult) Succeeded() bool {
 	return c.ToolsUsed.Succeeded() && c.RequireAny.Succeeded() && c.ToolsNotUsed.Succeeded() &&
 		c.MinToolCalls.Succeeded() && c.MaxToolCalls.Succeeded() && c.ResourcesRead.Succeeded() &&
 		c.ResourcesNotRead.Succeeded() && c.PromptsUsed.Succeeded() && c.PromptsNotUsed.Succeeded() &&
-		c.CallOrder.Succeeded() && c.NoDuplicateCalls.Succeeded()
+		c.CallOrder.Succeeded() && c.NoDuplicateCalls.Succeeded() && c.SamplingUsed.Succeeded() &&
+		c.SamplingNotUsed.Succeeded() && c.ElicitationUsed.Succeeded() && c.ElicitationNotUsed.Succeeded() &&
+		c.RootsListed.Succeeded() && c.ListsCalled.Succeeded() && c.ListedBeforeUse.Succeeded() &&
+		c.ResourcesSubscribed.Succeeded() && c.ResourcesNotSubscribed.Succeeded() &&
+		c.ToolOutputSchemaValid.Succeeded() && c.CommandsRun.Succeeded() && c.CommandsNotRun.Succeeded() &&
+		c.NoDeniedPermissions.Succeeded() && c.PermissionsNotRequested.Succeeded()
 }
 
 // TotalAssertions returns the total number of individual assertions that were evaluated
@@ -94,6 +142,48 @@ func (c *CompositeAssertionResult) TotalAssertions() int {
 	if c.NoDuplicateCalls != nil {
 		count++
 	}
+	if c.SamplingUsed != nil {
+		count++
+	}
+	if c.SamplingNotUsed != nil {
+		count++
+	}
+	if c.ElicitationUsed != nil {
+		count++
+	}
+	if c.ElicitationNotUsed != nil {
+		count++
+	}
+	if c.RootsListed != nil {
+		count++
+	}
+	if c.ListsCalled != nil {
+		count++
+	}
+	if c.ListedBeforeUse != nil {
+		count++
+	}
+	if c.ResourcesSubscribed != nil {
+		count++
+	}
+	if c.ResourcesNotSubscribed != nil {
+		count++
+	}
+	if c.ToolOutputSchemaValid != nil {
+		count++
+	}
+	if c.CommandsRun != nil {
+		count++
+	}
+	if c.CommandsNotRun != nil {
+		count++
+	}
+	if c.NoDeniedPermissions != nil {
+		count++
+	}
+	if c.PermissionsNotRequested != nil {
+		count++
+	}
 	return count
 }
 
@@ -133,6 +223,48 @@ func (c *CompositeAssertionResult) PassedAssertions() int {
 	if c.NoDuplicateCalls != nil && c.NoDuplicateCalls.Succeeded() {
 		count++
 	}
+	if c.SamplingUsed != nil && c.SamplingUsed.Succeeded() {
+		count++
+	}
+	if c.SamplingNotUsed != nil && c.SamplingNotUsed.Succeeded() {
+		count++
+	}
+	if c.ElicitationUsed != nil && c.ElicitationUsed.Succeeded() {
+		count++
+	}
+	if c.ElicitationNotUsed != nil && c.ElicitationNotUsed.Succeeded() {
+		count++
+	}
+	if c.RootsListed != nil && c.RootsListed.Succeeded() {
+		count++
+	}
+	if c.ListsCalled != nil && c.ListsCalled.Succeeded() {
+		count++
+	}
+	if c.ListedBeforeUse != nil && c.ListedBeforeUse.Succeeded() {
+		count++
+	}
+	if c.ResourcesSubscribed != nil && c.ResourcesSubscribed.Succeeded() {
+		count++
+	}
+	if c.ResourcesNotSubscribed != nil && c.ResourcesNotSubscribed.Succeeded() {
+		count++
+	}
+	if c.ToolOutputSchemaValid != nil && c.ToolOutputSchemaValid.Succeeded() {
+		count++
+	}
+	if c.CommandsRun != nil && c.CommandsRun.Succeeded() {
+		count++
+	}
+	if c.CommandsNotRun != nil && c.CommandsNotRun.Succeeded() {
+		count++
+	}
+	if c.NoDeniedPermissions != nil && c.NoDeniedPermissions.Succeeded() {
+		count++
+	}
+	if c.PermissionsNotRequested != nil && c.PermissionsNotRequested.Succeeded() {
+		count++
+	}
 	return count
 }
 
@@ -154,7 +286,9 @@ type assertionEvaluator struct {
 	evaluators []SingleAssertionEvaluator
 }
 
-func NewCompositeAssertionEvaluator(assertions *TaskAssertions) CompositeAssertionEvaluator {
+// NewCompositeAssertionEvaluator creates an evaluator for a set of assertions. agentDetails
+// is what the agent did outside of the MCP servers, and may be nil if the agent did not run.
+func NewCompositeAssertionEvaluator(assertions *TaskAssertions, agentDetails *task.AgentDetails) CompositeAssertionEvaluator {
 	evaluators := make([]SingleAssertionEvaluator, 0)
 	if len(assertions.ToolsUsed) > 0 {
 		evaluators = append(evaluators, NewToolsUsedEvaluator(assertions.ToolsUsed))
@@ -200,6 +334,62 @@ func NewCompositeAssertionEvaluator(assertions *TaskAssertions) CompositeAsserti
 		evaluators = append(evaluators, NewNoDuplicateCallsEvaluator())
 	}
 
+	if len(assertions.SamplingUsed) > 0 {
+		evaluators = append(evaluators, NewSamplingUsedEvaluator(assertions.SamplingUsed))
+	}
+
+	if len(assertions.SamplingNotUsed) > 0 {
+		evaluators = append(evaluators, NewSamplingNotUsedEvaluator(assertions.SamplingNotUsed))
+	}
+
+	if len(assertions.ElicitationUsed) > 0 {
+		evaluators = append(evaluators, NewElicitationUsedEvaluator(assertions.ElicitationUsed))
+	}
+
+	if len(assertions.ElicitationNotUsed) > 0 {
+		evaluators = append(evaluators, NewElicitationNotUsedEvaluator(assertions.ElicitationNotUsed))
+	}
+
+	if len(assertions.RootsListed) > 0 {
+		evaluators = append(evaluators, NewRootsListedEvaluator(assertions.RootsListed))
+	}
+
+	if len(assertions.ListsCalled) > 0 {
+		evaluators = append(evaluators, NewListsCalledEvaluator(assertions.ListsCalled))
+	}
+
+	if len(assertions.ListedBeforeUse) > 0 {
+		evaluators = append(evaluators, NewListedBeforeUseEvaluator(assertions.ListedBeforeUse))
+	}
+
+	if len(assertions.ResourcesSubscribed) > 0 {
+		evaluators = append(evaluators, NewResourcesSubscribedEvaluator(assertions.ResourcesSubscribed))
+	}
+
+	if len(assertions.ResourcesNotSubscribed) > 0 {
+		evaluators = append(evaluators, NewResourcesNotSubscribedEvaluator(assertions.ResourcesNotSubscribed))
+	}
+
+	if assertions.ToolOutputSchemaValid {
+		evaluators = append(evaluators, NewToolOutputSchemaValidEvaluator())
+	}
+
+	if len(assertions.CommandsRun) > 0 {
+		evaluators = append(evaluators, NewCommandsRunEvaluator(assertions.CommandsRun, agentCommands(agentDetails)))
+	}
+
+	if len(assertions.CommandsNotRun) > 0 {
+		evaluators = append(evaluators, NewCommandsNotRunEvaluator(assertions.CommandsNotRun, agentCommands(agentDetails)))
+	}
+
+	if assertions.NoDeniedPermissions {
+		evaluators = append(evaluators, NewNoDeniedPermissionsEvaluator(permissionRequests(agentDetails)))
+	}
+
+	if len(assertions.PermissionsNotRequested) > 0 {
+		evaluators = append(evaluators, NewPermissionsNotRequestedEvaluator(assertions.PermissionsNotRequested, permissionRequests(agentDetails)))
+	}
+
 	return &assertionEvaluator{
 		evaluators: evaluators,
 	}
@@ -233,6 +423,34 @@ func (a *assertionEvaluator) Evaluate(history *mcpproxy.CallHistory) *CompositeA
 			res.CallOrder = got
 		case assertionTypeNoDuplicateCalls:
 			res.NoDuplicateCalls = got
+		case assertionTypeSamplingUsed:
+			res.SamplingUsed = got
+		case assertionTypeSamplingNotUsed:
+			res.SamplingNotUsed = got
+		case assertionTypeElicitationUsed:
+			res.ElicitationUsed = got
+		case assertionTypeElicitationNotUsed:
+			res.ElicitationNotUsed = got
+		case assertionTypeRootsListed:
+			res.RootsListed = got
+		case assertionTypeListsCalled:
+			res.ListsCalled = got
+		case assertionTypeListedBeforeUse:
+			res.ListedBeforeUse = got
+		case assertionTypeResourcesSubscribed:
+			res.ResourcesSubscribed = got
+		case assertionTypeResourcesNotSubscribed:
+			res.ResourcesNotSubscribed = got
+		case assertionTypeToolOutputSchemaValid:
+			res.ToolOutputSchemaValid = got
+		case assertionTypeCommandsRun:
+			res.CommandsRun = got
+		case assertionTypeCommandsNotRun:
+			res.CommandsNotRun = got
+		case assertionTypeNoDeniedPermissions:
+			res.NoDeniedPermissions = got
+		case assertionTypePermissionsNotRequested:
+			res.PermissionsNotRequested = got
 		default:
 		}
 	}
@@ -464,6 +682,79 @@ func (e *resourcesNotReadEvaluator) Type() string {
 	return assertionTypeResourcesNotRead
 }
 
+type resourcesSubscribedEvaluator struct {
+	assertions []ResourceAssertion
+}
+
+func NewResourcesSubscribedEvaluator(assertions []ResourceAssertion) SingleAssertionEvaluator {
+	return &resourcesSubscribedEvaluator{
+		assertions: assertions,
+	}
+}
+
+func (e *resourcesSubscribedEvaluator) Evaluate(history *mcpproxy.CallHistory) *SingleAssertionResult {
+	for _, assertion := range e.assertions {
+		found := false
+		for _, sub := range history.Subscriptions {
+			if isSubscribe(sub) && matchesResourceURI(sub.ServerName, sub.URI, assertion) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return &SingleAssertionResult{
+				Passed: false,
+				Reason: fmt.Sprintf("Required resource not subscribed: server=%s, uri=%s, pattern=%s",
+					assertion.Server, assertion.URI, assertion.URIPattern,
+				),
+			}
+		}
+	}
+
+	return &SingleAssertionResult{Passed: true}
+}
+
+func (e *resourcesSubscribedEvaluator) Type() string {
+	return assertionTypeResourcesSubscribed
+}
+
+type resourcesNotSubscribedEvaluator struct {
+	assertions []ResourceAssertion
+}
+
+func NewResourcesNotSubscribedEvaluator(assertions []ResourceAssertion) SingleAssertionEvaluator {
+	return &resourcesNotSubscribedEvaluator{
+		assertions: assertions,
+	}
+}
+
+func (e *resourcesNotSubscribedEvaluator) Evaluate(history *mcpproxy.CallHistory) *SingleAssertionResult {
+	for _, assertion := range e.assertions {
+		for _, sub := range history.Subscriptions {
+			if isSubscribe(sub) && matchesResourceURI(sub.ServerName, sub.URI, assertion) {
+				return &SingleAssertionResult{
+					Passed: false,
+					Reason: fmt.Sprintf("Forbidden resource subscription: server=%s, uri=%s",
+						assertion.Server, sub.URI,
+					),
+				}
+			}
+		}
+	}
+
+	return &SingleAssertionResult{Passed: true}
+}
+
+func (e *resourcesNotSubscribedEvaluator) Type() string {
+	return assertionTypeResourcesNotSubscribed
+}
+
+// isSubscribe reports whether sub is a successful subscribe request
+func isSubscribe(sub *mcpproxy.Subscription) bool {
+	return sub != nil && !sub.Unsubscribe && sub.Success
+}
+
 type promptsUsedEvaluator struct {
 	assertions []PromptAssertion
 }
@@ -639,6 +930,496 @@ func (e *noDuplicateCallsEvaluator) Type() string {
 	return assertionTypeNoDuplicateCalls
 }
 
+type toolOutputSchemaValidEvaluator struct{}
+
+func NewToolOutputSchemaValidEvaluator() SingleAssertionEvaluator {
+	return &toolOutputSchemaValidEvaluator{}
+}
+
+func (e *toolOutputSchemaValidEvaluator) Evaluate(history *mcpproxy.CallHistory) *SingleAssertionResult {
+	if len(history.OutputSchemaViolations) == 0 {
+		return &SingleAssertionResult{Passed: true}
+	}
+
+	details := make([]string, 0, len(history.OutputSchemaViolations))
+	for _, v := range history.OutputSchemaViolations {
+		details = append(details, fmt.Sprintf("%s.%s: %s", v.ServerName, v.ToolName, v.Error))
+	}
+
+	first := history.OutputSchemaViolations[0]
+	return &SingleAssertionResult{
+		Passed:  false,
+		Reason:  fmt.Sprintf("%d tool results did not match their outputSchema, first: %s.%s", len(details), first.ServerName, first.ToolName),
+		Details: details,
+	}
+}
+
+func (e *toolOutputSchemaValidEvaluator) Type() string {
+	return assertionTypeToolOutputSchemaValid
+}
+
+// agentCommands returns the command lines the agent ran, in terminals of the client
+// and with its own tools of kind execute
+func agentCommands(details *task.AgentDetails) []string {
+	if details == nil {
+		return nil
+	}
+
+	var commands []string
+	for _, cmd := range details.TerminalCommands {
+		commands = append(commands, cmd.CommandLine())
+	}
+
+	for _, tc := range details.ToolCalls {
+		if tc.Kind != "execute" {
+			continue
+		}
+		command := tc.Title
+		if input, ok := tc.RawInput.(map[string]any); ok {
+			switch c := input["command"].(type) {
+			case string:
+				command = c
+			case []any:
+				parts := make([]string, 0, len(c))
+				for _, p := range c {
+					parts = append(parts, fmt.Sprint(p))
+				}
+				command = strings.Join(parts, " ")
+			}
+		}
+		commands = append(commands, command)
+	}
+
+	return commands
+}
+
+type commandsRunEvaluator struct {
+	assertions []CommandAssertion
+	commands   []string
+}
+
+func NewCommandsRunEvaluator(assertions []CommandAssertion, commands []string) SingleAssertionEvaluator {
+	return &commandsRunEvaluator{
+		assertions: assertions,
+		commands:   commands,
+	}
+}
+
+func (e *commandsRunEvaluator) Evaluate(history *mcpproxy.CallHistory) *SingleAssertionResult {
+	for _, assertion := range e.assertions {
+		found := false
+		for _, cmd := range e.commands {
+			if matched, _ := regexp.MatchString(assertion.Pattern, cmd); matched {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return &SingleAssertionResult{
+				Passed: false,
+				Reason: fmt.Sprintf("Required command not run: pattern=%s", assertion.Pattern),
+			}
+		}
+	}
+
+	return &SingleAssertionResult{Passed: true}
+}
+
+func (e *commandsRunEvaluator) Type() string {
+	return assertionTypeCommandsRun
+}
+
+type commandsNotRunEvaluator struct {
+	assertions []CommandAssertion
+	commands   []string
+}
+
+func NewCommandsNotRunEvaluator(assertions []CommandAssertion, commands []string) SingleAssertionEvaluator {
+	return &commandsNotRunEvaluator{
+		assertions: assertions,
+		commands:   commands,
+	}
+}
+
+func (e *commandsNotRunEvaluator) Evaluate(history *mcpproxy.CallHistory) *SingleAssertionResult {
+	for _, assertion := range e.assertions {
+		for _, cmd := range e.commands {
+			if matched, _ := regexp.MatchString(assertion.Pattern, cmd); matched {
+				return &SingleAssertionResult{
+					Passed: false,
+					Reason: fmt.Sprintf("Forbidden command was run: %s (pattern=%s)", cmd, assertion.Pattern),
+				}
+			}
+		}
+	}
+
+	return &SingleAssertionResult{Passed: true}
+}
+
+func (e *commandsNotRunEvaluator) Type() string {
+	return assertionTypeCommandsNotRun
+}
+
+func permissionRequests(details *task.AgentDetails) []acpclient.PermissionRequest {
+	if details == nil {
+		return nil
+	}
+	return details.PermissionRequests
+}
+
+// describePermissionRequest names the tool of a permission request in assertion reasons
+func describePermissionRequest(req acpclient.PermissionRequest) string {
+	if req.Server != "" {
+		return fmt.Sprintf("server=%s, tool=%s", req.Server, req.Tool)
+	}
+	return fmt.Sprintf("title=%q", req.Title)
+}
+
+type noDeniedPermissionsEvaluator struct {
+	requests []acpclient.PermissionRequest
+}
+
+func NewNoDeniedPermissionsEvaluator(requests []acpclient.PermissionRequest) SingleAssertionEvaluator {
+	return &noDeniedPermissionsEvaluator{
+		requests: requests,
+	}
+}
+
+func (e *noDeniedPermissionsEvaluator) Evaluate(history *mcpproxy.CallHistory) *SingleAssertionResult {
+	var denied []string
+	for _, req := range e.requests {
+		if req.Decision == acpclient.PermissionDeny {
+			denied = append(denied, describePermissionRequest(req))
+		}
+	}
+
+	if len(denied) > 0 {
+		return &SingleAssertionResult{
+			Passed:  false,
+			Reason:  fmt.Sprintf("Agent requested permission for %d denied tool call(s), first: %s", len(denied), denied[0]),
+			Details: denied,
+		}
+	}
+
+	return &SingleAssertionResult{Passed: true}
+}
+
+func (e *noDeniedPermissionsEvaluator) Type() string {
+	return assertionTypeNoDeniedPermissions
+}
+
+type permissionsNotRequestedEvaluator struct {
+	assertions []PermissionAssertion
+	requests   []acpclient.PermissionRequest
+}
+
+func NewPermissionsNotRequestedEvaluator(assertions []PermissionAssertion, requests []acpclient.PermissionRequest) SingleAssertionEvaluator {
+	return &permissionsNotRequestedEvaluator{
+		assertions: assertions,
+		requests:   requests,
+	}
+}
+
+func (e *permissionsNotRequestedEvaluator) Evaluate(history *mcpproxy.CallHistory) *SingleAssertionResult {
+	for _, assertion := range e.assertions {
+		for _, req := range e.requests {
+			if matchesPermissionAssertion(req, assertion) {
+				return &SingleAssertionResult{
+					Passed: false,
+					Reason: fmt.Sprintf("Forbidden permission was requested: %s", describePermissionRequest(req)),
+				}
+			}
+		}
+	}
+
+	return &SingleAssertionResult{Passed: true}
+}
+
+func (e *permissionsNotRequestedEvaluator) Type() string {
+	return assertionTypePermissionsNotRequested
+}
+
+// serverRequest is the assertable view of a request initiated by an MCP server
+type serverRequest struct {
+	server string
+	texts  []string
+	// elicitation requests also match on the action they were answered with
+	elicitation bool
+	action      string
+}
+
+func samplingRequests(history *mcpproxy.CallHistory) []serverRequest {
+	reqs := make([]serverRequest, 0, len(history.SamplingRequests))
+	for _, sr := range history.SamplingRequests {
+		reqs = append(reqs, serverRequest{server: sr.ServerName, texts: []string{sr.Text()}})
+	}
+	return reqs
+}
+
+func elicitationRequests(history *mcpproxy.CallHistory) []serverRequest {
+	reqs := make([]serverRequest, 0, len(history.Elicitations))
+	for _, e := range history.Elicitations {
+		reqs = append(reqs, serverRequest{server: e.ServerName, texts: []string{e.Message}, elicitation: true, action: e.Action})
+	}
+	return reqs
+}
+
+func rootsListRequests(history *mcpproxy.CallHistory) []serverRequest {
+	reqs := make([]serverRequest, 0, len(history.RootsLists))
+	for _, rl := range history.RootsLists {
+		req := serverRequest{server: rl.ServerName}
+		if rl.Result != nil {
+			for _, root := range rl.Result.Roots {
+				req.texts = append(req.texts, root.URI)
+			}
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+type serverRequestsUsedEvaluator struct {
+	assertionType string
+	kind          string
+	assertions    []ServerRequestAssertion
+	requests      func(history *mcpproxy.CallHistory) []serverRequest
+}
+
+func NewSamplingUsedEvaluator(assertions []ServerRequestAssertion) SingleAssertionEvaluator {
+	return &serverRequestsUsedEvaluator{
+		assertionType: assertionTypeSamplingUsed,
+		kind:          "sampling request",
+		assertions:    assertions,
+		requests:      samplingRequests,
+	}
+}
+
+func NewElicitationUsedEvaluator(assertions []ServerRequestAssertion) SingleAssertionEvaluator {
+	return &serverRequestsUsedEvaluator{
+		assertionType: assertionTypeElicitationUsed,
+		kind:          "elicitation request",
+		assertions:    assertions,
+		requests:      elicitationRequests,
+	}
+}
+
+func NewRootsListedEvaluator(assertions []ServerRequestAssertion) SingleAssertionEvaluator {
+	return &serverRequestsUsedEvaluator{
+		assertionType: assertionTypeRootsListed,
+		kind:          "roots list request",
+		assertions:    assertions,
+		requests:      rootsListRequests,
+	}
+}
+
+func (e *serverRequestsUsedEvaluator) Evaluate(history *mcpproxy.CallHistory) *SingleAssertionResult {
+	requests := e.requests(history)
+	for _, assertion := range e.assertions {
+		found := false
+		for _, req := range requests {
+			if matchesServerRequestAssertion(req, assertion) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return &SingleAssertionResult{
+				Passed: false,
+				Reason: fmt.Sprintf("Required %s not made: server=%s, pattern=%s",
+					e.kind, assertion.Server, assertion.Pattern,
+				),
+			}
+		}
+	}
+
+	return &SingleAssertionResult{Passed: true}
+}
+
+func (e *serverRequestsUsedEvaluator) Type() string {
+	return e.assertionType
+}
+
+type serverRequestsNotUsedEvaluator struct {
+	assertionType string
+	kind          string
+	assertions    []ServerRequestAssertion
+	requests      func(history *mcpproxy.CallHistory) []serverRequest
+}
+
+func NewSamplingNotUsedEvaluator(assertions []ServerRequestAssertion) SingleAssertionEvaluator {
+	return &serverRequestsNotUsedEvaluator{
+		assertionType: assertionTypeSamplingNotUsed,
+		kind:          "sampling request",
+		assertions:    assertions,
+		requests:      samplingRequests,
+	}
+}
+
+func NewElicitationNotUsedEvaluator(assertions []ServerRequestAssertion) SingleAssertionEvaluator {
+	return &serverRequestsNotUsedEvaluator{
+		assertionType: assertionTypeElicitationNotUsed,
+		kind:          "elicitation request",
+		assertions:    assertions,
+		requests:      elicitationRequests,
+	}
+}
+
+func (e *serverRequestsNotUsedEvaluator) Evaluate(history *mcpproxy.CallHistory) *SingleAssertionResult {
+	requests := e.requests(history)
+	for _, assertion := range e.assertions {
+		for _, req := range requests {
+			if matchesServerRequestAssertion(req, assertion) {
+				return &SingleAssertionResult{
+					Passed: false,
+					Reason: fmt.Sprintf("Forbidden %s made: server=%s, pattern=%s",
+						e.kind, req.server, assertion.Pattern,
+					),
+				}
+			}
+		}
+	}
+
+	return &SingleAssertionResult{Passed: true}
+}
+
+func (e *serverRequestsNotUsedEvaluator) Type() string {
+	return e.assertionType
+}
+
+func matchesServerRequestAssertion(req serverRequest, assertion ServerRequestAssertion) bool {
+	if req.server != assertion.Server {
+		return false
+	}
+
+	if req.elicitation && assertion.Action != "" && req.action != assertion.Action {
+		return false
+	}
+
+	// if no pattern specified, match any request from this server
+	if assertion.Pattern == "" {
+		return true
+	}
+
+	for _, text := range req.texts {
+		if matched, _ := regexp.MatchString(assertion.Pattern, text); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+type listsCalledEvaluator struct {
+	assertions []ListAssertion
+}
+
+func NewListsCalledEvaluator(assertions []ListAssertion) SingleAssertionEvaluator {
+	return &listsCalledEvaluator{
+		assertions: assertions,
+	}
+}
+
+func (e *listsCalledEvaluator) Evaluate(history *mcpproxy.CallHistory) *SingleAssertionResult {
+	for _, assertion := range e.assertions {
+		if firstListCall(history, assertion) == nil {
+			return &SingleAssertionResult{
+				Passed: false,
+				Reason: fmt.Sprintf("Required list not requested: server=%s, list=%s", assertion.Server, assertion.List),
+			}
+		}
+	}
+
+	return &SingleAssertionResult{Passed: true}
+}
+
+func (e *listsCalledEvaluator) Type() string {
+	return assertionTypeListsCalled
+}
+
+type listedBeforeUseEvaluator struct {
+	assertions []ListAssertion
+}
+
+func NewListedBeforeUseEvaluator(assertions []ListAssertion) SingleAssertionEvaluator {
+	return &listedBeforeUseEvaluator{
+		assertions: assertions,
+	}
+}
+
+// Evaluate checks that the agent listed the tools, prompts or resources of a server
+// before it first used one of them
+func (e *listedBeforeUseEvaluator) Evaluate(history *mcpproxy.CallHistory) *SingleAssertionResult {
+	for _, assertion := range e.assertions {
+		name, usedAt, used := firstUse(history, assertion)
+		if !used {
+			continue
+		}
+
+		listed := firstListCall(history, assertion)
+		if listed == nil || !listed.Timestamp.Before(usedAt) {
+			return &SingleAssertionResult{
+				Passed: false,
+				Reason: fmt.Sprintf("%s was used before the agent listed %s: server=%s",
+					name, assertion.List, assertion.Server,
+				),
+			}
+		}
+	}
+
+	return &SingleAssertionResult{Passed: true}
+}
+
+func (e *listedBeforeUseEvaluator) Type() string {
+	return assertionTypeListedBeforeUse
+}
+
+// firstListCall returns the earliest successful list call matching the assertion
+func firstListCall(history *mcpproxy.CallHistory, assertion ListAssertion) *mcpproxy.ListCall {
+	var first *mcpproxy.ListCall
+	for _, call := range history.ListCalls {
+		if call.ServerName != assertion.Server || call.List() != assertion.List || !call.Success {
+			continue
+		}
+		if first == nil || call.Timestamp.Before(first.Timestamp) {
+			first = call
+		}
+	}
+
+	return first
+}
+
+// firstUse returns the name and time of the earliest use of an item from the asserted list
+func firstUse(history *mcpproxy.CallHistory, assertion ListAssertion) (string, time.Time, bool) {
+	var name string
+	var first time.Time
+	used := false
+	use := func(server, n string, at time.Time) {
+		if server != assertion.Server || (used && !at.Before(first)) {
+			return
+		}
+		name, first, used = n, at, true
+	}
+
+	switch assertion.List {
+	case mcpproxy.ListTools:
+		for _, call := range history.ToolCalls {
+			use(call.ServerName, "tool "+call.ToolName, call.Timestamp)
+		}
+	case mcpproxy.ListPrompts:
+		for _, call := range history.PromptGets {
+			use(call.ServerName, "prompt "+call.Name, call.Timestamp)
+		}
+	case mcpproxy.ListResources:
+		for _, call := range history.ResourceReads {
+			use(call.ServerName, "resource "+call.URI, call.Timestamp)
+		}
+	}
+
+	return name, first, used
+}
+
 func matchesToolAssertion(call *mcpproxy.ToolCall, assertion ToolAssertion) bool {
 	if call == nil {
 		return false
@@ -665,12 +1446,43 @@ func matchesToolAssertion(call *mcpproxy.ToolCall, assertion ToolAssertion) bool
 	return false
 }
 
+func matchesPermissionAssertion(req acpclient.PermissionRequest, assertion PermissionAssertion) bool {
+	if assertion.Server != "" && req.Server != assertion.Server {
+		return false
+	}
+
+	if assertion.Kind != "" && req.Kind != assertion.Kind {
+		return false
+	}
+
+	// MCP tools are matched by name, the agent's own tools by title
+	name := req.Title
+	if req.Server != "" {
+		name = req.Tool
+	}
+
+	if assertion.Tool != "" && name != assertion.Tool {
+		return false
+	}
+
+	if assertion.ToolPattern != "" {
+		matched, _ := regexp.MatchString(assertion.ToolPattern, name)
+		return matched
+	}
+
+	return true
+}
+
 func matchesResourceAssertion(call *mcpproxy.ResourceRead, assertion ResourceAssertion) bool {
 	if call == nil {
 		return false
 	}
 
-	if call.ServerName != assertion.Server {
+	return matchesResourceURI(call.ServerName, call.URI, assertion)
+}
+
+func matchesResourceURI(server, uri string, assertion ResourceAssertion) bool {
+	if server != assertion.Server {
 		return false
 	}
 
@@ -679,12 +1491,12 @@ func matchesResourceAssertion(call *mcpproxy.ResourceRead, assertion ResourceAss
 		return true
 	}
 
-	if assertion.URI != "" && call.URI == assertion.URI {
+	if assertion.URI != "" && uri == assertion.URI {
 		return true
 	}
 
 	if assertion.URIPattern != "" {
-		matched, _ := regexp.MatchString(assertion.URIPattern, call.URI)
+		matched, _ := regexp.MatchString(assertion.URIPattern, uri)
 		return matched
 	}
 
@@ -774,5 +1586,25 @@ func (c *CompositeAssertionResult) Merge(other *CompositeAssertionResult) *Compo
 		PromptsNotUsed:   mergeField(c.PromptsNotUsed, other.PromptsNotUsed),
 		CallOrder:        mergeField(c.CallOrder, other.CallOrder),
 		NoDuplicateCalls: mergeField(c.NoDuplicateCalls, other.NoDuplicateCalls),
+
+		SamplingUsed:       mergeField(c.SamplingUsed, other.SamplingUsed),
+		SamplingNotUsed:    mergeField(c.SamplingNotUsed, other.SamplingNotUsed),
+		ElicitationUsed:    mergeField(c.ElicitationUsed, other.ElicitationUsed),
+		ElicitationNotUsed: mergeField(c.ElicitationNotUsed, other.ElicitationNotUsed),
+		RootsListed:        mergeField(c.RootsListed, other.RootsListed),
+
+		ListsCalled:     mergeField(c.ListsCalled, other.ListsCalled),
+		ListedBeforeUse: mergeField(c.ListedBeforeUse, other.ListedBeforeUse),
+
+		ResourcesSubscribed:    mergeField(c.ResourcesSubscribed, other.ResourcesSubscribed),
+		ResourcesNotSubscribed: mergeField(c.ResourcesNotSubscribed, other.ResourcesNotSubscribed),
+
+		ToolOutputSchemaValid: mergeField(c.ToolOutputSchemaValid, other.ToolOutputSchemaValid),
+
+		CommandsRun:    mergeField(c.CommandsRun, other.CommandsRun),
+		CommandsNotRun: mergeField(c.CommandsNotRun, other.CommandsNotRun),
+
+		NoDeniedPermissions:     mergeField(c.NoDeniedPermissions, other.NoDeniedPermissions),
+		PermissionsNotRequested: mergeField(c.PermissionsNotRequested, other.PermissionsNotRequested),
 	}
 }
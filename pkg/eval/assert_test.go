@@ -6,9 +6,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
+	"github.com/mcpchecker/mcpchecker/pkg/agent"
 	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
+	"github.com/mcpchecker/mcpchecker/pkg/task"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSingleAssertionResult_Succeeded(t *testing.T) {
@@ -1002,6 +1006,378 @@ func TestPromptsNotUsedEvaluator(t *testing.T) {
 	}
 }
 
+func TestServerRequestEvaluators(t *testing.T) {
+	history := &mcpproxy.CallHistory{
+		SamplingRequests: []*mcpproxy.SamplingRequest{
+			{
+				CallRecord: mcpproxy.CallRecord{ServerName: "s1"},
+				Request: &mcp.CreateMessageParams{
+					Messages: []*mcp.SamplingMessage{
+						{Role: "user", Content: &mcp.TextContent{Text: "summarize the logs"}},
+					},
+				},
+			},
+		},
+		Elicitations: []*mcpproxy.Elicitation{
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s1"}, Message: "delete the pod?", Action: "decline"},
+		},
+		RootsLists: []*mcpproxy.RootsList{
+			{
+				CallRecord: mcpproxy.CallRecord{ServerName: "s2"},
+				Result:     &mcp.ListRootsResult{Roots: []*mcp.Root{{URI: "file:///workspace"}}},
+			},
+		},
+	}
+
+	tt := map[string]struct {
+		eval       SingleAssertionEvaluator
+		history    *mcpproxy.CallHistory
+		expectPass bool
+		expectType string
+	}{
+		"sampling used with matching pattern passes": {
+			eval:       NewSamplingUsedEvaluator([]ServerRequestAssertion{{Server: "s1", Pattern: "summarize"}}),
+			history:    history,
+			expectPass: true,
+			expectType: assertionTypeSamplingUsed,
+		},
+		"sampling used with wrong server fails": {
+			eval:       NewSamplingUsedEvaluator([]ServerRequestAssertion{{Server: "s2"}}),
+			history:    history,
+			expectPass: false,
+			expectType: assertionTypeSamplingUsed,
+		},
+		"sampling not used fails when server sampled": {
+			eval:       NewSamplingNotUsedEvaluator([]ServerRequestAssertion{{Server: "s1"}}),
+			history:    history,
+			expectPass: false,
+			expectType: assertionTypeSamplingNotUsed,
+		},
+		"sampling not used passes on empty history": {
+			eval:       NewSamplingNotUsedEvaluator([]ServerRequestAssertion{{Server: "s1"}}),
+			history:    &mcpproxy.CallHistory{},
+			expectPass: true,
+			expectType: assertionTypeSamplingNotUsed,
+		},
+		"elicitation used with message pattern passes": {
+			eval:       NewElicitationUsedEvaluator([]ServerRequestAssertion{{Server: "s1", Pattern: "(?i)delete"}}),
+			history:    history,
+			expectPass: true,
+			expectType: assertionTypeElicitationUsed,
+		},
+		"elicitation used with matching action passes": {
+			eval:       NewElicitationUsedEvaluator([]ServerRequestAssertion{{Server: "s1", Pattern: "delete", Action: "decline"}}),
+			history:    history,
+			expectPass: true,
+			expectType: assertionTypeElicitationUsed,
+		},
+		"elicitation used with other action fails": {
+			eval:       NewElicitationUsedEvaluator([]ServerRequestAssertion{{Server: "s1", Pattern: "delete", Action: "accept"}}),
+			history:    history,
+			expectPass: false,
+			expectType: assertionTypeElicitationUsed,
+		},
+		"elicitation not used with non-matching pattern passes": {
+			eval:       NewElicitationNotUsedEvaluator([]ServerRequestAssertion{{Server: "s1", Pattern: "scale"}}),
+			history:    history,
+			expectPass: true,
+			expectType: assertionTypeElicitationNotUsed,
+		},
+		"roots listed matches root uri": {
+			eval:       NewRootsListedEvaluator([]ServerRequestAssertion{{Server: "s2", Pattern: "^file:///workspace$"}}),
+			history:    history,
+			expectPass: true,
+			expectType: assertionTypeRootsListed,
+		},
+		"roots listed fails when not requested": {
+			eval:       NewRootsListedEvaluator([]ServerRequestAssertion{{Server: "s1"}}),
+			history:    history,
+			expectPass: false,
+			expectType: assertionTypeRootsListed,
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			result := tc.eval.Evaluate(tc.history)
+
+			assert.Equal(t, tc.expectPass, result.Passed, result.Reason)
+			assert.Equal(t, tc.expectType, tc.eval.Type())
+		})
+	}
+}
+
+func TestDiscoveryEvaluators(t *testing.T) {
+	baseTime := time.Now()
+
+	history := &mcpproxy.CallHistory{
+		ListCalls: []*mcpproxy.ListCall{
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s1", Timestamp: baseTime, Success: true}, Method: mcpproxy.MethodListTools},
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s1", Timestamp: baseTime.Add(2 * time.Second), Success: true}, Method: mcpproxy.MethodListResourceTemplates},
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s1", Timestamp: baseTime, Success: false}, Method: mcpproxy.MethodListPrompts},
+		},
+		ToolCalls: []*mcpproxy.ToolCall{
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s1", Timestamp: baseTime.Add(time.Second)}, ToolName: "t1"},
+		},
+		ResourceReads: []*mcpproxy.ResourceRead{
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s1", Timestamp: baseTime.Add(3 * time.Second)}, URI: "file:///b"},
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s1", Timestamp: baseTime.Add(time.Second)}, URI: "file:///a"},
+		},
+	}
+
+	tt := map[string]struct {
+		eval       SingleAssertionEvaluator
+		expectPass bool
+		expectType string
+	}{
+		"lists called passes when listed": {
+			eval:       NewListsCalledEvaluator([]ListAssertion{{Server: "s1", List: "tools"}, {Server: "s1", List: "resources"}}),
+			expectPass: true,
+			expectType: assertionTypeListsCalled,
+		},
+		"lists called ignores failed list calls": {
+			eval:       NewListsCalledEvaluator([]ListAssertion{{Server: "s1", List: "prompts"}}),
+			expectPass: false,
+			expectType: assertionTypeListsCalled,
+		},
+		"lists called fails for other server": {
+			eval:       NewListsCalledEvaluator([]ListAssertion{{Server: "s2", List: "tools"}}),
+			expectPass: false,
+			expectType: assertionTypeListsCalled,
+		},
+		"tools listed before call passes": {
+			eval:       NewListedBeforeUseEvaluator([]ListAssertion{{Server: "s1", List: "tools"}}),
+			expectPass: true,
+			expectType: assertionTypeListedBeforeUse,
+		},
+		"resource read before listing fails": {
+			eval:       NewListedBeforeUseEvaluator([]ListAssertion{{Server: "s1", List: "resources"}}),
+			expectPass: false,
+			expectType: assertionTypeListedBeforeUse,
+		},
+		"unused list passes": {
+			eval:       NewListedBeforeUseEvaluator([]ListAssertion{{Server: "s1", List: "prompts"}}),
+			expectPass: true,
+			expectType: assertionTypeListedBeforeUse,
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			result := tc.eval.Evaluate(history)
+
+			assert.Equal(t, tc.expectPass, result.Passed, result.Reason)
+			assert.Equal(t, tc.expectType, tc.eval.Type())
+		})
+	}
+
+	result := NewListedBeforeUseEvaluator([]ListAssertion{{Server: "s1", List: "resources"}}).Evaluate(history)
+	assert.Contains(t, result.Reason, "resource file:///a")
+}
+
+func TestToolOutputSchemaValidEvaluator(t *testing.T) {
+	eval := NewToolOutputSchemaValidEvaluator()
+	assert.Equal(t, assertionTypeToolOutputSchemaValid, eval.Type())
+
+	result := eval.Evaluate(&mcpproxy.CallHistory{
+		ToolCalls: []*mcpproxy.ToolCall{{CallRecord: mcpproxy.CallRecord{ServerName: "s1", Success: true}, ToolName: "t1"}},
+	})
+	assert.True(t, result.Passed, result.Reason)
+
+	result = eval.Evaluate(&mcpproxy.CallHistory{
+		OutputSchemaViolations: []*mcpproxy.OutputSchemaViolation{
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s1", Error: "missing property \"count\""}, ToolName: "t1"},
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s2", Error: "no structured content"}, ToolName: "t2"},
+		},
+	})
+	assert.False(t, result.Passed)
+	assert.Contains(t, result.Reason, "s1.t1")
+	assert.Equal(t, []string{`s1.t1: missing property "count"`, "s2.t2: no structured content"}, result.Details)
+}
+
+func TestCommandEvaluators(t *testing.T) {
+	details := &task.AgentDetails{
+		TerminalCommands: []acpclient.TerminalCommand{
+			{Command: "kubectl", Args: []string{"get", "pods", "-n", "default"}},
+		},
+		ToolCalls: []agent.ToolCallSummary{
+			{Title: "Run ls", Kind: "execute", RawInput: map[string]any{"command": "ls -la"}},
+			{Title: "helm list", Kind: "execute"},
+			{Title: "pods_list", Kind: "other", RawInput: map[string]any{"command": "oc get pods"}},
+		},
+	}
+	commands := agentCommands(details)
+	assert.Equal(t, []string{"kubectl get pods -n default", "ls -la", "helm list"}, commands)
+
+	tt := map[string]struct {
+		eval       SingleAssertionEvaluator
+		expectPass bool
+		expectType string
+	}{
+		"required command run": {
+			eval:       NewCommandsRunEvaluator([]CommandAssertion{{Pattern: `^ls\b`}}, commands),
+			expectPass: true,
+			expectType: assertionTypeCommandsRun,
+		},
+		"required command not run": {
+			eval:       NewCommandsRunEvaluator([]CommandAssertion{{Pattern: `\boc\b`}}, commands),
+			expectPass: false,
+			expectType: assertionTypeCommandsRun,
+		},
+		"forbidden terminal command run": {
+			eval:       NewCommandsNotRunEvaluator([]CommandAssertion{{Pattern: `\bkubectl\b`}}, commands),
+			expectPass: false,
+			expectType: assertionTypeCommandsNotRun,
+		},
+		"forbidden tool command run": {
+			eval:       NewCommandsNotRunEvaluator([]CommandAssertion{{Pattern: `^helm `}}, commands),
+			expectPass: false,
+			expectType: assertionTypeCommandsNotRun,
+		},
+		"non execute tool calls are not commands": {
+			eval:       NewCommandsNotRunEvaluator([]CommandAssertion{{Pattern: `\boc\b`}}, commands),
+			expectPass: true,
+			expectType: assertionTypeCommandsNotRun,
+		},
+		"no agent details": {
+			eval:       NewCommandsNotRunEvaluator([]CommandAssertion{{Pattern: `kubectl`}}, agentCommands(nil)),
+			expectPass: true,
+			expectType: assertionTypeCommandsNotRun,
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			result := tc.eval.Evaluate(&mcpproxy.CallHistory{})
+			assert.Equal(t, tc.expectPass, result.Passed, result.Reason)
+			assert.Equal(t, tc.expectType, tc.eval.Type())
+		})
+	}
+
+	composite := NewCompositeAssertionEvaluator(&TaskAssertions{
+		CommandsNotRun: []CommandAssertion{{Pattern: `\bkubectl\b`}},
+	}, details).Evaluate(&mcpproxy.CallHistory{})
+	require.NotNil(t, composite.CommandsNotRun)
+	assert.False(t, composite.Succeeded())
+	assert.Contains(t, composite.CommandsNotRun.Reason, "kubectl get pods -n default")
+}
+
+func TestPermissionEvaluators(t *testing.T) {
+	details := &task.AgentDetails{
+		PermissionRequests: []acpclient.PermissionRequest{
+			{ToolCallId: "1", Title: "mcp__kubernetes__pods_list", Server: "kubernetes", Tool: "pods_list", Decision: acpclient.PermissionAllow},
+			{ToolCallId: "2", Title: "kubectl delete pod nginx", Kind: "execute", Decision: acpclient.PermissionDeny},
+		},
+	}
+	requests := permissionRequests(details)
+
+	tt := map[string]struct {
+		eval       SingleAssertionEvaluator
+		expectPass bool
+		expectType string
+	}{
+		"denied permission": {
+			eval:       NewNoDeniedPermissionsEvaluator(requests),
+			expectPass: false,
+			expectType: assertionTypeNoDeniedPermissions,
+		},
+		"no denied permission": {
+			eval:       NewNoDeniedPermissionsEvaluator(requests[:1]),
+			expectPass: true,
+			expectType: assertionTypeNoDeniedPermissions,
+		},
+		"forbidden mcp tool requested": {
+			eval:       NewPermissionsNotRequestedEvaluator([]PermissionAssertion{{Server: "kubernetes", ToolPattern: "^pods_"}}, requests),
+			expectPass: false,
+			expectType: assertionTypePermissionsNotRequested,
+		},
+		"forbidden kind requested": {
+			eval:       NewPermissionsNotRequestedEvaluator([]PermissionAssertion{{Kind: "execute"}}, requests),
+			expectPass: false,
+			expectType: assertionTypePermissionsNotRequested,
+		},
+		"title of other tool does not match mcp tool name": {
+			eval:       NewPermissionsNotRequestedEvaluator([]PermissionAssertion{{Tool: "mcp__kubernetes__pods_list"}}, requests),
+			expectPass: true,
+			expectType: assertionTypePermissionsNotRequested,
+		},
+		"no agent details": {
+			eval:       NewPermissionsNotRequestedEvaluator([]PermissionAssertion{{Kind: "execute"}}, permissionRequests(nil)),
+			expectPass: true,
+			expectType: assertionTypePermissionsNotRequested,
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			result := tc.eval.Evaluate(&mcpproxy.CallHistory{})
+			assert.Equal(t, tc.expectPass, result.Passed, result.Reason)
+			assert.Equal(t, tc.expectType, tc.eval.Type())
+		})
+	}
+
+	composite := NewCompositeAssertionEvaluator(&TaskAssertions{NoDeniedPermissions: true}, details).Evaluate(&mcpproxy.CallHistory{})
+	require.NotNil(t, composite.NoDeniedPermissions)
+	assert.False(t, composite.Succeeded())
+	assert.Equal(t, []string{`title="kubectl delete pod nginx"`}, composite.NoDeniedPermissions.Details)
+}
+
+func TestResourceSubscriptionEvaluators(t *testing.T) {
+	history := &mcpproxy.CallHistory{
+		Subscriptions: []*mcpproxy.Subscription{
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s1", Success: true}, URI: "file:///logs/app.log"},
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s1", Success: true}, URI: "file:///metrics", Unsubscribe: true},
+			{CallRecord: mcpproxy.CallRecord{ServerName: "s1", Success: false}, URI: "file:///events"},
+		},
+	}
+
+	tt := map[string]struct {
+		eval       SingleAssertionEvaluator
+		expectPass bool
+		expectType string
+	}{
+		"subscribed by uri passes": {
+			eval:       NewResourcesSubscribedEvaluator([]ResourceAssertion{{Server: "s1", URI: "file:///logs/app.log"}}),
+			expectPass: true,
+			expectType: assertionTypeResourcesSubscribed,
+		},
+		"subscribed by pattern passes": {
+			eval:       NewResourcesSubscribedEvaluator([]ResourceAssertion{{Server: "s1", URIPattern: "^file:///logs/"}}),
+			expectPass: true,
+			expectType: assertionTypeResourcesSubscribed,
+		},
+		"unsubscribe does not count as subscribed": {
+			eval:       NewResourcesSubscribedEvaluator([]ResourceAssertion{{Server: "s1", URI: "file:///metrics"}}),
+			expectPass: false,
+			expectType: assertionTypeResourcesSubscribed,
+		},
+		"failed subscription does not count": {
+			eval:       NewResourcesSubscribedEvaluator([]ResourceAssertion{{Server: "s1", URI: "file:///events"}}),
+			expectPass: false,
+			expectType: assertionTypeResourcesSubscribed,
+		},
+		"not subscribed fails when subscribed": {
+			eval:       NewResourcesNotSubscribedEvaluator([]ResourceAssertion{{Server: "s1"}}),
+			expectPass: false,
+			expectType: assertionTypeResourcesNotSubscribed,
+		},
+		"not subscribed passes for other server": {
+			eval:       NewResourcesNotSubscribedEvaluator([]ResourceAssertion{{Server: "s2"}}),
+			expectPass: true,
+			expectType: assertionTypeResourcesNotSubscribed,
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			result := tc.eval.Evaluate(history)
+
+			assert.Equal(t, tc.expectPass, result.Passed, result.Reason)
+			assert.Equal(t, tc.expectType, tc.eval.Type())
+		})
+	}
+}
+
 func TestCallOrderEvaluator(t *testing.T) {
 	baseTime := time.Now()
 
@@ -1701,7 +2077,7 @@ func TestNewCompositeAssertionEvaluator(t *testing.T) {
 
 	for tn, tc := range tt {
 		t.Run(tn, func(t *testing.T) {
-			eval := NewCompositeAssertionEvaluator(tc.assertions)
+			eval := NewCompositeAssertionEvaluator(tc.assertions, nil)
 			// We can't directly access the evaluators slice, so we test by evaluating
 			// an empty history and counting non-nil results
 			result := eval.Evaluate(&mcpproxy.CallHistory{})
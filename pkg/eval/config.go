@@ -7,9 +7,13 @@ import (
 
 	"sigs.k8s.io/yaml"
 
+	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
 	"github.com/mcpchecker/mcpchecker/pkg/agent"
 	"github.com/mcpchecker/mcpchecker/pkg/extension"
+	"github.com/mcpchecker/mcpchecker/pkg/llmagent"
 	"github.com/mcpchecker/mcpchecker/pkg/llmjudge"
+	"github.com/mcpchecker/mcpchecker/pkg/toolfilter"
+	"github.com/mcpchecker/mcpchecker/pkg/tooloverride"
 	"github.com/mcpchecker/mcpchecker/pkg/util"
 )
 
@@ -46,14 +50,44 @@ type EvalConfig struct {
 	McpConfigFile string                       `json:"mcpConfigFile"`
 	LLMJudge      *llmjudge.LLMJudgeEvalConfig `json:"llmJudge"`
 
+	// Sampling configures how sampling requests from MCP servers are answered
+	Sampling *SamplingConfig `json:"sampling,omitempty"`
+
 	// DefaultTaskLimits sets default timeout limits for all tasks in this eval.
 	// Individual tasks can override these via spec.limits.
 	DefaultTaskLimits *util.Limits `json:"defaultTaskLimits,omitempty"`
 
+	// ToolOverrides lists variants of how the MCP servers are advertised to the agent.
+	// Every task runs once per variant, and results are tagged with the variant name.
+	ToolOverrides []tooloverride.Variant `json:"toolOverrides,omitempty"`
+
+	// ServerChecks runs the lint-server checks against the MCP servers before the tasks,
+	// and reports tools that returned structured content without an outputSchema after them
+	ServerChecks bool `json:"serverChecks,omitempty"`
+
+	// Permissions decides the permission requests of ACP agents, by default tools of the
+	// MCP servers are allowed and other tools are denied
+	Permissions *acpclient.PermissionPolicy `json:"permissions,omitempty"`
+
+	// Retry retries agent runs that fail with an infra error, e.g. a rate limited
+	// model API. Without it infra errors are classified but not retried.
+	Retry *agent.RetryPolicy `json:"retry,omitempty"`
+
+	// RateLimits caps the requests and tokens per minute of builtin.llm-agent models per
+	// provider, e.g. anthropic. The limits are shared by all tasks, the judge and sampling.
+	RateLimits map[string]llmagent.RateLimit `json:"rateLimits,omitempty"`
+
 	// Advanced mode: different assertion sets
 	TaskSets []TaskSet `json:"taskSets,omitempty"`
 }
 
+// SamplingConfig configures a model that answers MCP sampling requests when the
+// agent does not support sampling. Requests are forwarded to the agent when it does.
+type SamplingConfig struct {
+	// Model in "provider:model-id" format (e.g. "openai:gpt-4o")
+	Model string `json:"model"`
+}
+
 type TaskSet struct {
 	// Exactly one of Glob or Path must be set
 	Glob string `json:"glob,omitempty"`
@@ -64,6 +98,9 @@ type TaskSet struct {
 	LabelSelector map[string]string `json:"labelSelector,omitempty"`
 
 	Assertions *TaskAssertions `json:"assertions,omitempty"`
+
+	// Optional tool restrictions applied to every task in the set, on top of the task's own
+	Tools *toolfilter.Config `json:"tools,omitempty"`
 }
 
 // TODO: add a custom Verify script for another form of assertion
@@ -79,6 +116,10 @@ type TaskAssertions struct {
 	ResourcesRead    []ResourceAssertion `json:"resourcesRead,omitempty"`
 	ResourcesNotRead []ResourceAssertion `json:"resourcesNotRead,omitempty"`
 
+	// Subscription assertions
+	ResourcesSubscribed    []ResourceAssertion `json:"resourcesSubscribed,omitempty"`
+	ResourcesNotSubscribed []ResourceAssertion `json:"resourcesNotSubscribed,omitempty"`
+
 	// Prompt assertions
 	PromptsUsed    []PromptAssertion `json:"promptsUsed,omitempty"`
 	PromptsNotUsed []PromptAssertion `json:"promptsNotUsed,omitempty"`
@@ -88,6 +129,28 @@ type TaskAssertions struct {
 
 	// Efficiency assertions
 	NoDuplicateCalls bool `json:"noDuplicateCalls,omitempty"`
+
+	// Server-initiated request assertions
+	SamplingUsed       []ServerRequestAssertion `json:"samplingUsed,omitempty"`
+	SamplingNotUsed    []ServerRequestAssertion `json:"samplingNotUsed,omitempty"`
+	ElicitationUsed    []ServerRequestAssertion `json:"elicitationUsed,omitempty"`
+	ElicitationNotUsed []ServerRequestAssertion `json:"elicitationNotUsed,omitempty"`
+	RootsListed        []ServerRequestAssertion `json:"rootsListed,omitempty"`
+
+	// Discovery assertions
+	ListsCalled     []ListAssertion `json:"listsCalled,omitempty"`
+	ListedBeforeUse []ListAssertion `json:"listedBeforeUse,omitempty"`
+
+	// Conformance assertions
+	ToolOutputSchemaValid bool `json:"toolOutputSchemaValid,omitempty"`
+
+	// Command assertions, on commands the agent ran itself instead of through the MCP servers
+	CommandsRun    []CommandAssertion `json:"commandsRun,omitempty"`
+	CommandsNotRun []CommandAssertion `json:"commandsNotRun,omitempty"`
+
+	// Permission assertions, on the permission requests of ACP agents
+	NoDeniedPermissions     bool                  `json:"noDeniedPermissions,omitempty"`
+	PermissionsNotRequested []PermissionAssertion `json:"permissionsNotRequested,omitempty"`
 }
 
 type ToolAssertion struct {
@@ -117,6 +180,41 @@ type PromptAssertion struct {
 	PromptPattern string `json:"promptPattern,omitempty"`
 }
 
+type ServerRequestAssertion struct {
+	Server string `json:"server"`
+
+	// Pattern is an optional regex matched against the sampling messages, the
+	// elicitation message or the listed root URIs. If unset, any request from the server matches.
+	Pattern string `json:"pattern,omitempty"`
+
+	// Action optionally restricts elicitation assertions to requests answered with
+	// this action (accept, decline or cancel). It is ignored for other requests.
+	Action string `json:"action,omitempty"`
+}
+
+type ListAssertion struct {
+	Server string `json:"server"`
+
+	// List is the list the agent must have requested: tools, prompts or resources.
+	// Listing resources includes listing resource templates.
+	List string `json:"list"`
+}
+
+type CommandAssertion struct {
+	// Pattern is a regex matched against the command line, e.g. \bkubectl\b
+	Pattern string `json:"pattern"`
+}
+
+// PermissionAssertion matches permission requests of ACP agents. All set fields must match.
+type PermissionAssertion struct {
+	Server string `json:"server,omitempty"`
+	// Tool is the name of an MCP tool, or the title of the agent's own tools
+	Tool        string `json:"tool,omitempty"`
+	ToolPattern string `json:"toolPattern,omitempty"`
+	// Kind is the ACP kind of the tool, e.g. execute or edit
+	Kind string `json:"kind,omitempty"`
+}
+
 type CallOrderAssertion struct {
 	Type   string `json:"type"` // "tool", "resource", "prompt"
 	Server string `json:"server"`
@@ -148,6 +246,38 @@ func Read(data []byte, basePath string) (*EvalSpec, error) {
 		return nil, fmt.Errorf("failed to resolve mcp config file path: %w", err)
 	}
 
+	if spec.Config.Sampling != nil && spec.Config.Sampling.Model == "" {
+		return nil, fmt.Errorf("sampling.model must be set when sampling is configured")
+	}
+
+	if err := tooloverride.Validate(spec.Config.ToolOverrides); err != nil {
+		return nil, err
+	}
+
+	if spec.Config.Permissions != nil {
+		if err := spec.Config.Permissions.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid permissions: %w", err)
+		}
+	}
+
+	for provider, limit := range spec.Config.RateLimits {
+		if err := limit.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid rateLimits.%s: %w", provider, err)
+		}
+	}
+
+	if spec.Config.LLMJudge != nil && spec.Config.LLMJudge.RateLimit != nil {
+		if err := spec.Config.LLMJudge.RateLimit.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid llmJudge.rateLimit: %w", err)
+		}
+	}
+
+	if spec.Config.Retry != nil {
+		if err := spec.Config.Retry.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid retry: %w", err)
+		}
+	}
+
 	// Resolve task set paths and globs
 	for i := range spec.Config.TaskSets {
 		if spec.Config.TaskSets[i].Path != "" {
@@ -159,6 +289,11 @@ func Read(data []byte, basePath string) (*EvalSpec, error) {
 				return nil, fmt.Errorf("failed to resolve task set glob at index %d: %w", i, err)
 			}
 		}
+		if spec.Config.TaskSets[i].Tools != nil {
+			if err := spec.Config.TaskSets[i].Tools.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid tools config for task set at index %d: %w", i, err)
+			}
+		}
 	}
 
 	return spec, nil
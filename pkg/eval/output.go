@@ -1,12 +1,17 @@
 package eval
 
-import "net/url"
+import (
+	"net/url"
+
+	"github.com/mcpchecker/mcpchecker/pkg/serverlint"
+)
 
 // EvalOutput wraps evaluation results with configuration summary metadata.
 // This is the top-level structure written to the JSON output file.
 type EvalOutput struct {
-	Summary *EvalSummary `json:"summary"`
-	Results []*EvalResult `json:"results"`
+	Summary      *EvalSummary       `json:"summary"`
+	Results      []*EvalResult      `json:"results"`
+	ServerChecks *serverlint.Report `json:"serverChecks,omitempty"`
 }
 
 // EvalSummary captures the resolved configuration used for an evaluation run.
@@ -24,6 +29,7 @@ type EvalSummary struct {
 type AgentSummary struct {
 	Type    string `json:"type"`
 	Name    string `json:"name,omitempty"`
+	Version string `json:"version,omitempty"`
 	Model   string `json:"model,omitempty"`
 	Path    string `json:"path,omitempty"`
 	Command string `json:"command,omitempty"`
@@ -44,12 +50,14 @@ type MCPServerSummary struct {
 	Type    string `json:"type"`
 	URL     string `json:"url,omitempty"`
 	Command string `json:"command,omitempty"`
+	Auth    string `json:"auth,omitempty"` // auth type only, credentials are never written
 }
 
 // EvalsSummary describes the matched evaluations.
 type EvalsSummary struct {
 	Names    []string         `json:"names"`
 	TaskSets []TaskSetSummary `json:"taskSets,omitempty"`
+	Variants []string         `json:"variants,omitempty"` // toolOverrides variants every task runs with
 }
 
 // TaskSetSummary describes a single task set configuration.
@@ -21,6 +21,8 @@ const (
 	EventTaskStart      ProgressEventType = "task_start"
 	EventTaskSetup      ProgressEventType = "task_setup"
 	EventTaskRunning    ProgressEventType = "task_running"
+	EventTaskRetry      ProgressEventType = "task_retry"
+	EventRateLimited    ProgressEventType = "rate_limited"
 	EventTaskVerifying  ProgressEventType = "task_verifying"
 	EventTaskAssertions ProgressEventType = "task_assertions"
 	EventTaskComplete   ProgressEventType = "task_complete"
@@ -0,0 +1,120 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/mcpchecker/mcpchecker/pkg/llmjudge"
+	"github.com/mcpchecker/mcpchecker/pkg/task"
+)
+
+// RejudgeResult describes what happened to a single result during a rejudge
+type RejudgeResult struct {
+	TaskName string
+	// Rejudged is false if the result was carried over unchanged
+	Rejudged bool
+	// Skipped explains why a result was not rejudged
+	Skipped          string
+	PreviouslyPassed bool
+	Passed           bool
+}
+
+// Rejudge re-runs only the llmJudge verify steps for every result in prev, using the
+// judge configured in spec and the agent output stored in each result. The agent,
+// setup, cleanup and non-judge verify steps are not run again. Results that cannot
+// be rejudged (agent errors, timeouts, tasks without llmJudge steps, tasks no longer
+// matched by the eval) are copied over unchanged. Tasks that fail to be rejudged are
+// recorded as failed with the error, and the remaining tasks are still rejudged.
+func Rejudge(ctx context.Context, spec *EvalSpec, prev *EvalOutput) (*EvalOutput, []RejudgeResult, error) {
+	if spec == nil {
+		return nil, nil, fmt.Errorf("eval spec cannot be nil")
+	}
+	if prev == nil {
+		return nil, nil, fmt.Errorf("results cannot be nil")
+	}
+
+	r := &evalRunner{spec: spec, progressCallback: NoopProgressCallback}
+
+	judge, err := llmjudge.NewLLMJudge(spec.Config.LLMJudge)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create llm judge from spec: %w", err)
+	}
+	defer judge.Close()
+
+	ctx = llmjudge.WithJudge(ctx, judge)
+
+	taskConfigs, err := r.collectTaskConfigs(regexp.MustCompile("."))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byPath := make(map[string]*task.TaskConfig, len(taskConfigs))
+	byName := make(map[string]*task.TaskConfig, len(taskConfigs))
+	for _, tc := range taskConfigs {
+		byPath[tc.path] = tc.spec
+		byName[tc.spec.Metadata.Name] = tc.spec
+	}
+
+	out := &EvalOutput{
+		Results: make([]*EvalResult, 0, len(prev.Results)),
+	}
+	if prev.Summary != nil {
+		summary := *prev.Summary
+		summary.Judge = r.buildJudgeSummary(judge)
+		out.Summary = &summary
+	}
+
+	report := make([]RejudgeResult, 0, len(prev.Results))
+	for _, prevResult := range prev.Results {
+		if prevResult == nil {
+			continue
+		}
+
+		result := *prevResult
+		entry := RejudgeResult{
+			TaskName:         result.TaskName,
+			PreviouslyPassed: result.TaskPassed,
+			Passed:           result.TaskPassed,
+		}
+
+		taskSpec, ok := byPath[filepath.Clean(result.TaskPath)]
+		if !ok {
+			taskSpec, ok = byName[result.TaskName]
+		}
+
+		switch {
+		case !ok:
+			entry.Skipped = "task not found in eval config"
+		case result.AgentExecutionError:
+			entry.Skipped = "agent failed to execute"
+		case result.TimedOut:
+			entry.Skipped = "task timed out"
+		case result.VerifyOutput == nil:
+			entry.Skipped = "verify phase did not run"
+		case !taskSpec.HasLLMJudgeSteps():
+			entry.Skipped = "task has no llmJudge steps"
+		}
+
+		if entry.Skipped != "" {
+			out.Results = append(out.Results, &result)
+			report = append(report, entry)
+			continue
+		}
+
+		verifyOutput, verifyErr := task.Rejudge(ctx, taskSpec, &task.RecordedRun{
+			Output:       result.TaskOutput,
+			SetupOutput:  result.SetupOutput,
+			VerifyOutput: result.VerifyOutput,
+		})
+		r.applyVerifyOutput(verifyOutput, verifyErr, &result)
+
+		entry.Rejudged = true
+		entry.Passed = result.TaskPassed
+		out.Results = append(out.Results, &result)
+		report = append(report, entry)
+	}
+
+	return out, report, nil
+}
@@ -0,0 +1,151 @@
+package eval
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mcpchecker/mcpchecker/pkg/steps"
+	"github.com/mcpchecker/mcpchecker/pkg/task"
+	"github.com/mcpchecker/mcpchecker/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRejudge(t *testing.T) {
+	dir := t.TempDir()
+	taskPath := filepath.Join(dir, "judged.yaml")
+	require.NoError(t, os.WriteFile(taskPath, []byte(`kind: Task
+apiVersion: mcpchecker/v1alpha2
+metadata:
+  name: judged
+spec:
+  verify:
+    - llmJudge:
+        contains: "pod web"
+  prompt:
+    inline: List pods
+`), 0644))
+
+	// No llmJudge config means the noop judge, which always passes
+	spec := &EvalSpec{
+		Config: EvalConfig{
+			TaskSets: []TaskSet{{Path: taskPath}},
+		},
+	}
+
+	prev := &EvalOutput{
+		Summary: &EvalSummary{Runs: 1},
+		Results: []*EvalResult{
+			{
+				TaskName:        "judged",
+				TaskPath:        taskPath,
+				TaskOutput:      "pod web is running",
+				TaskPassed:      false,
+				TaskError:       "one or more verification steps failed",
+				TaskJudgeReason: "old reason",
+				JudgeTokenUsage: &tokens.Usage{InputTokens: 10},
+				VerifyOutput: &task.PhaseOutput{
+					Steps: []*steps.StepOutput{{Type: "llmJudge", Success: false}},
+				},
+			},
+			{
+				TaskName:            "judged",
+				TaskPath:            taskPath,
+				AgentExecutionError: true,
+				TaskError:           "agent crashed",
+			},
+			{
+				TaskName:   "removed",
+				TaskPath:   filepath.Join(dir, "removed.yaml"),
+				TaskPassed: true,
+			},
+		},
+	}
+
+	out, report, err := Rejudge(context.Background(), spec, prev)
+	require.NoError(t, err)
+	require.Len(t, out.Results, 3)
+	require.Len(t, report, 3)
+
+	rejudged := out.Results[0]
+	assert.True(t, rejudged.TaskPassed)
+	assert.Empty(t, rejudged.TaskError)
+	assert.Equal(t, "noop judge always passes", rejudged.TaskJudgeReason)
+	assert.Nil(t, rejudged.JudgeTokenUsage)
+	assert.True(t, report[0].Rejudged)
+	assert.False(t, report[0].PreviouslyPassed)
+	assert.True(t, report[0].Passed)
+
+	// the input must not be modified
+	assert.False(t, prev.Results[0].TaskPassed)
+	assert.Equal(t, "old reason", prev.Results[0].TaskJudgeReason)
+
+	assert.Equal(t, "agent crashed", out.Results[1].TaskError)
+	assert.False(t, report[1].Rejudged)
+	assert.Equal(t, "agent failed to execute", report[1].Skipped)
+
+	assert.True(t, out.Results[2].TaskPassed)
+	assert.Equal(t, "task not found in eval config", report[2].Skipped)
+
+	assert.Equal(t, 1, out.Summary.Runs)
+	assert.Nil(t, out.Summary.Judge)
+}
+
+func TestRejudgeContinuesAfterTaskError(t *testing.T) {
+	dir := t.TempDir()
+	brokenPath := filepath.Join(dir, "broken.yaml")
+	require.NoError(t, os.WriteFile(brokenPath, []byte(`kind: Task
+apiVersion: mcpchecker/v1alpha2
+metadata:
+  name: broken
+spec:
+  verify:
+    - llmJudge:
+        contains: "pod web"
+  prompt:
+    file: missing.txt
+`), 0644))
+	judgedPath := filepath.Join(dir, "judged.yaml")
+	require.NoError(t, os.WriteFile(judgedPath, []byte(`kind: Task
+apiVersion: mcpchecker/v1alpha2
+metadata:
+  name: judged
+spec:
+  verify:
+    - llmJudge:
+        contains: "pod web"
+  prompt:
+    inline: List pods
+`), 0644))
+
+	spec := &EvalSpec{
+		Config: EvalConfig{
+			TaskSets: []TaskSet{{Path: brokenPath}, {Path: judgedPath}},
+		},
+	}
+
+	verifyOutput := &task.PhaseOutput{
+		Steps: []*steps.StepOutput{{Type: "llmJudge", Success: false}},
+	}
+	prev := &EvalOutput{
+		Results: []*EvalResult{
+			{TaskName: "broken", TaskPath: brokenPath, TaskOutput: "pod web is running", TaskPassed: true, VerifyOutput: verifyOutput},
+			{TaskName: "judged", TaskPath: judgedPath, TaskOutput: "pod web is running", VerifyOutput: verifyOutput},
+		},
+	}
+
+	out, report, err := Rejudge(context.Background(), spec, prev)
+	require.NoError(t, err)
+	require.Len(t, out.Results, 2)
+
+	assert.False(t, out.Results[0].TaskPassed)
+	assert.Contains(t, out.Results[0].TaskError, "failed to get prompt for task")
+	assert.Equal(t, FailureVerification, out.Results[0].FailureKind)
+	assert.True(t, report[0].Rejudged)
+	assert.False(t, report[0].Passed)
+
+	assert.True(t, out.Results[1].TaskPassed)
+	assert.True(t, report[1].Passed)
+}
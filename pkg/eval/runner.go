@@ -6,25 +6,33 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
 	"github.com/mcpchecker/mcpchecker/pkg/agent"
+	"github.com/mcpchecker/mcpchecker/pkg/elicitation"
 	"github.com/mcpchecker/mcpchecker/pkg/extension/client"
 	"github.com/mcpchecker/mcpchecker/pkg/extension/resolver"
+	"github.com/mcpchecker/mcpchecker/pkg/llmagent"
 	"github.com/mcpchecker/mcpchecker/pkg/llmjudge"
 	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
 	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
+	"github.com/mcpchecker/mcpchecker/pkg/serverlint"
 	"github.com/mcpchecker/mcpchecker/pkg/task"
 	"github.com/mcpchecker/mcpchecker/pkg/tokens"
+	"github.com/mcpchecker/mcpchecker/pkg/toolfilter"
+	"github.com/mcpchecker/mcpchecker/pkg/tooloverride"
 	"github.com/mcpchecker/mcpchecker/pkg/util"
 )
 
 type EvalResult struct {
 	TaskName            string                    `json:"taskName"`
 	TaskPath            string                    `json:"taskPath"`
+	Variant             string                    `json:"variant,omitempty"` // toolOverrides variant the task ran with
 	TaskPassed          bool                      `json:"taskPassed"`
 	TaskOutput          string                    `json:"taskOutput"`
 	TaskError           string                    `json:"taskError,omitempty"`
@@ -32,6 +40,7 @@ type EvalResult struct {
 	TaskJudgeReason     string                    `json:"taskJudgeReason,omitempty"`
 	TaskJudgeError      string                    `json:"taskJudgeError,omitempty"`
 	AgentExecutionError bool                      `json:"agentExecutionError,omitempty"` // True if agent failed to execute
+	FailureKind         FailureKind               `json:"failureKind,omitempty"`         // Why the task failed, unset if it passed
 	Difficulty          string                    `json:"difficulty"`
 	Parallel            bool                      `json:"parallel,omitempty"`
 	RunIndex            int                       `json:"runIndex,omitempty"`  // 0-indexed run number (for multi-run)
@@ -39,6 +48,7 @@ type EvalResult struct {
 	AssertionResults    *CompositeAssertionResult `json:"assertionResults"`
 	AllAssertionsPassed bool                      `json:"allAssertionsPassed"`
 	CallHistory         *mcpproxy.CallHistory     `json:"callHistory"`
+	ServerEvents        []mcpclient.ServerEvent   `json:"serverEvents,omitempty"` // MCP server crashes and reconnects
 
 	// TokenEstimate contains token count estimates from agent execution.
 	// Uses tiktoken (cl100k_base encoding). Excludes system prompt and cache tokens.
@@ -54,6 +64,21 @@ type EvalResult struct {
 	CleanupOutput *task.PhaseOutput `json:"cleanupOutput,omitempty"`
 }
 
+// FailureKind classifies why a task failed
+type FailureKind string
+
+const (
+	// FailureSetup is a task that failed before the agent ran, e.g. in setup or while
+	// starting the MCP servers
+	FailureSetup FailureKind = "setup"
+	// FailureInfra is an agent or judge that failed with an infra error of the retry policy
+	FailureInfra FailureKind = "infra"
+	// FailureAgent is an agent that failed to run to completion, e.g. it crashed or timed out
+	FailureAgent FailureKind = "agent"
+	// FailureVerification is an agent that completed, but the verify phase failed
+	FailureVerification FailureKind = "verification"
+)
+
 type EvalRunner interface {
 	Run(ctx context.Context, taskPattern string) (*EvalOutput, error)
 	RunWithProgress(ctx context.Context, taskPattern string, callback ProgressCallback) (*EvalOutput, error)
@@ -84,6 +109,9 @@ type evalRunner struct {
 	taskTimeout           string
 	defaultCleanupTimeout string
 	cleanupTimeout        string
+
+	// sampler answers MCP sampling requests for agents without sampling support
+	sampler mcpproxy.Sampler
 }
 
 var _ EvalRunner = &evalRunner{}
@@ -91,7 +119,9 @@ var _ EvalRunner = &evalRunner{}
 type taskConfig struct {
 	path       string
 	spec       *task.TaskConfig
-	assertions []*TaskAssertions // multiple assertion sets from matching TaskSets, evaluated independently
+	assertions []*TaskAssertions    // multiple assertion sets from matching TaskSets, evaluated independently
+	tools      []*toolfilter.Config // tool restrictions from matching TaskSets, all of which apply
+	variant    *tooloverride.Variant
 }
 
 // NewRunner creates a new EvalRunner from an EvalSpec
@@ -199,11 +229,24 @@ func (r *evalRunner) RunWithProgress(ctx context.Context, taskPattern string, ca
 	}
 	defer judge.Close()
 
+	// Rate limits are shared by the parallel tasks, the judge and the sampler
+	ctx = llmagent.WithRateLimiters(ctx, llmagent.NewRateLimiters(r.spec.Config.RateLimits))
+	ctx = llmagent.WithRateLimitWait(ctx, r.rateLimitWait(nil))
+
+	if r.spec.Config.Sampling != nil {
+		sampler, err := llmagent.NewSampler(ctx, r.spec.Config.Sampling.Model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create sampler from spec: %w", err)
+		}
+		r.sampler = sampler
+	}
+
 	resolver := resolver.GetResolver(resolver.Options{
 		BasePath: r.spec.BasePath(),
 	})
 
 	ctx = llmjudge.WithJudge(ctx, judge)
+	ctx = acpclient.WithPermissionPolicy(ctx, r.spec.Config.Permissions)
 
 	taskConfigs, err := r.collectTaskConfigs(taskMatcher)
 	if err != nil {
@@ -211,7 +254,9 @@ func (r *evalRunner) RunWithProgress(ctx context.Context, taskPattern string, ca
 	}
 
 	// Build summary from resolved configuration
-	summary := r.buildSummary(agentSpec, mcpConfig, judge, taskConfigs)
+	summary := r.buildSummary(ctx, agentSpec, mcpConfig, judge, taskConfigs)
+
+	taskConfigs = expandVariants(taskConfigs, r.spec.Config.ToolOverrides)
 
 	r.progressCallback(ProgressEvent{
 		Type:    EventEvalStart,
@@ -219,6 +264,24 @@ func (r *evalRunner) RunWithProgress(ctx context.Context, taskPattern string, ca
 		Summary: summary,
 	})
 
+	connections, err := mcpclient.NewConnections(mcpConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up mcp connections: %w", err)
+	}
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_ = connections.Close(cleanupCtx)
+	}()
+
+	var serverChecks *serverlint.Report
+	if r.spec.Config.ServerChecks {
+		serverChecks, err = serverlint.Lint(ctx, mcpConfig, serverlint.Options{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to run server checks: %w", err)
+		}
+	}
+
 	// Group tasks by parallel support
 	groups := groupTasksByParallelSupport(taskConfigs)
 
@@ -231,7 +294,7 @@ func (r *evalRunner) RunWithProgress(ctx context.Context, taskPattern string, ca
 			workerLimit = r.parallelWorkers
 		}
 
-		groupResults := r.runTaskGroup(ctx, runner, mcpConfig, resolver, group.tasks, workerLimit)
+		groupResults := r.runTaskGroup(ctx, runner, connections, resolver, group.tasks, workerLimit)
 		results = append(results, groupResults...)
 	}
 
@@ -240,13 +303,38 @@ func (r *evalRunner) RunWithProgress(ctx context.Context, taskPattern string, ca
 		Message: "Evaluation complete",
 	})
 
+	if serverChecks != nil {
+		serverChecks.CheckStructuredResults(structuredToolResults(results))
+	}
+
 	return &EvalOutput{
-		Summary: summary,
-		Results: results,
+		Summary:      summary,
+		Results:      results,
+		ServerChecks: serverChecks,
 	}, nil
 }
 
-func (r *evalRunner) buildSummary(agentSpec *agent.AgentSpec, mcpConfig *mcpclient.MCPConfig, judge llmjudge.LLMJudge, taskConfigs []taskConfig) *EvalSummary {
+// structuredToolResults maps server names to the tools that returned structured content in any result
+func structuredToolResults(results []*EvalResult) map[string][]string {
+	structured := make(map[string][]string)
+	for _, result := range results {
+		if result.CallHistory == nil {
+			continue
+		}
+		for _, call := range result.CallHistory.ToolCalls {
+			if call.Result == nil || call.Result.StructuredContent == nil {
+				continue
+			}
+			if !slices.Contains(structured[call.ServerName], call.ToolName) {
+				structured[call.ServerName] = append(structured[call.ServerName], call.ToolName)
+			}
+		}
+	}
+
+	return structured
+}
+
+func (r *evalRunner) buildSummary(ctx context.Context, agentSpec *agent.AgentSpec, mcpConfig *mcpclient.MCPConfig, judge llmjudge.LLMJudge, taskConfigs []taskConfig) *EvalSummary {
 	summary := &EvalSummary{
 		ParallelWorkers: r.parallelWorkers,
 		Runs:            r.runs,
@@ -261,6 +349,8 @@ func (r *evalRunner) buildSummary(agentSpec *agent.AgentSpec, mcpConfig *mcpclie
 		}
 		if agentSpec != nil {
 			agentSummary.Name = agentSpec.Metadata.Name
+			// The version is informational, a failing getVersion command does not fail the eval
+			agentSummary.Version, _ = agentSpec.ResolveVersion(ctx)
 			if agentSummary.Model == "" && agentSpec.Builtin != nil {
 				agentSummary.Model = agentSpec.Builtin.Model
 			}
@@ -272,25 +362,7 @@ func (r *evalRunner) buildSummary(agentSpec *agent.AgentSpec, mcpConfig *mcpclie
 	}
 
 	// Judge
-	if modelName := judge.ModelName(); modelName != "" && modelName != "noop" {
-		judgeSummary := &JudgeSummary{Model: modelName}
-		if r.spec.Config.LLMJudge != nil && r.spec.Config.LLMJudge.AgentRef != nil {
-			ref := r.spec.Config.LLMJudge.AgentRef
-			judgeSummary.Type = ref.Type
-			judgeSummary.Path = ref.Path
-			// Resolve spec for additional details (name, ACP command)
-			if judgeSpec, err := agent.ResolveAgentRef(ref); err == nil && judgeSpec != nil {
-				judgeSummary.Name = judgeSpec.Metadata.Name
-				if judgeSummary.Model == "" && judgeSpec.Builtin != nil {
-					judgeSummary.Model = judgeSpec.Builtin.Model
-				}
-				if judgeSpec.AcpConfig != nil {
-					judgeSummary.Command = judgeSpec.AcpConfig.Cmd
-				}
-			}
-		}
-		summary.Judge = judgeSummary
-	}
+	summary.Judge = r.buildJudgeSummary(judge)
 
 	// MCP servers (sorted by name for deterministic output)
 	if mcpConfig != nil {
@@ -306,13 +378,19 @@ func (r *evalRunner) buildSummary(agentSpec *agent.AgentSpec, mcpConfig *mcpclie
 			serverType := "stdio"
 			if server.IsHttp() {
 				serverType = "http"
+			} else if server.IsSse() {
+				serverType = "sse"
 			}
-			summary.MCPServers = append(summary.MCPServers, MCPServerSummary{
+			serverSummary := MCPServerSummary{
 				Name:    name,
 				Type:    serverType,
 				URL:     sanitizeURL(server.URL),
 				Command: server.Command,
-			})
+			}
+			if server.Auth != nil {
+				serverSummary.Auth = server.Auth.Type
+			}
+			summary.MCPServers = append(summary.MCPServers, serverSummary)
 		}
 	}
 
@@ -335,6 +413,9 @@ func (r *evalRunner) buildSummary(agentSpec *agent.AgentSpec, mcpConfig *mcpclie
 		Names:    taskNames,
 		TaskSets: taskSetSummaries,
 	}
+	for _, v := range r.spec.Config.ToolOverrides {
+		summary.Evals.Variants = append(summary.Evals.Variants, v.Name)
+	}
 
 	// Timeouts
 	timeout := &TimeoutSummary{
@@ -358,6 +439,33 @@ func (r *evalRunner) buildSummary(agentSpec *agent.AgentSpec, mcpConfig *mcpclie
 	return summary
 }
 
+// buildJudgeSummary describes the configured judge, or returns nil if no real judge is configured
+func (r *evalRunner) buildJudgeSummary(judge llmjudge.LLMJudge) *JudgeSummary {
+	modelName := judge.ModelName()
+	if modelName == "" || modelName == "noop" {
+		return nil
+	}
+
+	judgeSummary := &JudgeSummary{Model: modelName}
+	if r.spec.Config.LLMJudge != nil && r.spec.Config.LLMJudge.AgentRef != nil {
+		ref := r.spec.Config.LLMJudge.AgentRef
+		judgeSummary.Type = ref.Type
+		judgeSummary.Path = ref.Path
+		// Resolve spec for additional details (name, ACP command)
+		if judgeSpec, err := agent.ResolveAgentRef(ref); err == nil && judgeSpec != nil {
+			judgeSummary.Name = judgeSpec.Metadata.Name
+			if judgeSummary.Model == "" && judgeSpec.Builtin != nil {
+				judgeSummary.Model = judgeSpec.Builtin.Model
+			}
+			if judgeSpec.AcpConfig != nil {
+				judgeSummary.Command = judgeSpec.AcpConfig.Cmd
+			}
+		}
+	}
+
+	return judgeSummary
+}
+
 func (r *evalRunner) collectTaskConfigs(rx *regexp.Regexp) ([]taskConfig, error) {
 	taskConfigs := make([]taskConfig, 0)
 	seen := make(map[string]int) // maps canonical path to index in taskConfigs for merging assertions
@@ -411,6 +519,9 @@ func (r *evalRunner) collectTaskConfigs(rx *regexp.Regexp) ([]taskConfig, error)
 				if ts.Assertions != nil {
 					taskConfigs[idx].assertions = append(taskConfigs[idx].assertions, ts.Assertions)
 				}
+				if ts.Tools != nil {
+					taskConfigs[idx].tools = append(taskConfigs[idx].tools, ts.Tools)
+				}
 				continue
 			}
 
@@ -419,10 +530,15 @@ func (r *evalRunner) collectTaskConfigs(rx *regexp.Regexp) ([]taskConfig, error)
 			if ts.Assertions != nil {
 				assertions = []*TaskAssertions{ts.Assertions}
 			}
+			var tools []*toolfilter.Config
+			if ts.Tools != nil {
+				tools = []*toolfilter.Config{ts.Tools}
+			}
 			taskConfigs = append(taskConfigs, taskConfig{
 				path:       displayPath,
 				spec:       taskSpec,
 				assertions: assertions,
+				tools:      tools,
 			})
 		}
 	}
@@ -430,6 +546,36 @@ func (r *evalRunner) collectTaskConfigs(rx *regexp.Regexp) ([]taskConfig, error)
 	return taskConfigs, nil
 }
 
+// key identifies the task for perTask server connections, variants of a task are separate tasks
+func (tc taskConfig) key() string {
+	return tc.path + "\x00" + tc.variantName()
+}
+
+func (tc taskConfig) variantName() string {
+	if tc.variant == nil {
+		return ""
+	}
+
+	return tc.variant.Name
+}
+
+// expandVariants repeats every task once per tool override variant, keeping the variants of a task together
+func expandVariants(tasks []taskConfig, variants []tooloverride.Variant) []taskConfig {
+	if len(variants) == 0 {
+		return tasks
+	}
+
+	expanded := make([]taskConfig, 0, len(tasks)*len(variants))
+	for _, tc := range tasks {
+		for i := range variants {
+			tc.variant = &variants[i]
+			expanded = append(expanded, tc)
+		}
+	}
+
+	return expanded
+}
+
 // taskGroup represents a batch of tasks to run together
 type taskGroup struct {
 	tasks    []taskConfig
@@ -480,7 +626,7 @@ func groupTasksByParallelSupport(tasks []taskConfig) []taskGroup {
 func (r *evalRunner) runTaskGroup(
 	ctx context.Context,
 	agentRunner agent.Runner,
-	mcpConfig *mcpclient.MCPConfig,
+	connections *mcpclient.Connections,
 	extResolver resolver.Resolver,
 	tasks []taskConfig,
 	workerLimit int,
@@ -500,7 +646,7 @@ func (r *evalRunner) runTaskGroup(
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			taskResults := r.executeTask(ctx, agentRunner, mcpConfig, extResolver, tc)
+			taskResults := r.executeTask(ctx, agentRunner, connections, extResolver, tc)
 
 			mu.Lock()
 			allResults = append(allResults, taskResults...)
@@ -619,7 +765,7 @@ func (r *evalRunner) resolveCleanupTimeout(tc taskConfig) (time.Duration, bool,
 func (r *evalRunner) executeTask(
 	ctx context.Context,
 	agentRunner agent.Runner,
-	mcpConfig *mcpclient.MCPConfig,
+	connections *mcpclient.Connections,
 	extResolver resolver.Resolver,
 	tc taskConfig,
 ) []*EvalResult {
@@ -627,12 +773,17 @@ func (r *evalRunner) executeTask(
 	results := make([]*EvalResult, 0, runs)
 
 	for runIdx := 0; runIdx < runs; runIdx++ {
-		result := r.executeSingleRun(ctx, agentRunner, mcpConfig, extResolver, tc)
+		result := r.executeSingleRun(ctx, agentRunner, connections, extResolver, tc)
 		result.RunIndex = runIdx
 		result.TotalRuns = runs
 		results = append(results, result)
 	}
 
+	// perTask servers are shared between the runs of the task
+	cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	_ = connections.CloseTask(cleanupCtx, tc.key())
+
 	return results
 }
 
@@ -641,20 +792,22 @@ func (r *evalRunner) executeTask(
 func (r *evalRunner) executeSingleRun(
 	ctx context.Context,
 	agentRunner agent.Runner,
-	mcpConfig *mcpclient.MCPConfig,
+	connections *mcpclient.Connections,
 	extResolver resolver.Resolver,
 	tc taskConfig,
 ) *EvalResult {
-	// Create a separate MCP manager for this task
-	taskMcpManager, err := mcpclient.NewManager(ctx, mcpConfig)
+	// Create a separate MCP manager for this task, servers are connected according to their lifecycle
+	taskMcpManager, err := connections.NewManager(ctx, tc.key())
 	if err != nil {
 		return &EvalResult{
-			TaskName:   tc.spec.Metadata.Name,
-			TaskPath:   tc.path,
-			Difficulty: tc.spec.Metadata.Difficulty,
-			Parallel:   tc.spec.Metadata.Parallel,
-			TaskPassed: false,
-			TaskError:  fmt.Sprintf("failed to create mcp manager: %v", err),
+			TaskName:    tc.spec.Metadata.Name,
+			TaskPath:    tc.path,
+			Variant:     tc.variantName(),
+			Difficulty:  tc.spec.Metadata.Difficulty,
+			Parallel:    tc.spec.Metadata.Parallel,
+			TaskPassed:  false,
+			TaskError:   fmt.Sprintf("failed to create mcp manager: %v", err),
+			FailureKind: FailureSetup,
 		}
 	}
 	defer func() {
@@ -674,12 +827,14 @@ func (r *evalRunner) executeSingleRun(
 	for alias, ext := range r.spec.Config.Extensions {
 		if err := taskExtManager.Register(alias, ext); err != nil {
 			return &EvalResult{
-				TaskName:   tc.spec.Metadata.Name,
-				TaskPath:   tc.path,
-				Difficulty: tc.spec.Metadata.Difficulty,
-				Parallel:   tc.spec.Metadata.Parallel,
-				TaskPassed: false,
-				TaskError:  fmt.Sprintf("failed to register extension %s: %v", alias, err),
+				TaskName:    tc.spec.Metadata.Name,
+				TaskPath:    tc.path,
+				Variant:     tc.variantName(),
+				Difficulty:  tc.spec.Metadata.Difficulty,
+				Parallel:    tc.spec.Metadata.Parallel,
+				TaskPassed:  false,
+				TaskError:   fmt.Sprintf("failed to register extension %s: %v", alias, err),
+				FailureKind: FailureSetup,
 			}
 		}
 	}
@@ -691,12 +846,14 @@ func (r *evalRunner) executeSingleRun(
 	result, err := r.runTask(taskCtx, agentRunner, tc)
 	if err != nil && result == nil {
 		return &EvalResult{
-			TaskName:   tc.spec.Metadata.Name,
-			TaskPath:   tc.path,
-			Difficulty: tc.spec.Metadata.Difficulty,
-			Parallel:   tc.spec.Metadata.Parallel,
-			TaskPassed: false,
-			TaskError:  err.Error(),
+			TaskName:    tc.spec.Metadata.Name,
+			TaskPath:    tc.path,
+			Variant:     tc.variantName(),
+			Difficulty:  tc.spec.Metadata.Difficulty,
+			Parallel:    tc.spec.Metadata.Parallel,
+			TaskPassed:  false,
+			TaskError:   err.Error(),
+			FailureKind: FailureSetup,
 		}
 	}
 
@@ -711,6 +868,7 @@ func (r *evalRunner) runTask(
 	result := &EvalResult{
 		TaskName:   tc.spec.Metadata.Name,
 		TaskPath:   tc.path,
+		Variant:    tc.variantName(),
 		Difficulty: tc.spec.Metadata.Difficulty,
 		Parallel:   tc.spec.Metadata.Parallel,
 	}
@@ -720,6 +878,7 @@ func (r *evalRunner) runTask(
 	if err != nil {
 		result.TaskPassed = false
 		result.TaskError = err.Error()
+		result.FailureKind = FailureSetup
 		return result, nil
 	}
 
@@ -727,6 +886,7 @@ func (r *evalRunner) runTask(
 	if err != nil {
 		result.TaskPassed = false
 		result.TaskError = err.Error()
+		result.FailureKind = FailureSetup
 		return result, nil
 	}
 
@@ -753,6 +913,7 @@ func (r *evalRunner) runTask(
 	taskRunner, manager, cleanup, err := r.setupTaskResources(taskCtx, tc, result)
 	if err != nil {
 		result.TaskPassed = false
+		result.FailureKind = FailureSetup
 		// Check if the error was caused by timeout
 		if hasTaskTimeout && taskCtx.Err() == context.DeadlineExceeded {
 			result.TimedOut = true
@@ -794,6 +955,9 @@ func (r *evalRunner) runTask(
 		result.TimedOut = true
 		result.TaskPassed = false
 		result.TaskError = fmt.Sprintf("task exceeded timeout of %s", taskTimeout)
+		if result.FailureKind == "" {
+			result.FailureKind = FailureAgent
+		}
 		r.progressCallback(ProgressEvent{
 			Type:    EventTaskTimeout,
 			Message: fmt.Sprintf("Task %s timed out after %s", tc.spec.Metadata.Name, taskTimeout),
@@ -801,6 +965,10 @@ func (r *evalRunner) runTask(
 		})
 	}
 
+	if mcpManager, ok := mcpclient.ManagerFromContext(ctx); ok {
+		result.ServerEvents = mcpManager.Events()
+	}
+
 	// Assertions and token computation use the original ctx, not taskCtx
 	r.progressCallback(ProgressEvent{
 		Type:    EventTaskAssertions,
@@ -865,7 +1033,24 @@ func (r *evalRunner) setupTaskResources(
 		return nil, nil, nil, fmt.Errorf("failed to create task runner for task '%s': %w", tc.spec.Metadata.Name, err)
 	}
 
-	manager, err := mcpproxy.NewServerManager(ctx, mcpManager)
+	proxyOpts := mcpproxy.ProxyOptions{
+		Sampler:       r.sampler,
+		ToolOverrides: tc.variant,
+	}
+	if tc.spec.Spec.Elicitation != nil {
+		responder, err := elicitation.NewResponder(ctx, tc.spec.Spec.Elicitation)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create elicitation responder for task '%s': %w", tc.spec.Metadata.Name, err)
+		}
+		proxyOpts.Elicitor = responder
+	}
+
+	proxyOpts.ToolFilter, err = toolfilter.New(append(tc.tools, tc.spec.Spec.Tools)...)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid tools config for task '%s': %w", tc.spec.Metadata.Name, err)
+	}
+
+	manager, err := mcpproxy.NewServerManager(ctx, mcpManager, proxyOpts)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to create mcp proxy server manager: %w", err)
 	}
@@ -903,7 +1088,20 @@ func (r *evalRunner) executeTaskSteps(
 		Task:    result,
 	})
 
-	agentRunner = agentRunner.WithMcpServerInfo(manager)
+	ctx = llmagent.WithRateLimitWait(ctx, r.rateLimitWait(result))
+	// only the MCP calls of the last attempt are graded
+	ctx = agent.WithRetryReset(ctx, func() error {
+		manager.ResetCallHistory()
+		return nil
+	})
+
+	agentRunner = agent.NewRetryRunner(agentRunner.WithMcpServerInfo(manager), r.retryPolicy(), func(attempt int, delay time.Duration, err error) {
+		r.progressCallback(ProgressEvent{
+			Type:    EventTaskRetry,
+			Message: fmt.Sprintf("Agent attempt %d for task %s failed with an infra error, retrying in %s: %v", attempt, result.TaskName, delay, err),
+			Task:    result,
+		})
+	})
 
 	if util.IsVerbose(ctx) {
 		fmt.Printf("  → Agent '%s' is working…\n", agentRunner.AgentName())
@@ -914,6 +1112,11 @@ func (r *evalRunner) executeTaskSteps(
 		result.TaskPassed = false
 		result.TaskError = err.Error()
 		result.AgentExecutionError = true
+		result.FailureKind = FailureAgent
+		var infraErr *agent.InfraError
+		if errors.As(err, &infraErr) {
+			result.FailureKind = FailureInfra
+		}
 		if agentOutput != nil && agentOutput.AgentDetails != nil {
 			result.TaskOutput = agent.FinalMessageFromSteps(agentOutput.AgentDetails.OutputSteps)
 		}
@@ -936,7 +1139,39 @@ func (r *evalRunner) executeTaskSteps(
 	})
 
 	verifyOutput, err := taskRunner.Verify(ctx)
+	r.applyVerifyOutput(verifyOutput, err, result)
+}
+
+// rateLimitWait reports waits for rate limits as progress events of the task, or of
+// the eval when task is nil
+func (r *evalRunner) rateLimitWait(task *EvalResult) llmagent.RateLimitWaitFunc {
+	return func(name string, waited time.Duration) {
+		msg := fmt.Sprintf("Waited %s for %s rate limit", waited.Round(time.Millisecond), name)
+		if task != nil {
+			msg = fmt.Sprintf("%s in task %s", msg, task.TaskName)
+		}
+		r.progressCallback(ProgressEvent{
+			Type:    EventRateLimited,
+			Message: msg,
+			Task:    task,
+		})
+	}
+}
+
+// retryPolicy returns the retry policy of the eval config, if any
+func (r *evalRunner) retryPolicy() *agent.RetryPolicy {
+	if r.spec == nil {
+		return nil
+	}
+	return r.spec.Config.Retry
+}
+
+// applyVerifyOutput records the verify phase output on the result and derives
+// the task verdict, judge token usage and judge reason from it.
+func (r *evalRunner) applyVerifyOutput(verifyOutput *task.PhaseOutput, err error, result *EvalResult) {
 	result.VerifyOutput = verifyOutput
+	result.JudgeTokenUsage = nil
+	result.TaskJudgeReason = ""
 
 	// Aggregate judge usage from verify phase steps
 	if verifyOutput != nil {
@@ -955,11 +1190,19 @@ func (r *evalRunner) executeTaskSteps(
 	if err != nil {
 		result.TaskPassed = false
 		result.TaskError = fmt.Sprintf("verification failed: %s", err.Error())
+		result.FailureKind = FailureVerification
+		// e.g. the model API of the judge being rate limited
+		if r.retryPolicy().IsInfraError(err) {
+			result.FailureKind = FailureInfra
+		}
 	} else if verifyOutput != nil && !verifyOutput.Success {
 		result.TaskPassed = false
 		result.TaskError = "one or more verification steps failed"
+		result.FailureKind = FailureVerification
 	} else {
 		result.TaskPassed = true
+		result.TaskError = ""
+		result.FailureKind = ""
 	}
 
 	// Extract judge results from verify phase output if LLM judge was used
@@ -997,6 +1240,10 @@ func (r *evalRunner) evaluateTaskAssertions(
 
 	// Evaluate each assertion set independently and combine results
 	callHistory := manager.GetAllCallHistory()
+	var agentDetails *task.AgentDetails
+	if result.AgentOutput != nil {
+		agentDetails = result.AgentOutput.AgentDetails
+	}
 	var combinedResults *CompositeAssertionResult
 	allPassed := true
 
@@ -1004,7 +1251,7 @@ func (r *evalRunner) evaluateTaskAssertions(
 		if assertions == nil {
 			continue
 		}
-		evaluator := NewCompositeAssertionEvaluator(assertions)
+		evaluator := NewCompositeAssertionEvaluator(assertions, agentDetails)
 		assertionResults := evaluator.Evaluate(callHistory)
 
 		if combinedResults == nil {
@@ -2,6 +2,8 @@ package eval
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"os"
 	"regexp"
 	"testing"
@@ -12,9 +14,12 @@ import (
 	extSpec "github.com/mcpchecker/mcpchecker/pkg/extension"
 	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
 	"github.com/mcpchecker/mcpchecker/pkg/mcpproxy"
+	"github.com/mcpchecker/mcpchecker/pkg/steps"
 	"github.com/mcpchecker/mcpchecker/pkg/task"
 	"github.com/mcpchecker/mcpchecker/pkg/tokens"
+	"github.com/mcpchecker/mcpchecker/pkg/tooloverride"
 	"github.com/mcpchecker/mcpchecker/pkg/util"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -767,6 +772,54 @@ func TestCollectTaskConfigsNilAssertions(t *testing.T) {
 	assert.Len(t, configs[0].assertions, 0, "nil assertions should not be added to slice")
 }
 
+func TestExpandVariants(t *testing.T) {
+	tasks := []taskConfig{
+		{path: "a.yaml", spec: &task.TaskConfig{Metadata: task.TaskMetadata{Name: "a"}}},
+		{path: "b.yaml", spec: &task.TaskConfig{Metadata: task.TaskMetadata{Name: "b"}}},
+	}
+
+	assert.Equal(t, tasks, expandVariants(tasks, nil), "tasks should be unchanged without variants")
+
+	variants := []tooloverride.Variant{{Name: "baseline"}, {Name: "concise"}}
+	expanded := expandVariants(tasks, variants)
+	require.Len(t, expanded, 4)
+
+	var got []string
+	for _, tc := range expanded {
+		got = append(got, tc.spec.Metadata.Name+"/"+tc.variantName())
+	}
+	assert.Equal(t, []string{"a/baseline", "a/concise", "b/baseline", "b/concise"}, got)
+	assert.Equal(t, "", tasks[0].variantName(), "the original tasks should not be modified")
+}
+
+func TestStructuredToolResults(t *testing.T) {
+	call := func(server, tool string, structured any) *mcpproxy.ToolCall {
+		return &mcpproxy.ToolCall{
+			CallRecord: mcpproxy.CallRecord{ServerName: server},
+			ToolName:   tool,
+			Result:     &mcp.CallToolResult{StructuredContent: structured},
+		}
+	}
+
+	results := []*EvalResult{
+		{CallHistory: &mcpproxy.CallHistory{ToolCalls: []*mcpproxy.ToolCall{
+			call("k8s", "pods_list", map[string]any{"pods": []any{}}),
+			call("k8s", "pods_delete", nil),
+			{CallRecord: mcpproxy.CallRecord{ServerName: "k8s"}, ToolName: "pods_get"},
+		}}},
+		{CallHistory: &mcpproxy.CallHistory{ToolCalls: []*mcpproxy.ToolCall{
+			call("k8s", "pods_list", map[string]any{"pods": []any{}}),
+			call("helm", "releases_list", map[string]any{}),
+		}}},
+		{},
+	}
+
+	assert.Equal(t, map[string][]string{
+		"k8s":  {"pods_list"},
+		"helm": {"releases_list"},
+	}, structuredToolResults(results))
+}
+
 func TestResolveTaskTimeout(t *testing.T) {
 	tests := map[string]struct {
 		taskTimeout        string
@@ -1032,6 +1085,7 @@ type fakeMcpManager struct{}
 
 func (f *fakeMcpManager) Get(_ string) (*mcpclient.Client, bool) { return nil, false }
 func (f *fakeMcpManager) GetAll() map[string]*mcpclient.Client   { return map[string]*mcpclient.Client{} }
+func (f *fakeMcpManager) Events() []mcpclient.ServerEvent        { return nil }
 func (f *fakeMcpManager) Close(_ context.Context) error          { return nil }
 
 // fakeExtensionManager implements client.ExtensionManager
@@ -1147,3 +1201,71 @@ func TestRunTaskCleanupRunsAfterTimeout(t *testing.T) {
 	assert.NotNil(t, result.CleanupOutput, "cleanup should run even after timeout")
 	assert.True(t, result.CleanupOutput.Success, "cleanup with no steps should succeed")
 }
+
+func TestRunTaskSetupFailureKind(t *testing.T) {
+	runner := &evalRunner{
+		spec:             &EvalSpec{Config: EvalConfig{}},
+		progressCallback: NoopProgressCallback,
+	}
+
+	taskCfg := taskConfig{
+		path: "test.yaml",
+		spec: &task.TaskConfig{
+			Metadata: task.TaskMetadata{Name: "setup-fails"},
+			Spec: &task.TaskSpec{
+				// a failing setup step mentioning a rate limit is still not an infra error
+				Setup: []*steps.StepConfig{{
+					Config: map[string]json.RawMessage{
+						"script": json.RawMessage(`{"inline":"echo 429 Too Many Requests; exit 1"}`),
+					},
+				}},
+				Prompt: &util.Step{Inline: "do something"},
+			},
+		},
+	}
+
+	result, err := runner.runTask(setupTestContext(), &fakeAgentRunner{}, taskCfg)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.False(t, result.TaskPassed)
+	assert.Equal(t, FailureSetup, result.FailureKind)
+}
+
+func TestApplyVerifyOutputFailureKind(t *testing.T) {
+	tests := map[string]struct {
+		output   *task.PhaseOutput
+		err      error
+		passed   bool
+		expected FailureKind
+	}{
+		"passed": {
+			output: &task.PhaseOutput{Success: true},
+			passed: true,
+		},
+		"verification step failed": {
+			output:   &task.PhaseOutput{Success: false},
+			expected: FailureVerification,
+		},
+		"verification error": {
+			err:      errors.New("judge returned invalid JSON"),
+			expected: FailureVerification,
+		},
+		"judge rate limited": {
+			err:      errors.New("llm judge failed: 429 Too Many Requests"),
+			expected: FailureInfra,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			runner := &evalRunner{spec: &EvalSpec{}}
+			result := &EvalResult{FailureKind: FailureVerification}
+
+			runner.applyVerifyOutput(tc.output, tc.err, result)
+
+			assert.Equal(t, tc.passed, result.TaskPassed)
+			assert.Equal(t, tc.expected, result.FailureKind)
+		})
+	}
+}
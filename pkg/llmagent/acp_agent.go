@@ -16,6 +16,21 @@ import (
 	"github.com/coder/acp-go-sdk"
 )
 
+const (
+	// ModeAct runs the tools of the MCP servers to complete the prompt
+	ModeAct acp.SessionModeId = "act"
+	// ModePlan describes how the prompt would be completed, without running tools
+	ModePlan acp.SessionModeId = "plan"
+)
+
+var sessionModes = []acp.SessionMode{
+	{Id: ModeAct, Name: "Act", Description: acp.Ptr("Run the tools of the MCP servers to complete the request")},
+	{Id: ModePlan, Name: "Plan", Description: acp.Ptr("Describe a plan without running any tools")},
+}
+
+// planModePrompt is added to the system prompt in plan mode, tools are listed instead of provided
+const planModePrompt = `You are in plan mode and cannot run any tools. Describe step by step how you would complete the request, including which tools you would call and with which arguments.`
+
 type AcpAgent interface {
 	RunACP(ctx context.Context, in io.Reader, out io.Writer) error
 }
@@ -37,24 +52,15 @@ type acpSession struct {
 	promptCancel  context.CancelFunc
 	promptGen     uint64
 	mcpClients    []McpClient
+	mode          acp.SessionModeId
 }
 
 func New(ctx context.Context, cfg Config) (AcpAgent, error) {
-	providerName, modelID, err := cfg.ParseModel()
+	model, err := NewLanguageModel(ctx, cfg.Model)
 	if err != nil {
 		return nil, err
 	}
 
-	provider, err := ResolveProvider(providerName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create provider %q: %w", providerName, err)
-	}
-
-	model, err := provider.LanguageModel(ctx, modelID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create language model %q: %w", modelID, err)
-	}
-
 	return &acpAgent{
 		model:        model,
 		systemPrompt: cfg.SystemPrompt,
@@ -137,9 +143,16 @@ func (a *acpAgent) NewSession(ctx context.Context, params acp.NewSessionRequest)
 		ctx:           sessionCtx,
 		sessionCancel: sessionCancel,
 		mcpClients:    mcpClients,
+		mode:          ModeAct,
 	}
 
-	return acp.NewSessionResponse{SessionId: sessionID}, nil
+	return acp.NewSessionResponse{
+		SessionId: sessionID,
+		Modes: &acp.SessionModeState{
+			AvailableModes: sessionModes,
+			CurrentModeId:  ModeAct,
+		},
+	}, nil
 }
 
 func (a *acpAgent) Authenticate(_ context.Context, _ acp.AuthenticateRequest) (acp.AuthenticateResponse, error) {
@@ -161,12 +174,29 @@ func (a *acpAgent) Cancel(_ context.Context, params acp.CancelNotification) erro
 	return nil
 }
 
-func (a *acpAgent) SetSessionMode(_ context.Context, _ acp.SetSessionModeRequest) (acp.SetSessionModeResponse, error) {
+func (a *acpAgent) SetSessionMode(_ context.Context, params acp.SetSessionModeRequest) (acp.SetSessionModeResponse, error) {
+	a.mu.Lock()
+	s, ok := a.sessions[params.SessionId]
+	a.mu.Unlock()
+
+	if !ok {
+		return acp.SetSessionModeResponse{}, fmt.Errorf("session %s not found", params.SessionId)
+	}
+
+	if params.ModeId != ModeAct && params.ModeId != ModePlan {
+		return acp.SetSessionModeResponse{}, fmt.Errorf("unknown mode %q", params.ModeId)
+	}
+
+	s.mu.Lock()
+	s.mode = params.ModeId
+	s.mu.Unlock()
+
 	return acp.SetSessionModeResponse{}, nil
 }
 
-func (a *acpAgent) SetSessionConfigOption(_ context.Context, _ acp.SetSessionConfigOptionRequest) (acp.SetSessionConfigOptionResponse, error) {
-	return acp.SetSessionConfigOptionResponse{}, nil
+func (a *acpAgent) SetSessionConfigOption(_ context.Context, params acp.SetSessionConfigOptionRequest) (acp.SetSessionConfigOptionResponse, error) {
+	// the agent has no config options, the mode is set with SetSessionMode
+	return acp.SetSessionConfigOptionResponse{}, fmt.Errorf("unknown config option %q", params.ConfigId)
 }
 
 func (a *acpAgent) Prompt(ctx context.Context, params acp.PromptRequest) (acp.PromptResponse, error) {
@@ -195,6 +225,7 @@ func (a *acpAgent) Prompt(ctx context.Context, params acp.PromptRequest) (acp.Pr
 	s.promptGen++
 	myGen := s.promptGen
 	s.promptCancel = promptCancel
+	mode := s.mode
 	s.mu.Unlock()
 
 	var promptBuilder strings.Builder
@@ -208,10 +239,16 @@ func (a *acpAgent) Prompt(ctx context.Context, params acp.PromptRequest) (acp.Pr
 
 	tools := ToolsFromMcpClients(s.mcpClients, a.toolInterceptorForSession(params.SessionId))
 
+	systemPrompt := a.systemPrompt
+	if mode == ModePlan {
+		systemPrompt = planSystemPrompt(systemPrompt, tools)
+		tools = nil
+	}
+
 	var opts []fantasy.AgentOption
 
-	if a.systemPrompt != "" {
-		opts = append(opts, fantasy.WithSystemPrompt(a.systemPrompt))
+	if systemPrompt != "" {
+		opts = append(opts, fantasy.WithSystemPrompt(systemPrompt))
 	}
 	if len(tools) > 0 {
 		opts = append(opts, fantasy.WithTools(tools...))
@@ -275,6 +312,30 @@ func (a *acpAgent) Prompt(ctx context.Context, params acp.PromptRequest) (acp.Pr
 	}, nil
 }
 
+// planSystemPrompt extends the system prompt for plan mode, listing the tools the
+// model could call since they are not provided to it
+func planSystemPrompt(systemPrompt string, tools []fantasy.AgentTool) string {
+	var sb strings.Builder
+	if systemPrompt != "" {
+		sb.WriteString(systemPrompt)
+		sb.WriteString("\n\n")
+	}
+	sb.WriteString(planModePrompt)
+
+	if len(tools) > 0 {
+		sb.WriteString("\n\nAvailable tools:")
+		for _, t := range tools {
+			info := t.Info()
+			fmt.Fprintf(&sb, "\n- %s", info.Name)
+			if info.Description != "" {
+				fmt.Fprintf(&sb, ": %s", info.Description)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
 func (a *acpAgent) toolInterceptorForSession(sessionId acp.SessionId) toolInterceptor {
 	return func(ctx context.Context, call fantasy.ToolCall) (bool, error) {
 		toolId := acp.ToolCallId(call.ID)
@@ -5,7 +5,9 @@ import (
 	"strings"
 	"testing"
 
+	"charm.land/fantasy"
 	"github.com/coder/acp-go-sdk"
+	mcpsdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -114,3 +116,96 @@ func TestCleanupAllSessions_NoDoubleCleanupWithCancel(t *testing.T) {
 	agent.cleanupAllSessions()
 	assert.Equal(t, 1, cleanupCount, "cleanupAllSessions should not double-cleanup")
 }
+
+func TestSetSessionMode(t *testing.T) {
+	tests := map[string]struct {
+		sessionID   acp.SessionId
+		mode        acp.SessionModeId
+		expected    acp.SessionModeId
+		errContains string
+	}{
+		"plan mode": {
+			sessionID: "test-session",
+			mode:      ModePlan,
+			expected:  ModePlan,
+		},
+		"act mode": {
+			sessionID: "test-session",
+			mode:      ModeAct,
+			expected:  ModeAct,
+		},
+		"unknown mode": {
+			sessionID:   "test-session",
+			mode:        "yolo",
+			expected:    ModeAct,
+			errContains: "unknown mode",
+		},
+		"unknown session": {
+			sessionID:   "other-session",
+			mode:        ModePlan,
+			expected:    ModeAct,
+			errContains: "not found",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			sess := &acpSession{mode: ModeAct}
+			agent := &acpAgent{
+				sessions: map[acp.SessionId]*acpSession{"test-session": sess},
+			}
+
+			_, err := agent.SetSessionMode(context.Background(), acp.SetSessionModeRequest{
+				SessionId: tc.sessionID,
+				ModeId:    tc.mode,
+			})
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, sess.mode)
+		})
+	}
+}
+
+func TestSetSessionConfigOption(t *testing.T) {
+	agent := &acpAgent{sessions: make(map[acp.SessionId]*acpSession)}
+
+	_, err := agent.SetSessionConfigOption(context.Background(), acp.SetSessionConfigOptionRequest{
+		SessionId: "test-session",
+		ConfigId:  "model",
+		Value:     "large",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown config option "model"`)
+}
+
+func TestPlanSystemPrompt(t *testing.T) {
+	tools := []fantasy.AgentTool{
+		&mcpTool{tool: mcpsdk.Tool{Name: "pods_list", Description: "List pods"}},
+		&mcpTool{tool: mcpsdk.Tool{Name: "pods_delete"}},
+	}
+
+	tests := map[string]struct {
+		systemPrompt string
+		tools        []fantasy.AgentTool
+		expected     string
+	}{
+		"with system prompt and tools": {
+			systemPrompt: "You are a Kubernetes expert.",
+			tools:        tools,
+			expected:     "You are a Kubernetes expert.\n\n" + planModePrompt + "\n\nAvailable tools:\n- pods_list: List pods\n- pods_delete",
+		},
+		"without tools": {
+			expected: planModePrompt,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, planSystemPrompt(tc.systemPrompt, tc.tools))
+		})
+	}
+}
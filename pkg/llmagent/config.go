@@ -7,7 +7,7 @@ import (
 
 type Config struct {
 	// Model specifies the provider and model in "provider:model-id" format
-	// Supported providers: openai, anthropic, gemini
+	// Supported providers: openai, anthropic, gemini, google, ollama, azure, openrouter, bedrock
 	// Example: "openai:gpt-5", "gemini:gemini-3-pro"
 	Model string
 
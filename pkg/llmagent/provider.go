@@ -1,21 +1,33 @@
 package llmagent
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
 	"sort"
+	"strings"
 
 	"charm.land/fantasy"
 	"charm.land/fantasy/providers/anthropic"
+	"charm.land/fantasy/providers/bedrock"
 	"charm.land/fantasy/providers/google"
 	"charm.land/fantasy/providers/openai"
+	"charm.land/fantasy/providers/openaicompat"
+	"charm.land/fantasy/providers/openrouter"
+	"github.com/charmbracelet/openai-go/option"
 )
 
 const (
-	anthropicProviderKey = "anthropic"
-	openaiProviderKey    = "openai"
-	geminiProviderKey    = "gemini"
-	googleProviderKey    = "google"
+	anthropicProviderKey  = "anthropic"
+	openaiProviderKey     = "openai"
+	geminiProviderKey     = "gemini"
+	googleProviderKey     = "google"
+	ollamaProviderKey     = "ollama"
+	azureProviderKey      = "azure"
+	openrouterProviderKey = "openrouter"
+	bedrockProviderKey    = "bedrock"
 
 	anthropicUseVertexEnvVar  = "ANTHROPIC_USE_VERTEX"
 	anthropicApiKeyEnvVar     = "ANTHROPIC_API_KEY"
@@ -27,6 +39,17 @@ const (
 	googleCloudLocationEnvVar = "GOOGLE_CLOUD_LOCATION"
 	openaiApiKeyEnvVar        = "OPENAI_API_KEY"
 	openaiBaseUrlEnvVar       = "OPENAI_BASE_URL"
+	ollamaHostEnvVar          = "OLLAMA_HOST"
+	ollamaApiKeyEnvVar        = "OLLAMA_API_KEY"
+	azureEndpointEnvVar       = "AZURE_OPENAI_ENDPOINT"
+	azureApiKeyEnvVar         = "AZURE_OPENAI_API_KEY"
+	azureApiVersionEnvVar     = "AZURE_OPENAI_API_VERSION"
+	openrouterApiKeyEnvVar    = "OPENROUTER_API_KEY"
+	awsRegionEnvVar           = "AWS_REGION"
+	bedrockApiKeyEnvVar       = "AWS_BEARER_TOKEN_BEDROCK"
+
+	defaultOllamaHost      = "http://localhost:11434"
+	defaultAzureApiVersion = "2024-10-21"
 )
 
 func ResolveProvider(providerName string) (fantasy.Provider, error) {
@@ -50,10 +73,14 @@ type providerBuilder interface {
 }
 
 var providerBuilders = map[string]providerBuilder{
-	anthropicProviderKey: &anthropicProviderBuilder{},
-	geminiProviderKey:    &googleProviderBuilder{providerName: geminiProviderKey},
-	googleProviderKey:    &googleProviderBuilder{providerName: googleProviderKey},
-	openaiProviderKey:    &openaiProviderBuilder{},
+	anthropicProviderKey:  &anthropicProviderBuilder{},
+	geminiProviderKey:     &googleProviderBuilder{providerName: geminiProviderKey},
+	googleProviderKey:     &googleProviderBuilder{providerName: googleProviderKey},
+	openaiProviderKey:     &openaiProviderBuilder{},
+	ollamaProviderKey:     &ollamaProviderBuilder{},
+	azureProviderKey:      &azureProviderBuilder{},
+	openrouterProviderKey: &openrouterProviderBuilder{},
+	bedrockProviderKey:    &bedrockProviderBuilder{},
 }
 
 type anthropicProviderBuilder struct{}
@@ -166,3 +193,140 @@ func (p *openaiProviderBuilder) Build() (fantasy.Provider, error) {
 
 	return openai.New(opts...)
 }
+
+// ollamaProviderBuilder uses the OpenAI compatible API of a local Ollama server, which
+// needs no API key
+type ollamaProviderBuilder struct{}
+
+func (p *ollamaProviderBuilder) Build() (fantasy.Provider, error) {
+	host := os.Getenv(ollamaHostEnvVar)
+	if host == "" {
+		host = defaultOllamaHost
+	}
+
+	// Ollama ignores the key, it is only set so the OpenAI key is not sent to the server
+	key := os.Getenv(ollamaApiKeyEnvVar)
+	if key == "" {
+		key = "ollama"
+	}
+
+	return openaicompat.New(
+		openaicompat.WithName(ollamaProviderKey),
+		openaicompat.WithBaseURL(ollamaBaseURL(host)),
+		openaicompat.WithAPIKey(key),
+	)
+}
+
+// ollamaBaseURL returns the URL of the OpenAI compatible API of an Ollama host, which
+// may be set without a scheme like OLLAMA_HOST=127.0.0.1:11434
+func ollamaBaseURL(host string) string {
+	if !strings.HasPrefix(host, "http://") && !strings.HasPrefix(host, "https://") {
+		host = "http://" + host
+	}
+
+	host = strings.TrimSuffix(host, "/")
+	if !strings.HasSuffix(host, "/v1") {
+		host += "/v1"
+	}
+
+	return host
+}
+
+// azureProviderBuilder uses an Azure OpenAI resource. The model id is the name of the
+// deployment.
+type azureProviderBuilder struct{}
+
+func (p *azureProviderBuilder) Build() (fantasy.Provider, error) {
+	endpoint := os.Getenv(azureEndpointEnvVar)
+	if endpoint == "" {
+		return nil, fmt.Errorf("provider azure requires env var %q to be set", azureEndpointEnvVar)
+	}
+
+	key := os.Getenv(azureApiKeyEnvVar)
+	if key == "" {
+		return nil, fmt.Errorf("provider azure requires env var %q to be set", azureApiKeyEnvVar)
+	}
+
+	apiVersion := os.Getenv(azureApiVersionEnvVar)
+	if apiVersion == "" {
+		apiVersion = defaultAzureApiVersion
+	}
+
+	return openai.New(
+		openai.WithName(azureProviderKey),
+		openai.WithSDKOptions(
+			option.WithBaseURL(azureBaseURL(endpoint)),
+			option.WithMiddleware(azureDeploymentMiddleware),
+			option.WithQueryAdd("api-version", apiVersion),
+			option.WithHeader("api-key", key),
+			// do not send the OpenAI key to Azure
+			option.WithHeaderDel("authorization"),
+		),
+	)
+}
+
+// azureBaseURL returns the base URL of the OpenAI API of an Azure endpoint, like
+// https://my-resource.openai.azure.com
+func azureBaseURL(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	endpoint = strings.TrimSuffix(endpoint, "/openai")
+	return endpoint + "/openai/"
+}
+
+// azureDeploymentMiddleware routes requests to the deployment named by the model of the request
+func azureDeploymentMiddleware(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+	if req.Body == nil || req.GetBody == nil {
+		return next(req)
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	defer body.Close()
+
+	var payload struct {
+		Model string `json:"model"`
+	}
+	if err := json.NewDecoder(body).Decode(&payload); err != nil || payload.Model == "" {
+		return next(req)
+	}
+
+	prefix, route, ok := strings.Cut(req.URL.Path, "/openai/")
+	if !ok {
+		return next(req)
+	}
+	req.URL.Path = prefix + "/openai/deployments/" + url.PathEscape(payload.Model) + "/" + route
+
+	return next(req)
+}
+
+type openrouterProviderBuilder struct{}
+
+func (p *openrouterProviderBuilder) Build() (fantasy.Provider, error) {
+	key := os.Getenv(openrouterApiKeyEnvVar)
+	if key == "" {
+		return nil, fmt.Errorf("provider openrouter requires env var %q to be set", openrouterApiKeyEnvVar)
+	}
+
+	return openrouter.New(openrouter.WithAPIKey(key))
+}
+
+// bedrockProviderBuilder uses Anthropic models on AWS Bedrock. Without a Bedrock API
+// key, the default AWS credential chain is used, e.g. AWS_PROFILE or AWS_ACCESS_KEY_ID.
+type bedrockProviderBuilder struct{}
+
+func (p *bedrockProviderBuilder) Build() (fantasy.Provider, error) {
+	if os.Getenv(awsRegionEnvVar) == "" {
+		return nil, fmt.Errorf("provider bedrock requires env var %q to be set", awsRegionEnvVar)
+	}
+
+	opts := []bedrock.Option{}
+
+	key := os.Getenv(bedrockApiKeyEnvVar)
+	if key != "" {
+		opts = append(opts, bedrock.WithAPIKey(key))
+	}
+
+	return bedrock.New(opts...)
+}
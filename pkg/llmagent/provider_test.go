@@ -1,9 +1,13 @@
 package llmagent
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"charm.land/fantasy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -64,6 +68,14 @@ func clearProviderEnv() {
 		googleCloudLocationEnvVar,
 		openaiApiKeyEnvVar,
 		openaiBaseUrlEnvVar,
+		ollamaHostEnvVar,
+		ollamaApiKeyEnvVar,
+		azureEndpointEnvVar,
+		azureApiKeyEnvVar,
+		azureApiVersionEnvVar,
+		openrouterApiKeyEnvVar,
+		awsRegionEnvVar,
+		bedrockApiKeyEnvVar,
 	}
 	for _, v := range envVars {
 		os.Unsetenv(v)
@@ -281,3 +293,202 @@ func TestOpenAIProviderBuilder(t *testing.T) {
 		})
 	}
 }
+
+func TestOllamaProviderBuilder(t *testing.T) {
+	tests := map[string]struct {
+		setupEnv func()
+	}{
+		"no env vars uses the local server": {
+			setupEnv: func() {},
+		},
+		"with host": {
+			setupEnv: func() {
+				os.Setenv(ollamaHostEnvVar, "127.0.0.1:11434")
+			},
+		},
+		"with API key": {
+			setupEnv: func() {
+				os.Setenv(ollamaApiKeyEnvVar, "secret")
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			clearProviderEnv()
+			defer clearProviderEnv()
+			tc.setupEnv()
+
+			builder := &ollamaProviderBuilder{}
+			provider, err := builder.Build()
+
+			require.NoError(t, err)
+			assert.NotNil(t, provider)
+		})
+	}
+}
+
+func TestOllamaBaseURL(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:11434":      "http://localhost:11434/v1",
+		"127.0.0.1:11434":             "http://127.0.0.1:11434/v1",
+		"https://ollama.example.com/": "https://ollama.example.com/v1",
+		"http://localhost:11434/v1":   "http://localhost:11434/v1",
+	}
+
+	for host, expected := range tests {
+		t.Run(host, func(t *testing.T) {
+			assert.Equal(t, expected, ollamaBaseURL(host))
+		})
+	}
+}
+
+func TestAzureProviderBuilder(t *testing.T) {
+	tests := map[string]struct {
+		setupEnv    func()
+		expectErr   bool
+		errContains string
+	}{
+		"missing endpoint": {
+			setupEnv: func() {
+				os.Setenv(azureApiKeyEnvVar, "test-key")
+			},
+			expectErr:   true,
+			errContains: azureEndpointEnvVar,
+		},
+		"missing API key": {
+			setupEnv: func() {
+				os.Setenv(azureEndpointEnvVar, "https://my-resource.openai.azure.com")
+			},
+			expectErr:   true,
+			errContains: azureApiKeyEnvVar,
+		},
+		"endpoint and API key": {
+			setupEnv: func() {
+				os.Setenv(azureEndpointEnvVar, "https://my-resource.openai.azure.com")
+				os.Setenv(azureApiKeyEnvVar, "test-key")
+			},
+		},
+		"with API version": {
+			setupEnv: func() {
+				os.Setenv(azureEndpointEnvVar, "https://my-resource.openai.azure.com")
+				os.Setenv(azureApiKeyEnvVar, "test-key")
+				os.Setenv(azureApiVersionEnvVar, "2025-01-01-preview")
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			clearProviderEnv()
+			defer clearProviderEnv()
+			tc.setupEnv()
+
+			builder := &azureProviderBuilder{}
+			provider, err := builder.Build()
+
+			if tc.expectErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, provider)
+		})
+	}
+}
+
+func TestAzureProviderRequests(t *testing.T) {
+	clearProviderEnv()
+	defer clearProviderEnv()
+
+	var req *http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","object":"chat.completion","choices":[{"index":0,"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`))
+	}))
+	defer server.Close()
+
+	os.Setenv(openaiApiKeyEnvVar, "sk-openai")
+	os.Setenv(azureEndpointEnvVar, server.URL+"/")
+	os.Setenv(azureApiKeyEnvVar, "azure-key")
+	os.Setenv(azureApiVersionEnvVar, "2025-01-01-preview")
+
+	provider, err := (&azureProviderBuilder{}).Build()
+	require.NoError(t, err)
+
+	model, err := provider.LanguageModel(context.Background(), "my-deployment")
+	require.NoError(t, err)
+
+	_, err = model.Generate(context.Background(), fantasy.Call{
+		Prompt: fantasy.Prompt{fantasy.NewUserMessage("hello")},
+	})
+	require.NoError(t, err)
+
+	require.NotNil(t, req)
+	assert.Equal(t, "/openai/deployments/my-deployment/chat/completions", req.URL.Path)
+	assert.Equal(t, "2025-01-01-preview", req.URL.Query().Get("api-version"))
+	assert.Equal(t, "azure-key", req.Header.Get("api-key"))
+	assert.Empty(t, req.Header.Get("Authorization"))
+}
+
+func TestOpenRouterProviderBuilder(t *testing.T) {
+	clearProviderEnv()
+	defer clearProviderEnv()
+
+	_, err := (&openrouterProviderBuilder{}).Build()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), openrouterApiKeyEnvVar)
+
+	os.Setenv(openrouterApiKeyEnvVar, "sk-or-test")
+	provider, err := (&openrouterProviderBuilder{}).Build()
+	require.NoError(t, err)
+	assert.NotNil(t, provider)
+}
+
+func TestBedrockProviderBuilder(t *testing.T) {
+	tests := map[string]struct {
+		setupEnv    func()
+		expectErr   bool
+		errContains string
+	}{
+		"missing region": {
+			setupEnv:    func() {},
+			expectErr:   true,
+			errContains: awsRegionEnvVar,
+		},
+		"region uses the AWS credential chain": {
+			setupEnv: func() {
+				os.Setenv(awsRegionEnvVar, "us-west-2")
+			},
+		},
+		"region and Bedrock API key": {
+			setupEnv: func() {
+				os.Setenv(awsRegionEnvVar, "us-west-2")
+				os.Setenv(bedrockApiKeyEnvVar, "test-token")
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			clearProviderEnv()
+			defer clearProviderEnv()
+			tc.setupEnv()
+
+			builder := &bedrockProviderBuilder{}
+			provider, err := builder.Build()
+
+			if tc.expectErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, provider)
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package llmagent
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"math"
+	"sync"
+	"time"
+
+	"charm.land/fantasy"
+)
+
+// RateLimit caps the requests and tokens per minute sent to a model provider
+type RateLimit struct {
+	RequestsPerMinute int `json:"requestsPerMinute,omitempty"`
+	TokensPerMinute   int `json:"tokensPerMinute,omitempty"`
+}
+
+func (l *RateLimit) Validate() error {
+	if l.RequestsPerMinute < 0 {
+		return fmt.Errorf("requestsPerMinute must be >= 0, got %d", l.RequestsPerMinute)
+	}
+	if l.TokensPerMinute < 0 {
+		return fmt.Errorf("tokensPerMinute must be >= 0, got %d", l.TokensPerMinute)
+	}
+	if l.RequestsPerMinute == 0 && l.TokensPerMinute == 0 {
+		return fmt.Errorf("at least one of requestsPerMinute or tokensPerMinute must be set")
+	}
+
+	return nil
+}
+
+// RateLimiter is a token bucket of requests and tokens that fills up at the rate of
+// its limit, up to one minute's worth. Requests wait until a request is available and
+// the token bucket is not in debt. The tokens a request used are only known after
+// it completed, so they are taken afterwards and may put the bucket in debt.
+type RateLimiter struct {
+	limit RateLimit
+
+	mu       sync.Mutex
+	requests float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+}
+
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	return &RateLimiter{
+		limit:    limit,
+		requests: float64(limit.RequestsPerMinute),
+		tokens:   float64(limit.TokensPerMinute),
+		last:     time.Now(),
+		now:      time.Now,
+	}
+}
+
+// Wait blocks until a request may be sent, takes it from the bucket, and returns how
+// long it waited
+func (l *RateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	if l == nil {
+		return 0, nil
+	}
+
+	var waited time.Duration
+	for {
+		delay := l.reserve()
+		if delay == 0 {
+			return waited, nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return waited, ctx.Err()
+		case <-timer.C:
+			waited += delay
+		}
+	}
+}
+
+// Consume takes the tokens a request used from the bucket
+func (l *RateLimiter) Consume(tokens int64) {
+	if l == nil || l.limit.TokensPerMinute == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+	l.tokens -= float64(tokens)
+}
+
+// reserve takes a request from the bucket and returns 0, or returns how long to wait
+// until one is available
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill()
+
+	var delay time.Duration
+	if l.limit.RequestsPerMinute > 0 && l.requests < 1 {
+		delay = max(delay, perMinuteDelay(1-l.requests, l.limit.RequestsPerMinute))
+	}
+	if l.limit.TokensPerMinute > 0 && l.tokens < 0 {
+		delay = max(delay, perMinuteDelay(-l.tokens, l.limit.TokensPerMinute))
+	}
+	if delay > 0 {
+		return delay
+	}
+
+	if l.limit.RequestsPerMinute > 0 {
+		l.requests--
+	}
+
+	return 0
+}
+
+func (l *RateLimiter) refill() {
+	now := l.now()
+	elapsed := now.Sub(l.last).Minutes()
+	l.last = now
+
+	if l.limit.RequestsPerMinute > 0 {
+		l.requests = math.Min(l.requests+elapsed*float64(l.limit.RequestsPerMinute), float64(l.limit.RequestsPerMinute))
+	}
+	if l.limit.TokensPerMinute > 0 {
+		l.tokens = math.Min(l.tokens+elapsed*float64(l.limit.TokensPerMinute), float64(l.limit.TokensPerMinute))
+	}
+}
+
+// perMinuteDelay returns how long it takes to refill amount at perMinute, rounded up to
+// a millisecond so callers never busy loop
+func perMinuteDelay(amount float64, perMinute int) time.Duration {
+	d := time.Duration(amount / float64(perMinute) * float64(time.Minute))
+	return max(d.Round(time.Millisecond), time.Millisecond)
+}
+
+// RateLimiters holds the rate limiters of the model providers, shared by every model
+// created with a context that carries them
+type RateLimiters struct {
+	limiters map[string]*RateLimiter
+}
+
+// NewRateLimiters creates a rate limiter per provider, e.g. anthropic or openai
+func NewRateLimiters(limits map[string]RateLimit) *RateLimiters {
+	limiters := make(map[string]*RateLimiter, len(limits))
+	for provider, limit := range limits {
+		limiters[provider] = NewRateLimiter(limit)
+	}
+
+	return &RateLimiters{limiters: limiters}
+}
+
+// For returns the rate limiter of the provider, or nil if it is not limited
+func (r *RateLimiters) For(provider string) *RateLimiter {
+	if r == nil {
+		return nil
+	}
+	return r.limiters[provider]
+}
+
+// RateLimitWaitFunc is called after a request waited for a rate limit. name is the
+// provider, or judge for the rate limit of the LLM judge.
+type RateLimitWaitFunc func(name string, waited time.Duration)
+
+type rateLimitersKey struct{}
+
+type rateLimitWaitKey struct{}
+
+// WithRateLimiters returns a context whose models are limited by the rate limiters
+func WithRateLimiters(ctx context.Context, limiters *RateLimiters) context.Context {
+	return context.WithValue(ctx, rateLimitersKey{}, limiters)
+}
+
+// RateLimitersFromContext returns the rate limiters of the context, if any
+func RateLimitersFromContext(ctx context.Context) (*RateLimiters, bool) {
+	limiters, ok := ctx.Value(rateLimitersKey{}).(*RateLimiters)
+	return limiters, ok && limiters != nil
+}
+
+// WithRateLimitWait returns a context whose models report waits for rate limits to fn
+func WithRateLimitWait(ctx context.Context, fn RateLimitWaitFunc) context.Context {
+	return context.WithValue(ctx, rateLimitWaitKey{}, fn)
+}
+
+// RateLimitWaitFromContext returns the function that waits for rate limits are reported to, if any
+func RateLimitWaitFromContext(ctx context.Context) (RateLimitWaitFunc, bool) {
+	fn, ok := ctx.Value(rateLimitWaitKey{}).(RateLimitWaitFunc)
+	return fn, ok && fn != nil
+}
+
+// rateLimitedModel waits for the rate limiter of its provider before every request
+type rateLimitedModel struct {
+	fantasy.LanguageModel
+	provider string
+	limiter  *RateLimiter
+	onWait   RateLimitWaitFunc
+}
+
+// withRateLimit limits model by the rate limiter of the provider in ctx. Waits are
+// reported to the wait function of ctx, so the model must be created per task.
+func withRateLimit(ctx context.Context, provider string, model fantasy.LanguageModel) fantasy.LanguageModel {
+	limiters, _ := RateLimitersFromContext(ctx)
+	limiter := limiters.For(provider)
+	if limiter == nil {
+		return model
+	}
+
+	onWait, _ := RateLimitWaitFromContext(ctx)
+
+	return &rateLimitedModel{
+		LanguageModel: model,
+		provider:      provider,
+		limiter:       limiter,
+		onWait:        onWait,
+	}
+}
+
+func (m *rateLimitedModel) wait(ctx context.Context) error {
+	waited, err := m.limiter.Wait(ctx)
+	if waited > 0 && m.onWait != nil {
+		m.onWait(m.provider, waited)
+	}
+	return err
+}
+
+func (m *rateLimitedModel) consume(usage fantasy.Usage) {
+	m.limiter.Consume(usage.InputTokens + usage.OutputTokens)
+}
+
+func (m *rateLimitedModel) Generate(ctx context.Context, call fantasy.Call) (*fantasy.Response, error) {
+	if err := m.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := m.LanguageModel.Generate(ctx, call)
+	if resp != nil {
+		m.consume(resp.Usage)
+	}
+
+	return resp, err
+}
+
+func (m *rateLimitedModel) Stream(ctx context.Context, call fantasy.Call) (fantasy.StreamResponse, error) {
+	if err := m.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	stream, err := m.LanguageModel.Stream(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return consumeStream(stream, func(part fantasy.StreamPart) {
+		if part.Type == fantasy.StreamPartTypeFinish {
+			m.consume(part.Usage)
+		}
+	}), nil
+}
+
+func (m *rateLimitedModel) GenerateObject(ctx context.Context, call fantasy.ObjectCall) (*fantasy.ObjectResponse, error) {
+	if err := m.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	resp, err := m.LanguageModel.GenerateObject(ctx, call)
+	if resp != nil {
+		m.consume(resp.Usage)
+	}
+
+	return resp, err
+}
+
+func (m *rateLimitedModel) StreamObject(ctx context.Context, call fantasy.ObjectCall) (fantasy.ObjectStreamResponse, error) {
+	if err := m.wait(ctx); err != nil {
+		return nil, err
+	}
+
+	stream, err := m.LanguageModel.StreamObject(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	return consumeStream(stream, func(part fantasy.ObjectStreamPart) {
+		if part.Type == fantasy.ObjectStreamPartTypeFinish {
+			m.consume(part.Usage)
+		}
+	}), nil
+}
+
+// consumeStream passes every part of stream to fn before yielding it
+func consumeStream[T any](stream iter.Seq[T], fn func(T)) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for part := range stream {
+			fn(part)
+			if !yield(part) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package llmagent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"charm.land/fantasy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock for rate limiters that only moves when advanced
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestRateLimiter(limit RateLimit) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewRateLimiter(limit)
+	l.now = clock.Now
+	l.last = clock.now
+	return l, clock
+}
+
+type usageModel struct {
+	fantasy.LanguageModel
+	usage fantasy.Usage
+	calls int
+}
+
+func (m *usageModel) Generate(ctx context.Context, call fantasy.Call) (*fantasy.Response, error) {
+	m.calls++
+	return &fantasy.Response{Usage: m.usage}, nil
+}
+
+func (m *usageModel) Stream(ctx context.Context, call fantasy.Call) (fantasy.StreamResponse, error) {
+	m.calls++
+	return func(yield func(fantasy.StreamPart) bool) {
+		if !yield(fantasy.StreamPart{Type: fantasy.StreamPartTypeTextDelta, Delta: "hi"}) {
+			return
+		}
+		yield(fantasy.StreamPart{Type: fantasy.StreamPartTypeFinish, Usage: m.usage})
+	}, nil
+}
+
+func TestRateLimitValidate(t *testing.T) {
+	tests := map[string]struct {
+		limit       RateLimit
+		expectedErr string
+	}{
+		"requests": {
+			limit: RateLimit{RequestsPerMinute: 60},
+		},
+		"tokens": {
+			limit: RateLimit{TokensPerMinute: 10000},
+		},
+		"empty": {
+			expectedErr: "at least one of requestsPerMinute or tokensPerMinute must be set",
+		},
+		"negative requests": {
+			limit:       RateLimit{RequestsPerMinute: -1},
+			expectedErr: "requestsPerMinute must be >= 0",
+		},
+		"negative tokens": {
+			limit:       RateLimit{TokensPerMinute: -1},
+			expectedErr: "tokensPerMinute must be >= 0",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.limit.Validate()
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestRateLimiterReserve(t *testing.T) {
+	t.Run("requests per minute", func(t *testing.T) {
+		l, clock := newTestRateLimiter(RateLimit{RequestsPerMinute: 2})
+
+		assert.Zero(t, l.reserve())
+		assert.Zero(t, l.reserve())
+		assert.Equal(t, 30*time.Second, l.reserve())
+
+		clock.now = clock.now.Add(30 * time.Second)
+		assert.Zero(t, l.reserve())
+		assert.Equal(t, 30*time.Second, l.reserve())
+	})
+
+	t.Run("bucket holds one minute", func(t *testing.T) {
+		l, clock := newTestRateLimiter(RateLimit{RequestsPerMinute: 1})
+
+		clock.now = clock.now.Add(time.Hour)
+		assert.Zero(t, l.reserve())
+		assert.Equal(t, time.Minute, l.reserve())
+	})
+
+	t.Run("tokens per minute", func(t *testing.T) {
+		l, clock := newTestRateLimiter(RateLimit{TokensPerMinute: 1000})
+
+		assert.Zero(t, l.reserve())
+		l.Consume(1500)
+		assert.Equal(t, 30*time.Second, l.reserve())
+
+		clock.now = clock.now.Add(30 * time.Second)
+		assert.Zero(t, l.reserve())
+	})
+
+	t.Run("waits for the longer limit", func(t *testing.T) {
+		l, _ := newTestRateLimiter(RateLimit{RequestsPerMinute: 1, TokensPerMinute: 600})
+
+		assert.Zero(t, l.reserve())
+		l.Consume(1200)
+		assert.Equal(t, time.Minute, l.reserve())
+	})
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	t.Run("nil limiter does not wait", func(t *testing.T) {
+		var l *RateLimiter
+		waited, err := l.Wait(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, waited)
+		l.Consume(100)
+	})
+
+	t.Run("waits until a request is available", func(t *testing.T) {
+		// one request every 50ms, with an empty bucket
+		l := NewRateLimiter(RateLimit{RequestsPerMinute: 1200})
+		l.requests = 0
+
+		waited, err := l.Wait(context.Background())
+		require.NoError(t, err)
+		assert.Greater(t, waited, time.Duration(0))
+	})
+
+	t.Run("stops waiting when the context is done", func(t *testing.T) {
+		l := NewRateLimiter(RateLimit{RequestsPerMinute: 1})
+		_, err := l.Wait(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = l.Wait(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestWithRateLimit(t *testing.T) {
+	t.Run("providers without a limit are not wrapped", func(t *testing.T) {
+		model := &usageModel{}
+		ctx := WithRateLimiters(context.Background(), NewRateLimiters(map[string]RateLimit{
+			"openai": {RequestsPerMinute: 10},
+		}))
+
+		assert.Same(t, model, withRateLimit(ctx, "anthropic", model))
+		assert.Same(t, model, withRateLimit(context.Background(), "openai", model))
+	})
+
+	t.Run("requests take tokens and report waits", func(t *testing.T) {
+		limiters := NewRateLimiters(map[string]RateLimit{
+			"openai": {TokensPerMinute: 60000},
+		})
+		var waits []string
+		ctx := WithRateLimiters(context.Background(), limiters)
+		ctx = WithRateLimitWait(ctx, func(name string, waited time.Duration) {
+			waits = append(waits, name)
+		})
+
+		// the first request puts the bucket 50 tokens, or 50ms, in debt
+		inner := &usageModel{usage: fantasy.Usage{InputTokens: 40000, OutputTokens: 20050}}
+		model := withRateLimit(ctx, "openai", inner)
+
+		_, err := model.Generate(ctx, fantasy.Call{})
+		require.NoError(t, err)
+		assert.Empty(t, waits)
+		assert.Less(t, limiters.For("openai").tokens, 0.0)
+
+		stream, err := model.Stream(ctx, fantasy.Call{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"openai"}, waits)
+
+		var parts int
+		for range stream {
+			parts++
+		}
+		assert.Equal(t, 2, parts)
+		assert.Equal(t, 2, inner.calls)
+		assert.Less(t, limiters.For("openai").tokens, -50000.0)
+	})
+}
@@ -0,0 +1,119 @@
+package llmagent
+
+import (
+	"context"
+	"fmt"
+
+	"charm.land/fantasy"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Sampler answers MCP sampling requests with a language model, for agents that
+// do not support sampling themselves.
+type Sampler struct {
+	model fantasy.LanguageModel
+}
+
+// NewSampler creates a sampler for a model in "provider:model-id" format
+func NewSampler(ctx context.Context, model string) (*Sampler, error) {
+	lm, err := NewLanguageModel(ctx, model)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sampler{model: lm}, nil
+}
+
+// NewLanguageModel resolves a model in "provider:model-id" format, limited by the
+// rate limiter of the provider in ctx, if any
+func NewLanguageModel(ctx context.Context, model string) (fantasy.LanguageModel, error) {
+	cfg := Config{Model: model}
+	providerName, modelID, err := cfg.ParseModel()
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := ResolveProvider(providerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create provider %q: %w", providerName, err)
+	}
+
+	lm, err := provider.LanguageModel(ctx, modelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create language model %q: %w", modelID, err)
+	}
+
+	return withRateLimit(ctx, providerName, lm), nil
+}
+
+// CreateMessage implements sampling/createMessage. Only text content is supported.
+func (s *Sampler) CreateMessage(ctx context.Context, params *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+	call, err := samplingCall(params)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.model.Generate(ctx, call)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate sampling response: %w", err)
+	}
+
+	return &mcp.CreateMessageResult{
+		Content:    &mcp.TextContent{Text: res.Content.Text()},
+		Model:      s.model.Model(),
+		Role:       "assistant",
+		StopReason: samplingStopReason(res.FinishReason),
+	}, nil
+}
+
+func samplingCall(params *mcp.CreateMessageParams) (fantasy.Call, error) {
+	prompt := make(fantasy.Prompt, 0, len(params.Messages)+1)
+	if params.SystemPrompt != "" {
+		prompt = append(prompt, fantasy.NewSystemMessage(params.SystemPrompt))
+	}
+
+	for i, m := range params.Messages {
+		if m == nil {
+			continue
+		}
+		text, ok := m.Content.(*mcp.TextContent)
+		if !ok {
+			return fantasy.Call{}, fmt.Errorf("messages[%d]: unsupported content type %T, only text is supported", i, m.Content)
+		}
+
+		role := fantasy.MessageRoleUser
+		if m.Role == "assistant" {
+			role = fantasy.MessageRoleAssistant
+		}
+
+		prompt = append(prompt, fantasy.Message{
+			Role:    role,
+			Content: []fantasy.MessagePart{fantasy.TextPart{Text: text.Text}},
+		})
+	}
+
+	call := fantasy.Call{Prompt: prompt}
+	if params.MaxTokens > 0 {
+		maxTokens := params.MaxTokens
+		call.MaxOutputTokens = &maxTokens
+	}
+	if params.Temperature != 0 {
+		temperature := params.Temperature
+		call.Temperature = &temperature
+	}
+
+	return call, nil
+}
+
+func samplingStopReason(reason fantasy.FinishReason) string {
+	switch reason {
+	case fantasy.FinishReasonStop:
+		return "endTurn"
+	case fantasy.FinishReasonLength:
+		return "maxTokens"
+	case fantasy.FinishReasonToolCalls:
+		return "toolUse"
+	default:
+		return ""
+	}
+}
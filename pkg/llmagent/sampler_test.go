@@ -0,0 +1,88 @@
+package llmagent
+
+import (
+	"testing"
+
+	"charm.land/fantasy"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplingCall(t *testing.T) {
+	tests := map[string]struct {
+		params      *mcp.CreateMessageParams
+		expectRoles []fantasy.MessageRole
+		expectMax   *int64
+		expectTemp  *float64
+		expectErr   string
+	}{
+		"system prompt and conversation": {
+			params: &mcp.CreateMessageParams{
+				SystemPrompt: "be brief",
+				MaxTokens:    100,
+				Temperature:  0.2,
+				Messages: []*mcp.SamplingMessage{
+					{Role: "user", Content: &mcp.TextContent{Text: "hi"}},
+					{Role: "assistant", Content: &mcp.TextContent{Text: "hello"}},
+					{Role: "user", Content: &mcp.TextContent{Text: "summarize"}},
+				},
+			},
+			expectRoles: []fantasy.MessageRole{
+				fantasy.MessageRoleSystem,
+				fantasy.MessageRoleUser,
+				fantasy.MessageRoleAssistant,
+				fantasy.MessageRoleUser,
+			},
+			expectMax:  ptr(int64(100)),
+			expectTemp: ptr(0.2),
+		},
+		"no limits": {
+			params: &mcp.CreateMessageParams{
+				Messages: []*mcp.SamplingMessage{
+					{Role: "user", Content: &mcp.TextContent{Text: "hi"}},
+				},
+			},
+			expectRoles: []fantasy.MessageRole{fantasy.MessageRoleUser},
+		},
+		"image content is rejected": {
+			params: &mcp.CreateMessageParams{
+				Messages: []*mcp.SamplingMessage{
+					{Role: "user", Content: &mcp.ImageContent{MIMEType: "image/png"}},
+				},
+			},
+			expectErr: "only text is supported",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			call, err := samplingCall(tc.params)
+			if tc.expectErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+
+			roles := make([]fantasy.MessageRole, 0, len(call.Prompt))
+			for _, m := range call.Prompt {
+				roles = append(roles, m.Role)
+			}
+			assert.Equal(t, tc.expectRoles, roles)
+			assert.Equal(t, tc.expectMax, call.MaxOutputTokens)
+			assert.Equal(t, tc.expectTemp, call.Temperature)
+		})
+	}
+}
+
+func TestSamplingStopReason(t *testing.T) {
+	assert.Equal(t, "endTurn", samplingStopReason(fantasy.FinishReasonStop))
+	assert.Equal(t, "maxTokens", samplingStopReason(fantasy.FinishReasonLength))
+	assert.Equal(t, "toolUse", samplingStopReason(fantasy.FinishReasonToolCalls))
+	assert.Equal(t, "", samplingStopReason(fantasy.FinishReasonError))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
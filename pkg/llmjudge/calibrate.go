@@ -0,0 +1,253 @@
+package llmjudge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/mcpchecker/mcpchecker/pkg/tokens"
+	"github.com/mcpchecker/mcpchecker/pkg/util"
+)
+
+const (
+	KindCalibrationDataset = "JudgeCalibrationDataset"
+
+	VerdictPass = "pass"
+	VerdictFail = "fail"
+)
+
+// CalibrationDataset is a set of human-labeled examples used to measure how well
+// the configured judge agrees with human verdicts.
+type CalibrationDataset struct {
+	util.TypeMeta `json:",inline"`
+	Metadata      CalibrationMetadata  `json:"metadata"`
+	Examples      []CalibrationExample `json:"examples"`
+}
+
+type CalibrationMetadata struct {
+	Name string `json:"name"`
+}
+
+// CalibrationExample is a single judged response with its human verdict
+type CalibrationExample struct {
+	// ID identifies the example in reports, defaults to its index
+	ID        string `json:"id,omitempty"`
+	Prompt    string `json:"prompt"`
+	Output    string `json:"output"`
+	Reference string `json:"reference"`
+	// Mode is "contains" (default) or "exact"
+	Mode string `json:"mode,omitempty"`
+	// Verdict is the human label: "pass" or "fail"
+	Verdict string `json:"verdict"`
+}
+
+// StepConfig converts the example into the judge config used by llmJudge steps
+func (e *CalibrationExample) StepConfig() (*LLMJudgeStepConfig, error) {
+	switch strings.ToUpper(e.Mode) {
+	case "", EvaluationModeContains:
+		return &LLMJudgeStepConfig{Contains: e.Reference}, nil
+	case EvaluationModeExact:
+		return &LLMJudgeStepConfig{Exact: e.Reference}, nil
+	default:
+		return nil, fmt.Errorf("unknown mode %q: must be one of contains or exact", e.Mode)
+	}
+}
+
+// HumanPassed returns the human verdict as a boolean
+func (e *CalibrationExample) HumanPassed() bool {
+	return strings.EqualFold(e.Verdict, VerdictPass)
+}
+
+func (d *CalibrationDataset) Validate() error {
+	if len(d.Examples) == 0 {
+		return fmt.Errorf("dataset must contain at least one example")
+	}
+
+	seen := make(map[string]struct{}, len(d.Examples))
+	for i := range d.Examples {
+		ex := &d.Examples[i]
+		if ex.ID == "" {
+			ex.ID = fmt.Sprintf("%d", i)
+		}
+		if _, ok := seen[ex.ID]; ok {
+			return fmt.Errorf("examples[%d]: duplicate id %q", i, ex.ID)
+		}
+		seen[ex.ID] = struct{}{}
+
+		if ex.Output == "" {
+			return fmt.Errorf("examples[%d]: output must be set", i)
+		}
+		if ex.Reference == "" {
+			return fmt.Errorf("examples[%d]: reference must be set", i)
+		}
+		if !strings.EqualFold(ex.Verdict, VerdictPass) && !strings.EqualFold(ex.Verdict, VerdictFail) {
+			return fmt.Errorf("examples[%d]: verdict must be one of %s or %s, got %q", i, VerdictPass, VerdictFail, ex.Verdict)
+		}
+		if _, err := ex.StepConfig(); err != nil {
+			return fmt.Errorf("examples[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// ReadCalibrationDataset parses a calibration dataset from YAML or JSON
+func ReadCalibrationDataset(data []byte) (*CalibrationDataset, error) {
+	ds := &CalibrationDataset{}
+	if err := yaml.Unmarshal(data, ds); err != nil {
+		return nil, err
+	}
+
+	if err := ds.TypeMeta.Validate(KindCalibrationDataset); err != nil {
+		return nil, err
+	}
+
+	if err := ds.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid calibration dataset: %w", err)
+	}
+
+	return ds, nil
+}
+
+func CalibrationDatasetFromFile(path string) (*CalibrationDataset, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%s' for calibration dataset: %w", path, err)
+	}
+
+	return ReadCalibrationDataset(data)
+}
+
+// CalibrationReport compares judge verdicts with human verdicts.
+// A "pass" verdict is treated as the positive class.
+type CalibrationReport struct {
+	Judge          string `json:"judge"`
+	Total          int    `json:"total"`
+	TruePositives  int    `json:"truePositives"`
+	FalsePositives int    `json:"falsePositives"`
+	TrueNegatives  int    `json:"trueNegatives"`
+	FalseNegatives int    `json:"falseNegatives"`
+	Errors         int    `json:"errors"`
+
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	Accuracy  float64 `json:"accuracy"`
+
+	Usage *tokens.Usage `json:"usage,omitempty"`
+
+	// Disagreements lists every example where the judge disagreed with the human label or failed
+	Disagreements []CalibrationOutcome `json:"disagreements,omitempty"`
+}
+
+// CalibrationOutcome is the judge result for a single example
+type CalibrationOutcome struct {
+	ID              string `json:"id"`
+	Mode            string `json:"mode"`
+	HumanPassed     bool   `json:"humanPassed"`
+	JudgePassed     bool   `json:"judgePassed"`
+	Reason          string `json:"reason,omitempty"`
+	FailureCategory string `json:"failureCategory,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// Calibrate runs the judge over every example in the dataset, using up to
+// parallel concurrent evaluations, and reports agreement with the human labels.
+func Calibrate(ctx context.Context, judge LLMJudge, ds *CalibrationDataset, parallel int) *CalibrationReport {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	outcomes := make([]CalibrationOutcome, len(ds.Examples))
+	usages := make([]*tokens.Usage, len(ds.Examples))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallel)
+
+	for i := range ds.Examples {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			ex := &ds.Examples[i]
+			outcome := CalibrationOutcome{
+				ID:          ex.ID,
+				HumanPassed: ex.HumanPassed(),
+			}
+
+			cfg, err := ex.StepConfig()
+			if err != nil {
+				outcome.Error = err.Error()
+				outcomes[i] = outcome
+				return
+			}
+			outcome.Mode = cfg.EvaluationMode()
+
+			res, err := judge.EvaluateText(ctx, cfg, ex.Prompt, ex.Output)
+			if err != nil {
+				outcome.Error = err.Error()
+				outcomes[i] = outcome
+				return
+			}
+
+			outcome.JudgePassed = res.Passed
+			outcome.Reason = res.Reason
+			outcome.FailureCategory = res.FailureCategory
+			outcomes[i] = outcome
+			usages[i] = res.Usage
+		}()
+	}
+
+	wg.Wait()
+
+	report := &CalibrationReport{
+		Judge: judge.ModelName(),
+		Total: len(outcomes),
+	}
+
+	usage := &tokens.Usage{}
+	for i, outcome := range outcomes {
+		if usages[i] != nil {
+			usage.Add(usages[i])
+		}
+
+		switch {
+		case outcome.Error != "":
+			report.Errors++
+		case outcome.HumanPassed && outcome.JudgePassed:
+			report.TruePositives++
+		case !outcome.HumanPassed && outcome.JudgePassed:
+			report.FalsePositives++
+		case !outcome.HumanPassed && !outcome.JudgePassed:
+			report.TrueNegatives++
+		default:
+			report.FalseNegatives++
+		}
+
+		if outcome.Error != "" || outcome.HumanPassed != outcome.JudgePassed {
+			report.Disagreements = append(report.Disagreements, outcome)
+		}
+	}
+
+	if usage.InputTokens > 0 || usage.OutputTokens > 0 {
+		report.Usage = usage
+	}
+
+	if predictedPositive := report.TruePositives + report.FalsePositives; predictedPositive > 0 {
+		report.Precision = float64(report.TruePositives) / float64(predictedPositive)
+	}
+	if actualPositive := report.TruePositives + report.FalseNegatives; actualPositive > 0 {
+		report.Recall = float64(report.TruePositives) / float64(actualPositive)
+	}
+	if scored := report.Total - report.Errors; scored > 0 {
+		report.Accuracy = float64(report.TruePositives+report.TrueNegatives) / float64(scored)
+	}
+
+	return report
+}
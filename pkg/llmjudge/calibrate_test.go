@@ -0,0 +1,146 @@
+package llmjudge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mcpchecker/mcpchecker/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// substringJudge passes when the output literally contains the reference answer
+type substringJudge struct{}
+
+func (j *substringJudge) EvaluateText(ctx context.Context, cfg *LLMJudgeStepConfig, prompt, output string) (*LLMJudgeResult, error) {
+	if output == "boom" {
+		return nil, fmt.Errorf("judge unavailable")
+	}
+	passed := strings.Contains(output, cfg.ReferenceAnswer())
+	category := "n/a"
+	if !passed {
+		category = "missing_information"
+	}
+	return &LLMJudgeResult{
+		Passed:          passed,
+		Reason:          "substring check",
+		FailureCategory: category,
+		Usage:           &tokens.Usage{InputTokens: 10, OutputTokens: 1},
+	}, nil
+}
+
+func (j *substringJudge) ModelName() string { return "substring" }
+func (j *substringJudge) Close() error      { return nil }
+
+func TestReadCalibrationDataset(t *testing.T) {
+	tt := map[string]struct {
+		data      string
+		expectErr string
+	}{
+		"valid": {
+			data: `kind: JudgeCalibrationDataset
+examples:
+  - prompt: p
+    output: o
+    reference: r
+    verdict: pass
+  - id: second
+    output: o
+    reference: r
+    mode: EXACT
+    verdict: FAIL
+`,
+		},
+		"wrong kind": {
+			data:      "kind: Task\nexamples: []\n",
+			expectErr: "wrong kind",
+		},
+		"no examples": {
+			data:      "kind: JudgeCalibrationDataset\n",
+			expectErr: "at least one example",
+		},
+		"bad verdict": {
+			data: `kind: JudgeCalibrationDataset
+examples:
+  - output: o
+    reference: r
+    verdict: maybe
+`,
+			expectErr: "verdict must be one of",
+		},
+		"bad mode": {
+			data: `kind: JudgeCalibrationDataset
+examples:
+  - output: o
+    reference: r
+    mode: fuzzy
+    verdict: pass
+`,
+			expectErr: "unknown mode",
+		},
+		"duplicate id": {
+			data: `kind: JudgeCalibrationDataset
+examples:
+  - id: a
+    output: o
+    reference: r
+    verdict: pass
+  - id: a
+    output: o
+    reference: r
+    verdict: pass
+`,
+			expectErr: "duplicate id",
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			ds, err := ReadCalibrationDataset([]byte(tc.data))
+			if tc.expectErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "0", ds.Examples[0].ID)
+			assert.Equal(t, "second", ds.Examples[1].ID)
+		})
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	ds := &CalibrationDataset{
+		Examples: []CalibrationExample{
+			{ID: "tp", Output: "image mysql:8", Reference: "mysql:8", Verdict: VerdictPass},
+			{ID: "fn", Output: "image is MySQL 8", Reference: "mysql:8", Verdict: VerdictPass},
+			{ID: "fp", Output: "mysql:8 or maybe postgres", Reference: "mysql:8", Mode: "exact", Verdict: VerdictFail},
+			{ID: "tn", Output: "postgres", Reference: "mysql:8", Verdict: VerdictFail},
+			{ID: "err", Output: "boom", Reference: "mysql:8", Verdict: VerdictFail},
+		},
+	}
+
+	report := Calibrate(context.Background(), &substringJudge{}, ds, 3)
+
+	assert.Equal(t, "substring", report.Judge)
+	assert.Equal(t, 5, report.Total)
+	assert.Equal(t, 1, report.TruePositives)
+	assert.Equal(t, 1, report.FalseNegatives)
+	assert.Equal(t, 1, report.FalsePositives)
+	assert.Equal(t, 1, report.TrueNegatives)
+	assert.Equal(t, 1, report.Errors)
+	assert.InDelta(t, 0.5, report.Precision, 0.0001)
+	assert.InDelta(t, 0.5, report.Recall, 0.0001)
+	assert.InDelta(t, 0.5, report.Accuracy, 0.0001)
+	assert.Equal(t, &tokens.Usage{InputTokens: 40, OutputTokens: 4}, report.Usage)
+
+	require.Len(t, report.Disagreements, 3)
+	assert.Equal(t, "fn", report.Disagreements[0].ID)
+	assert.Equal(t, "missing_information", report.Disagreements[0].FailureCategory)
+	assert.Equal(t, "fp", report.Disagreements[1].ID)
+	assert.Equal(t, EvaluationModeExact, report.Disagreements[1].Mode)
+	assert.Equal(t, "err", report.Disagreements[2].ID)
+	assert.Equal(t, "judge unavailable", report.Disagreements[2].Error)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/mcpchecker/mcpchecker/pkg/agent"
+	"github.com/mcpchecker/mcpchecker/pkg/llmagent"
 )
 
 const (
@@ -14,6 +15,8 @@ const (
 type LLMJudgeEvalConfig struct {
 	Env      *LLMJudgeEnvConfig `json:"env,omitempty"`
 	AgentRef *agent.AgentRef    `json:"ref,omitempty"`
+	// RateLimit caps the judgements per minute and the tokens they use, shared by all tasks
+	RateLimit *llmagent.RateLimit `json:"rateLimit,omitempty"`
 }
 
 type LLMJudgeEnvConfig struct {
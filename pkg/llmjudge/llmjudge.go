@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/mcpchecker/mcpchecker/pkg/agent"
+	"github.com/mcpchecker/mcpchecker/pkg/llmagent"
 	"github.com/mcpchecker/mcpchecker/pkg/tokens"
 )
 
@@ -25,10 +26,11 @@ type LLMJudgeResult struct {
 }
 
 type llmJudge struct {
-	runner agent.Runner
-	name   string
-	server *judgeServer
-	cancel context.CancelFunc
+	runner  agent.Runner
+	name    string
+	server  *judgeServer
+	cancel  context.CancelFunc
+	limiter *llmagent.RateLimiter
 }
 
 type noopLLMJudge struct{}
@@ -93,12 +95,17 @@ func NewLLMJudge(cfg *LLMJudgeEvalConfig) (LLMJudge, error) {
 		return nil, fmt.Errorf("failed to start judge server: %w", err)
 	}
 
-	return &llmJudge{
+	judge := &llmJudge{
 		runner: runner,
 		name:   runner.AgentName(),
 		server: server,
 		cancel: cancel,
-	}, nil
+	}
+	if cfg.RateLimit != nil {
+		judge.limiter = llmagent.NewRateLimiter(*cfg.RateLimit)
+	}
+
+	return judge, nil
 }
 
 func (j *llmJudge) EvaluateText(ctx context.Context, judgeConfig *LLMJudgeStepConfig, prompt, output string) (*LLMJudgeResult, error) {
@@ -127,16 +134,28 @@ func (j *llmJudge) EvaluateText(ctx context.Context, judgeConfig *LLMJudgeStepCo
 	manager := &judgeServerManager{server: j.server, requestID: requestID}
 	judgeRunner := j.runner.WithMcpServerInfo(manager)
 
+	waited, err := j.limiter.Wait(ctx)
+	if waited > 0 {
+		if onWait, ok := llmagent.RateLimitWaitFromContext(ctx); ok {
+			onWait("judge", waited)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for judge rate limit: %w", err)
+	}
+
 	result, err := judgeRunner.RunTask(ctx, combinedPrompt)
 	if err != nil {
 		return nil, fmt.Errorf("failed to run judge agent: %w", err)
 	}
 
 	estimate := result.GetTokenEstimate()
+	usage := estimate.ToUsage()
+	j.limiter.Consume(usage.InputTokens + usage.OutputTokens)
 
 	select {
 	case res := <-resultCh:
-		res.Usage = estimate.ToUsage()
+		res.Usage = usage
 		return res, nil
 	default:
 		return nil, fmt.Errorf("judge agent completed without calling submit_judgement tool")
@@ -88,6 +88,8 @@ func (m *judgeServerManager) GetCallHistoryForServer(serverName string) (mcpprox
 	return mcpproxy.CallHistory{}, false
 }
 
+func (m *judgeServerManager) ResetCallHistory() {}
+
 var _ mcpproxy.Server = &judgeServerProxy{}
 
 // noop for llm judge
@@ -124,6 +126,9 @@ func (s *judgeServerProxy) GetCallHistory() mcpproxy.CallHistory {
 	return mcpproxy.CallHistory{}
 }
 
+// ResetCallHistory is a noop, as no calls are recorded
+func (s *judgeServerProxy) ResetCallHistory() {}
+
 // WaitReady blocks until the server has initialized and is ready to serve
 func (s *judgeServerProxy) WaitReady(ctx context.Context) error {
 	return nil
@@ -0,0 +1,253 @@
+package mcpclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Auth types
+const (
+	// AuthTypeClientCredentials gets access tokens with the OAuth client credentials grant
+	AuthTypeClientCredentials = "clientCredentials"
+	// AuthTypeRefreshToken gets access tokens by exchanging a refresh token
+	AuthTypeRefreshToken = "refreshToken"
+	// AuthTypeTokenCommand runs a local command that prints an access token
+	AuthTypeTokenCommand = "tokenCommand"
+)
+
+const redacted = "[REDACTED]"
+
+// AuthConfig configures how the client authenticates to an http or sse server.
+// Secrets (clientId, clientSecret and refreshToken) may reference environment
+// variables like ${VAR}, which are expanded when a token is requested.
+type AuthConfig struct {
+	// Type is the way access tokens are obtained: "clientCredentials", "refreshToken" or "tokenCommand"
+	Type string `json:"type"`
+
+	// TokenURL is the token endpoint of the authorization server
+	// Used for clientCredentials and refreshToken
+	TokenURL string `json:"tokenUrl,omitempty"`
+
+	// ClientID and ClientSecret identify the client to the authorization server
+	ClientID     string `json:"clientId,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+
+	// RefreshToken is exchanged for access tokens
+	// Used for refreshToken. If the server rotates refresh tokens, the new one is used from then on
+	RefreshToken string `json:"refreshToken,omitempty"`
+
+	// Scopes are the scopes requested for the access token
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Resource is sent as the resource indicator (RFC 8707) with client credentials requests,
+	// defaults to the server URL as required by the MCP authorization spec
+	Resource string `json:"resource,omitempty"`
+
+	// Command and Args run a local command that prints an access token to stdout
+	// Used for tokenCommand. The command is run again whenever the server rejects the token
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+}
+
+func (a *AuthConfig) validate() error {
+	switch a.Type {
+	case AuthTypeClientCredentials:
+		if a.TokenURL == "" || a.ClientID == "" {
+			return fmt.Errorf("tokenUrl and clientId are required for %s auth", a.Type)
+		}
+	case AuthTypeRefreshToken:
+		if a.TokenURL == "" || a.RefreshToken == "" {
+			return fmt.Errorf("tokenUrl and refreshToken are required for %s auth", a.Type)
+		}
+	case AuthTypeTokenCommand:
+		if a.Command == "" {
+			return fmt.Errorf("command is required for %s auth", a.Type)
+		}
+	default:
+		return fmt.Errorf("unknown auth type %q: must be one of clientCredentials, refreshToken or tokenCommand", a.Type)
+	}
+
+	return nil
+}
+
+// Redact replaces the secrets of the auth config in s, so that errors can be
+// stored in results without leaking credentials
+func (a *AuthConfig) Redact(s string) string {
+	if a == nil {
+		return s
+	}
+
+	for _, secret := range []string{a.ClientSecret, a.RefreshToken, os.ExpandEnv(a.ClientSecret), os.ExpandEnv(a.RefreshToken)} {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+
+	return s
+}
+
+// tokenSource fetches access tokens for an AuthConfig, caching them until they
+// expire or the server rejects them
+type tokenSource struct {
+	auth     *AuthConfig
+	resource string
+
+	mu           sync.Mutex
+	token        *oauth2.Token
+	refreshToken string
+}
+
+func newTokenSource(auth *AuthConfig, serverURL string) *tokenSource {
+	resource := auth.Resource
+	if resource == "" {
+		resource = serverURL
+	}
+
+	return &tokenSource{
+		auth:         auth,
+		resource:     resource,
+		refreshToken: os.ExpandEnv(auth.RefreshToken),
+	}
+}
+
+// Token returns a valid access token, fetching a new one if needed
+func (ts *tokenSource) Token(ctx context.Context) (*oauth2.Token, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token.Valid() {
+		return ts.token, nil
+	}
+
+	token, err := ts.fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s access token: %s", ts.auth.Type, ts.auth.Redact(err.Error()))
+	}
+	if token.RefreshToken != "" {
+		ts.refreshToken = token.RefreshToken
+	}
+	ts.token = token
+
+	return token, nil
+}
+
+// invalidate drops the cached token if it is still the rejected one
+func (ts *tokenSource) invalidate(rejected *oauth2.Token) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.token == rejected {
+		ts.token = nil
+	}
+}
+
+func (ts *tokenSource) fetch(ctx context.Context) (*oauth2.Token, error) {
+	switch ts.auth.Type {
+	case AuthTypeClientCredentials:
+		cfg := &clientcredentials.Config{
+			ClientID:       os.ExpandEnv(ts.auth.ClientID),
+			ClientSecret:   os.ExpandEnv(ts.auth.ClientSecret),
+			TokenURL:       ts.auth.TokenURL,
+			Scopes:         ts.auth.Scopes,
+			EndpointParams: url.Values{"resource": {ts.resource}},
+		}
+		return cfg.Token(ctx)
+	case AuthTypeRefreshToken:
+		cfg := &oauth2.Config{
+			ClientID:     os.ExpandEnv(ts.auth.ClientID),
+			ClientSecret: os.ExpandEnv(ts.auth.ClientSecret),
+			Endpoint:     oauth2.Endpoint{TokenURL: ts.auth.TokenURL},
+			Scopes:       ts.auth.Scopes,
+		}
+		return cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: ts.refreshToken}).Token()
+	case AuthTypeTokenCommand:
+		cmd := exec.CommandContext(ctx, ts.auth.Command, ts.auth.Args...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("token command failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+
+		token := strings.TrimSpace(string(out))
+		if token == "" {
+			return nil, fmt.Errorf("token command printed no token")
+		}
+		return &oauth2.Token{AccessToken: token, TokenType: "Bearer"}, nil
+	default:
+		return nil, fmt.Errorf("unknown auth type %q", ts.auth.Type)
+	}
+}
+
+// AuthRoundTripper adds an access token to every request. When the server answers
+// 401 Unauthorized, a new token is fetched and the request is retried once.
+type AuthRoundTripper struct {
+	tokens    *tokenSource
+	Transport http.RoundTripper
+}
+
+// NewAuthRoundTripper creates a new AuthRoundTripper for a server at serverURL.
+// If transport is nil, http.DefaultTransport is used.
+func NewAuthRoundTripper(auth *AuthConfig, serverURL string, transport http.RoundTripper) *AuthRoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &AuthRoundTripper{
+		tokens:    newTokenSource(auth, serverURL),
+		Transport: transport,
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (a *AuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := a.tokens.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.Transport.RoundTrip(withToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// The request can only be retried if its body can be sent again
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	a.tokens.invalidate(token)
+	token, err = a.tokens.Token(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	retry := withToken(req, token)
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return a.Transport.RoundTrip(retry)
+}
+
+func withToken(req *http.Request, token *oauth2.Token) *http.Request {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", token.Type()+" "+token.AccessToken)
+	return req
+}
@@ -0,0 +1,232 @@
+package mcpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAuthServer is a minimal authorization server and protected MCP server.
+// Issued tokens are accepted by the MCP server until revoked.
+type stubAuthServer struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	issued   int
+	valid    map[string]bool
+	requests []url.Values // token requests
+	refresh  string       // the refresh token currently accepted
+
+	tokenURL string
+	mcpURL   string
+}
+
+func newStubAuthServer(t *testing.T) *stubAuthServer {
+	t.Helper()
+
+	s := &stubAuthServer{t: t, valid: map[string]bool{}, refresh: "refresh-0"}
+
+	auth := httptest.NewServer(http.HandlerFunc(s.token))
+	t.Cleanup(auth.Close)
+	s.tokenURL = auth.URL + "/token"
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "protected", Version: "0.0.1"}, nil)
+	server.AddTool(&mcp.Tool{Name: "echo", InputSchema: map[string]any{"type": "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "echo"}}}, nil
+	})
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+
+	protected := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		s.mu.Lock()
+		ok := s.valid[token]
+		s.mu.Unlock()
+
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(protected.Close)
+	s.mcpURL = protected.URL + "/mcp"
+
+	return s
+}
+
+func (s *stubAuthServer) token(w http.ResponseWriter, r *http.Request) {
+	require.NoError(s.t, r.ParseForm())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	form := r.PostForm
+	if id, secret, ok := r.BasicAuth(); ok {
+		form.Set("client_id", id)
+		form.Set("client_secret", secret)
+	}
+	s.requests = append(s.requests, form)
+
+	resp := map[string]any{"token_type": "Bearer", "expires_in": 3600}
+	switch form.Get("grant_type") {
+	case "client_credentials":
+		if form.Get("client_secret") != "s3cret" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+	case "refresh_token":
+		if form.Get("refresh_token") != s.refresh {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		// rotate the refresh token on every use
+		s.refresh = fmt.Sprintf("refresh-%d", s.issued+1)
+		resp["refresh_token"] = s.refresh
+	}
+
+	s.issued++
+	access := fmt.Sprintf("access-%d", s.issued)
+	s.valid[access] = true
+	resp["access_token"] = access
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// revokeAll makes the MCP server reject all tokens issued so far
+func (s *stubAuthServer) revokeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.valid = map[string]bool{}
+}
+
+func (s *stubAuthServer) tokenRequests() []url.Values {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]url.Values(nil), s.requests...)
+}
+
+func connectWithAuth(t *testing.T, s *stubAuthServer, auth *AuthConfig) *Client {
+	t.Helper()
+
+	c, err := Connect(context.Background(), &ServerConfig{URL: s.mcpURL, Auth: auth})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c
+}
+
+func callEcho(t *testing.T, c *Client) {
+	t.Helper()
+
+	res, err := c.CallTool(context.Background(), &mcp.CallToolParams{Name: "echo"})
+	require.NoError(t, err)
+	assert.False(t, res.IsError)
+}
+
+func TestAuthClientCredentials(t *testing.T) {
+	s := newStubAuthServer(t)
+	t.Setenv("TEST_CLIENT_SECRET", "s3cret")
+
+	c := connectWithAuth(t, s, &AuthConfig{
+		Type:         AuthTypeClientCredentials,
+		TokenURL:     s.tokenURL,
+		ClientID:     "mcpchecker",
+		ClientSecret: "${TEST_CLIENT_SECRET}",
+		Scopes:       []string{"tools:call"},
+	})
+	callEcho(t, c)
+
+	requests := s.tokenRequests()
+	require.Len(t, requests, 1, "the token should be reused until it is rejected")
+	assert.Equal(t, "mcpchecker", requests[0].Get("client_id"))
+	assert.Equal(t, "s3cret", requests[0].Get("client_secret"))
+	assert.Equal(t, "tools:call", requests[0].Get("scope"))
+	assert.Equal(t, s.mcpURL, requests[0].Get("resource"))
+
+	s.revokeAll()
+	callEcho(t, c)
+	assert.Len(t, s.tokenRequests(), 2, "a rejected token should be refreshed")
+}
+
+func TestAuthRefreshToken(t *testing.T) {
+	s := newStubAuthServer(t)
+
+	c := connectWithAuth(t, s, &AuthConfig{
+		Type:         AuthTypeRefreshToken,
+		TokenURL:     s.tokenURL,
+		ClientID:     "mcpchecker",
+		RefreshToken: "refresh-0",
+	})
+	callEcho(t, c)
+
+	s.revokeAll()
+	callEcho(t, c)
+
+	requests := s.tokenRequests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "refresh-0", requests[0].Get("refresh_token"))
+	assert.Equal(t, "refresh-1", requests[1].Get("refresh_token"), "the rotated refresh token should be used")
+}
+
+func TestAuthTokenCommand(t *testing.T) {
+	s := newStubAuthServer(t)
+	s.valid["from-command"] = true
+
+	c := connectWithAuth(t, s, &AuthConfig{
+		Type:    AuthTypeTokenCommand,
+		Command: "echo",
+		Args:    []string{"from-command"},
+	})
+	callEcho(t, c)
+	assert.Empty(t, s.tokenRequests())
+}
+
+func TestAuthErrorsAreRedacted(t *testing.T) {
+	s := newStubAuthServer(t)
+
+	_, err := Connect(context.Background(), &ServerConfig{URL: s.mcpURL, Auth: &AuthConfig{
+		Type:    AuthTypeTokenCommand,
+		Command: "sh",
+		Args:    []string{"-c", "echo 'bad secret hunter2' >&2; exit 1"},
+	}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "hunter2", "only configured secrets are redacted")
+
+	_, err = Connect(context.Background(), &ServerConfig{URL: s.mcpURL, Auth: &AuthConfig{
+		Type:         AuthTypeRefreshToken,
+		TokenURL:     s.tokenURL,
+		RefreshToken: "stale-refresh-token",
+	}})
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "stale-refresh-token")
+}
+
+func TestAuthConfigRedact(t *testing.T) {
+	t.Setenv("TEST_REFRESH_TOKEN", "rt-from-env")
+
+	a := &AuthConfig{
+		ClientSecret: "s3cret",
+		RefreshToken: "${TEST_REFRESH_TOKEN}",
+	}
+
+	assert.Equal(t, "secret [REDACTED] and token [REDACTED]", a.Redact("secret s3cret and token rt-from-env"))
+	assert.Equal(t, "unchanged", (*AuthConfig)(nil).Redact("unchanged"))
+}
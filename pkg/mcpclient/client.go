@@ -7,29 +7,83 @@ import (
 	"os"
 	"os/exec"
 	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 type Client struct {
 	*mcp.ClientSession
-	cfg *ServerConfig
+	cfg    *ServerConfig
+	client *mcp.Client
+
+	mu      sync.RWMutex
+	handler ServerRequestHandler
+
+	rootsMu sync.Mutex
+	roots   []string // URIs of the roots exposed to the server
+
+	outputSchemas *OutputSchemaValidator
+
+	// Connection tracking, done is closed when the session ends
+	done           chan struct{}
+	waitErr        error
+	disconnectedAt time.Time
+	closing        atomic.Bool
+	reported       atomic.Bool // whether the lost connection was reported as an event
 }
 
+// ServerRequestHandler services requests and list_changed notifications the MCP
+// server sends to the client (sampling, elicitation and roots). Since the handler usually forwards these
+// to the agent, it is set after Connect once the agent-facing proxy exists.
+type ServerRequestHandler interface {
+	CreateMessage(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error)
+	Elicit(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error)
+	ListRoots(ctx context.Context, req *mcp.ListRootsRequest) (*mcp.ListRootsResult, error)
+	// ListChanged is called when the server's tools, prompts or resources list changed
+	ListChanged(ctx context.Context, list string)
+	// Progress and Log are called for progress and logging notifications from the server
+	Progress(ctx context.Context, params *mcp.ProgressNotificationParams)
+	Log(ctx context.Context, params *mcp.LoggingMessageParams)
+	// ResourceUpdated is called when a resource the client subscribed to changed
+	ResourceUpdated(ctx context.Context, params *mcp.ResourceUpdatedNotificationParams)
+}
+
+const healthCheckTimeout = 5 * time.Second
+
+const (
+	ListTools     = "tools"
+	ListPrompts   = "prompts"
+	ListResources = "resources"
+)
+
 func Connect(ctx context.Context, cfg *ServerConfig) (*Client, error) {
 	var transport mcp.Transport
-	if cfg.IsHttp() {
+	if cfg.IsHttp() || cfg.IsSse() {
 		hdrs := make(http.Header, len(cfg.Headers))
 		for k, v := range cfg.Headers {
 			hdrs.Set(k, v)
 		}
+		var rt http.RoundTripper
+		if cfg.Auth != nil {
+			rt = NewAuthRoundTripper(cfg.Auth, cfg.URL, nil)
+		}
 		client := &http.Client{
-			Transport: NewHeaderRoundTripper(hdrs, nil),
+			Transport: NewHeaderRoundTripper(hdrs, rt),
 		}
 
-		transport = &mcp.StreamableClientTransport{
-			Endpoint:   cfg.URL,
-			HTTPClient: client,
+		if cfg.IsSse() {
+			transport = &mcp.SSEClientTransport{
+				Endpoint:   cfg.URL,
+				HTTPClient: client,
+			}
+		} else {
+			transport = &mcp.StreamableClientTransport{
+				Endpoint:   cfg.URL,
+				HTTPClient: client,
+			}
 		}
 	} else {
 		cmd := exec.Command(cfg.Command, cfg.Args...)
@@ -37,21 +91,197 @@ func Connect(ctx context.Context, cfg *ServerConfig) (*Client, error) {
 		transport = &mcp.CommandTransport{Command: cmd}
 	}
 
-	// TODO: revisit the client options, we probably want to leverage many
-	client := mcp.NewClient(&mcp.Implementation{
+	c := &Client{cfg: cfg, outputSchemas: NewOutputSchemaValidator()}
+
+	// Sampling and elicitation are always advertised so that servers relying on
+	// them can be evaluated, requests are forwarded through the ServerRequestHandler
+	c.client = mcp.NewClient(&mcp.Implementation{
 		Name:    "mcpchecker-client",
 		Version: "0.0.0",
-	}, nil)
+	}, &mcp.ClientOptions{
+		CreateMessageHandler: c.createMessage,
+		ElicitationHandler:   c.elicit,
+		ToolListChangedHandler: func(ctx context.Context, _ *mcp.ToolListChangedRequest) {
+			c.listChanged(ctx, ListTools)
+		},
+		PromptListChangedHandler: func(ctx context.Context, _ *mcp.PromptListChangedRequest) {
+			c.listChanged(ctx, ListPrompts)
+		},
+		ResourceListChangedHandler: func(ctx context.Context, _ *mcp.ResourceListChangedRequest) {
+			c.listChanged(ctx, ListResources)
+		},
+		ProgressNotificationHandler: func(ctx context.Context, req *mcp.ProgressNotificationClientRequest) {
+			if h := c.getHandler(); h != nil {
+				h.Progress(ctx, req.Params)
+			}
+		},
+		LoggingMessageHandler: func(ctx context.Context, req *mcp.LoggingMessageRequest) {
+			if h := c.getHandler(); h != nil {
+				h.Log(ctx, req.Params)
+			}
+		},
+		ResourceUpdatedHandler: func(ctx context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			if h := c.getHandler(); h != nil {
+				h.ResourceUpdated(ctx, req.Params)
+			}
+		},
+	})
+	c.client.AddReceivingMiddleware(c.rootsMiddleware)
 
-	cs, err := client.Connect(ctx, transport, nil)
+	cs, err := c.client.Connect(ctx, transport, nil)
 	if err != nil {
 		return nil, err
 	}
+	c.ClientSession = cs
+
+	c.done = make(chan struct{})
+	go func() {
+		c.waitErr = cs.Wait()
+		c.disconnectedAt = time.Now()
+		close(c.done)
+	}()
+
+	return c, nil
+}
+
+// Close closes the connection to the server. A closed client is not reported as disconnected.
+func (c *Client) Close() error {
+	c.closing.Store(true)
+	return c.ClientSession.Close()
+}
+
+// Health checks that the server is still connected and answers a ping
+func (c *Client) Health(ctx context.Context) error {
+	if c.disconnected() {
+		return fmt.Errorf("server disconnected: %w", c.disconnectErr())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	if err := c.Ping(ctx, nil); err != nil {
+		return fmt.Errorf("server did not answer ping: %w", err)
+	}
+
+	return nil
+}
+
+// disconnected reports whether the connection was lost without Close being called
+func (c *Client) disconnected() bool {
+	select {
+	case <-c.done:
+		return !c.closing.Load()
+	default:
+		return false
+	}
+}
+
+func (c *Client) disconnectErr() error {
+	if c.waitErr != nil {
+		return c.waitErr
+	}
+	return fmt.Errorf("connection closed by the server")
+}
+
+// disconnectEvent returns an event for a lost connection, at most once per client
+func (c *Client) disconnectEvent(server string) (ServerEvent, bool) {
+	if !c.disconnected() || !c.reported.CompareAndSwap(false, true) {
+		return ServerEvent{}, false
+	}
+
+	return ServerEvent{
+		Server:    server,
+		Type:      ServerEventDisconnected,
+		Error:     c.disconnectErr().Error(),
+		Timestamp: c.disconnectedAt,
+	}, true
+}
+
+// SetServerRequestHandler sets the handler for server-initiated requests.
+// Passing nil makes the client reject sampling and elicitation requests.
+func (c *Client) SetServerRequestHandler(h ServerRequestHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handler = h
+}
+
+// SetRoots replaces the roots of the client, notifying the server if the list changed
+func (c *Client) SetRoots(roots []*mcp.Root) {
+	c.rootsMu.Lock()
+	defer c.rootsMu.Unlock()
+
+	uris := make([]string, 0, len(roots))
+	for _, r := range roots {
+		uris = append(uris, r.URI)
+	}
+
+	var stale []string
+	for _, uri := range c.roots {
+		if !slices.Contains(uris, uri) {
+			stale = append(stale, uri)
+		}
+	}
+
+	if len(stale) > 0 {
+		c.client.RemoveRoots(stale...)
+	}
+	if len(roots) > 0 {
+		c.client.AddRoots(roots...)
+	}
+	c.roots = uris
+}
+
+// reset clears the handler and roots of a shared client, so that nothing of the run that
+// used it carries over to the next
+func (c *Client) reset() {
+	c.SetServerRequestHandler(nil)
+	c.SetRoots(nil)
+}
+
+func (c *Client) getHandler() ServerRequestHandler {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.handler
+}
+
+func (c *Client) createMessage(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	h := c.getHandler()
+	if h == nil {
+		return nil, fmt.Errorf("sampling is not available: no agent is connected")
+	}
 
-	return &Client{
-		ClientSession: cs,
-		cfg:           cfg,
-	}, nil
+	return h.CreateMessage(ctx, req)
+}
+
+func (c *Client) elicit(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	h := c.getHandler()
+	if h == nil {
+		return nil, fmt.Errorf("elicitation is not available: no agent is connected")
+	}
+
+	return h.Elicit(ctx, req)
+}
+
+func (c *Client) listChanged(ctx context.Context, list string) {
+	if h := c.getHandler(); h != nil {
+		h.ListChanged(ctx, list)
+	}
+}
+
+// rootsMiddleware hands roots/list requests to the handler, the sdk client only
+// answers them itself (with the roots set through SetRoots) when no handler is set
+func (c *Client) rootsMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		h := c.getHandler()
+		listReq, ok := req.(*mcp.ListRootsRequest)
+		if method != "roots/list" || h == nil || !ok {
+			return next(ctx, method, req)
+		}
+
+		return h.ListRoots(ctx, listReq)
+	}
 }
 
 func (c *Client) GetAllowedTools(ctx context.Context) []*mcp.Tool {
@@ -71,6 +301,15 @@ func (c *Client) GetAllowedTools(ctx context.Context) []*mcp.Tool {
 	return allowed
 }
 
+// OutputSchemas returns the validator for the tool results of the server, kept with the
+// client so that resolved schemas are reused across calls
+func (c *Client) OutputSchemas() *OutputSchemaValidator {
+	if c.outputSchemas == nil {
+		return NewOutputSchemaValidator()
+	}
+	return c.outputSchemas
+}
+
 func (c *Client) GetConfig() *ServerConfig {
 	return c.cfg
 }
@@ -13,6 +13,17 @@ import (
 const (
 	TransportTypeHttp  = "http"
 	TransportTypeStdio = "stdio"
+	TransportTypeSse   = "sse"
+)
+
+// Server lifecycles, deciding how long a connection to a server is kept
+const (
+	// LifecyclePerEval connects once and shares the connection between all tasks of the eval
+	LifecyclePerEval = "perEval"
+	// LifecyclePerTask connects once per task and shares the connection between its runs
+	LifecyclePerTask = "perTask"
+	// LifecyclePerRun connects for every run of every task
+	LifecyclePerRun = "perRun"
 )
 
 // MCPConfig represents the top-level MCP configuration file structure
@@ -22,9 +33,9 @@ type MCPConfig struct {
 }
 
 // ServerConfig represents the configuration for a single MCP server.
-// Supports both stdio (command-based) and HTTP-based servers.
+// Supports stdio (command-based), HTTP-based and SSE-based servers.
 type ServerConfig struct {
-	// Type specifies the server type: "stdio" or "http"
+	// Type specifies the server type: "stdio", "http" or "sse"
 	// If not specified, will be inferred from URL (http) or Command (stdio)
 	Type string `json:"type,omitempty"`
 
@@ -41,14 +52,18 @@ type ServerConfig struct {
 	Env map[string]string `json:"env,omitempty"`
 
 	// URL is the HTTP endpoint for the MCP server
-	// Used for http servers. May contain environment variable references
+	// Used for http and sse servers. May contain environment variable references
 	// like ${VAR} or ${VAR:-default}
 	URL string `json:"url,omitempty"`
 
 	// Headers are HTTP headers to send with requests
-	// Used for http servers. Values may contain environment variable references
+	// Used for http and sse servers. Values may contain environment variable references
 	Headers map[string]string `json:"headers,omitempty"`
 
+	// Auth configures OAuth access tokens sent with requests
+	// Used for http and sse servers
+	Auth *AuthConfig `json:"auth,omitempty"`
+
 	// Disabled indicates whether this server should be skipped
 	Disabled bool `json:"disabled,omitempty"`
 
@@ -57,6 +72,14 @@ type ServerConfig struct {
 
 	// EnableAllTools sets all tools to be allowed
 	EnableAllTools bool `json:"enableAllTools"`
+
+	// ProxyTransport is the transport agents use to reach the recording proxy
+	// in front of this server: "http" (default), "sse" or "stdio"
+	ProxyTransport string `json:"proxyTransport,omitempty"`
+
+	// Lifecycle decides how long a connection to the server (and a stdio server process)
+	// is kept: "perEval", "perTask" or "perRun" (default)
+	Lifecycle string `json:"lifecycle,omitempty"`
 }
 
 // ParseConfigFile reads and parses an MCP config file from the given path.
@@ -94,9 +117,9 @@ func validateConfig(config *MCPConfig) error {
 	}
 
 	for name, server := range config.MCPServers {
-		if server.IsHttp() {
+		if server.IsHttp() || server.IsSse() {
 			if server.URL == "" {
-				return fmt.Errorf("server %q: url is required for http servers", name)
+				return fmt.Errorf("server %q: url is required for %s servers", name, server.transportType())
 			}
 		} else if server.IsStdio() {
 			if server.Command == "" {
@@ -105,6 +128,27 @@ func validateConfig(config *MCPConfig) error {
 		} else {
 			return fmt.Errorf("server %q: must specify either command or url", name)
 		}
+
+		if server.Auth != nil {
+			if !server.IsHttp() && !server.IsSse() {
+				return fmt.Errorf("server %q: auth is only supported for http and sse servers", name)
+			}
+			if err := server.Auth.validate(); err != nil {
+				return fmt.Errorf("server %q: invalid auth: %w", name, err)
+			}
+		}
+
+		switch server.ProxyTransport {
+		case "", TransportTypeHttp, TransportTypeSse, TransportTypeStdio:
+		default:
+			return fmt.Errorf("server %q: unknown proxyTransport %q: must be one of http, sse or stdio", name, server.ProxyTransport)
+		}
+
+		switch server.Lifecycle {
+		case "", LifecyclePerEval, LifecyclePerTask, LifecyclePerRun:
+		default:
+			return fmt.Errorf("server %q: unknown lifecycle %q: must be one of perEval, perTask or perRun", name, server.Lifecycle)
+		}
 	}
 
 	return nil
@@ -148,7 +192,7 @@ func (s *ServerConfig) IsStdio() bool {
 	if s.Type == "stdio" {
 		return true
 	}
-	if s.Type == "http" {
+	if s.Type == "http" || s.Type == "sse" {
 		return false
 	}
 	// Type not specified - infer from fields
@@ -160,13 +204,38 @@ func (s *ServerConfig) IsHttp() bool {
 	if s.Type == "http" {
 		return true
 	}
-	if s.Type == "stdio" {
+	if s.Type == "stdio" || s.Type == "sse" {
 		return false
 	}
 	// Type not specified - infer from fields
 	return s.URL != ""
 }
 
+// IsSse returns true if this is a server using the legacy HTTP+SSE transport.
+// SSE servers must set the type explicitly, a URL alone is treated as http.
+func (s *ServerConfig) IsSse() bool {
+	return s.Type == "sse"
+}
+
+// GetLifecycle returns the lifecycle of the server, defaulting to perRun
+func (s *ServerConfig) GetLifecycle() string {
+	if s.Lifecycle == "" {
+		return LifecyclePerRun
+	}
+	return s.Lifecycle
+}
+
+func (s *ServerConfig) transportType() string {
+	switch {
+	case s.IsSse():
+		return TransportTypeSse
+	case s.IsHttp():
+		return TransportTypeHttp
+	default:
+		return TransportTypeStdio
+	}
+}
+
 // Environment variable names for MCP configuration
 const (
 	EnvMcpURL            = "MCP_URL"
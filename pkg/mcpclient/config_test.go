@@ -17,6 +17,7 @@ func TestParseConfigFile(t *testing.T) {
 	type serverTypes struct {
 		isStdio bool
 		isHttp  bool
+		isSse   bool
 	}
 
 	tt := map[string]struct {
@@ -73,6 +74,71 @@ func TestParseConfigFile(t *testing.T) {
 				"api-server": {isHttp: true},
 			},
 		},
+		"sse-server": {
+			file: "sse-server.json",
+			expected: &MCPConfig{
+				MCPServers: map[string]*ServerConfig{
+					"legacy": {
+						Type:           "sse",
+						URL:            "http://localhost:8080/sse",
+						ProxyTransport: "stdio",
+					},
+				},
+			},
+			expectedTypes: map[string]serverTypes{
+				"legacy": {isSse: true},
+			},
+		},
+		"invalid proxy transport": {
+			file:      "invalid-proxy-transport.json",
+			expectErr: true,
+		},
+		"lifecycle": {
+			file: "lifecycle.json",
+			expected: &MCPConfig{
+				MCPServers: map[string]*ServerConfig{
+					"kubernetes": {
+						Command:   "kubernetes-mcp-server",
+						Lifecycle: LifecyclePerEval,
+					},
+				},
+			},
+			expectedTypes: map[string]serverTypes{
+				"kubernetes": {isStdio: true},
+			},
+		},
+		"invalid lifecycle": {
+			file:      "invalid-lifecycle.json",
+			expectErr: true,
+		},
+		"auth": {
+			file: "auth.json",
+			expected: &MCPConfig{
+				MCPServers: map[string]*ServerConfig{
+					"hosted": {
+						URL: "https://mcp.example.com/mcp",
+						Auth: &AuthConfig{
+							Type:         AuthTypeClientCredentials,
+							TokenURL:     "https://auth.example.com/token",
+							ClientID:     "mcpchecker",
+							ClientSecret: "${HOSTED_CLIENT_SECRET}",
+							Scopes:       []string{"tools:read"},
+						},
+					},
+				},
+			},
+			expectedTypes: map[string]serverTypes{
+				"hosted": {isHttp: true},
+			},
+		},
+		"auth missing refresh token": {
+			file:      "invalid-auth.json",
+			expectErr: true,
+		},
+		"auth on stdio server": {
+			file:      "stdio-auth.json",
+			expectErr: true,
+		},
 	}
 
 	for tn, tc := range tt {
@@ -85,6 +151,11 @@ func TestParseConfigFile(t *testing.T) {
 
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expected, got)
+			for name, types := range tc.expectedTypes {
+				assert.Equal(t, types.isStdio, got.MCPServers[name].IsStdio(), "IsStdio for %s", name)
+				assert.Equal(t, types.isHttp, got.MCPServers[name].IsHttp(), "IsHttp for %s", name)
+				assert.Equal(t, types.isSse, got.MCPServers[name].IsSse(), "IsSse for %s", name)
+			}
 		})
 	}
 }
@@ -0,0 +1,211 @@
+package mcpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Connections hands out a Manager per task run, connecting each server according to its
+// lifecycle. perRun servers are connected for every run and closed with its manager, perTask
+// and perEval servers are shared and checked before every run. A shared server that crashed
+// or stopped answering is reconnected, and the run records the events.
+//
+// A shared server is used by one run at a time, runs in parallel wait for the run using it
+// to close its manager. Server-initiated requests, notifications and roots therefore always
+// belong to the run using the server.
+type Connections struct {
+	config *MCPConfig
+
+	mu      sync.Mutex
+	clients map[connectionKey]*Client
+	leases  map[connectionKey]chan struct{} // held by the run using the shared client
+}
+
+type connectionKey struct {
+	task   string // empty for perEval servers
+	server string
+}
+
+func NewConnections(config *MCPConfig) (*Connections, error) {
+	if config == nil {
+		return nil, fmt.Errorf("no config provided")
+	}
+
+	if len(config.GetEnabledServers()) == 0 {
+		return nil, fmt.Errorf("no enabled mcp servers found in config")
+	}
+
+	return &Connections{
+		config:  config,
+		clients: make(map[connectionKey]*Client),
+		leases:  make(map[connectionKey]chan struct{}),
+	}, nil
+}
+
+// NewManager returns a manager for a single run of task. Closing the manager only closes the perRun servers.
+func (c *Connections) NewManager(ctx context.Context, task string) (Manager, error) {
+	servers := c.config.GetEnabledServers()
+	m := newManager(len(servers))
+
+	var err error
+	// shared servers are leased in order of their names, so runs waiting for each other never deadlock
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		cfg := servers[name]
+		if cfg.GetLifecycle() == LifecyclePerRun {
+			cs, connErr := Connect(ctx, cfg)
+			if connErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to connect to mcp server %q: %w", name, connErr))
+				continue
+			}
+
+			m.sessions[name] = cs
+			m.owned[name] = true
+			continue
+		}
+
+		key := connectionKey{server: name}
+		if cfg.GetLifecycle() == LifecyclePerTask {
+			key.task = task
+		}
+
+		release, leaseErr := c.lease(ctx, key)
+		if leaseErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to wait for mcp server %q: %w", name, leaseErr))
+			continue
+		}
+
+		cs, events, connErr := c.shared(ctx, key, cfg)
+		m.events = append(m.events, events...)
+		if connErr != nil {
+			release()
+			err = errors.Join(err, fmt.Errorf("failed to connect to mcp server %q: %w", name, connErr))
+			continue
+		}
+
+		m.sessions[name] = cs
+		m.releases = append(m.releases, func() {
+			cs.reset()
+			release()
+		})
+	}
+
+	if err != nil {
+		return nil, m.closeOnError(ctx, err)
+	}
+
+	return m, nil
+}
+
+// lease waits until no other run uses the shared client for key and takes it, the
+// returned func hands it back
+func (c *Connections) lease(ctx context.Context, key connectionKey) (func(), error) {
+	c.mu.Lock()
+	lease, ok := c.leases[key]
+	if !ok {
+		lease = make(chan struct{}, 1)
+		c.leases[key] = lease
+	}
+	c.mu.Unlock()
+
+	select {
+	case lease <- struct{}{}:
+		return func() { <-lease }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// shared returns the shared client for key, connecting if there is none or the existing one is unhealthy
+func (c *Connections) shared(ctx context.Context, key connectionKey, cfg *ServerConfig) (*Client, []ServerEvent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var events []ServerEvent
+	cs, ok := c.clients[key]
+	if ok {
+		healthErr := cs.Health(ctx)
+		if healthErr == nil {
+			return cs, nil, nil
+		}
+
+		if ev, disconnected := cs.disconnectEvent(key.server); disconnected {
+			events = append(events, ev)
+		} else if !cs.disconnected() {
+			events = append(events, ServerEvent{
+				Server:    key.server,
+				Type:      ServerEventUnhealthy,
+				Error:     healthErr.Error(),
+				Timestamp: time.Now(),
+			})
+		}
+
+		_ = cs.Close()
+		delete(c.clients, key)
+	}
+
+	cs, err := Connect(ctx, cfg)
+	if err != nil {
+		return nil, events, err
+	}
+	c.clients[key] = cs
+
+	if ok {
+		events = append(events, ServerEvent{
+			Server:    key.server,
+			Type:      ServerEventReconnected,
+			Timestamp: time.Now(),
+		})
+	}
+
+	return cs, events, nil
+}
+
+// CloseTask closes the perTask servers of task, once all of its runs are done
+func (c *Connections) CloseTask(ctx context.Context, task string) error {
+	return c.close(ctx, func(key connectionKey) bool {
+		return key.task != "" && key.task == task
+	})
+}
+
+// Close closes all shared servers
+func (c *Connections) Close(ctx context.Context) error {
+	return c.close(ctx, func(connectionKey) bool {
+		return true
+	})
+}
+
+func (c *Connections) close(ctx context.Context, match func(connectionKey) bool) error {
+	c.mu.Lock()
+	var clients []*Client
+	for key, cs := range c.clients {
+		if match(key) {
+			clients = append(clients, cs)
+			delete(c.clients, key)
+		}
+	}
+	c.mu.Unlock()
+
+	results := make(chan error, len(clients))
+	for _, cs := range clients {
+		go func() {
+			results <- cs.Close()
+		}()
+	}
+
+	var err error
+	for range len(clients) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case closeErr := <-results:
+			err = errors.Join(err, closeErr)
+		}
+	}
+
+	return err
+}
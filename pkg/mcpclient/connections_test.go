@@ -0,0 +1,229 @@
+package mcpclient
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testServerEnv = "MCPCLIENT_TEST_SERVER"
+
+// TestMain runs the test binary as a stdio MCP server when testServerEnv is set,
+// so that tests can start a real server process and make it crash
+func TestMain(m *testing.M) {
+	if os.Getenv(testServerEnv) != "" {
+		runTestServer()
+		return
+	}
+
+	os.Exit(m.Run())
+}
+
+func runTestServer() {
+	s := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "0.0.1"}, nil)
+	s.AddTool(&mcp.Tool{Name: "echo", InputSchema: map[string]any{"type": "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "echo"}}}, nil
+	})
+	s.AddTool(&mcp.Tool{Name: "crash", InputSchema: map[string]any{"type": "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		os.Exit(1)
+		return nil, nil
+	})
+
+	_ = s.Run(context.Background(), &mcp.StdioTransport{})
+}
+
+func testServerConfig(t *testing.T, lifecycle string) *MCPConfig {
+	t.Helper()
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	return &MCPConfig{MCPServers: map[string]*ServerConfig{
+		"test": {
+			Command:   exe,
+			Env:       map[string]string{testServerEnv: "1"},
+			Lifecycle: lifecycle,
+		},
+	}}
+}
+
+func newTestConnections(t *testing.T, lifecycle string) *Connections {
+	t.Helper()
+
+	c, err := NewConnections(testServerConfig(t, lifecycle))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = c.Close(context.Background()) })
+
+	return c
+}
+
+func newTestManager(t *testing.T, c *Connections, task string) (Manager, *Client) {
+	t.Helper()
+
+	m, err := c.NewManager(context.Background(), task)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close(context.Background()) })
+
+	client, ok := m.Get("test")
+	require.True(t, ok)
+
+	return m, client
+}
+
+func TestConnectionsLifecycle(t *testing.T) {
+	tests := map[string]struct {
+		lifecycle      string
+		sameRun        bool // two runs of the same task share the client
+		sameTask       bool // runs of different tasks share the client
+		openAfterClose bool // the client stays connected after its manager is closed
+	}{
+		"perRun": {
+			lifecycle: LifecyclePerRun,
+		},
+		"perTask": {
+			lifecycle:      LifecyclePerTask,
+			sameRun:        true,
+			openAfterClose: true,
+		},
+		"perEval": {
+			lifecycle:      LifecyclePerEval,
+			sameRun:        true,
+			sameTask:       true,
+			openAfterClose: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			c := newTestConnections(t, tc.lifecycle)
+
+			// shared clients are leased to one run at a time, so each run closes its manager
+			first, a1 := newTestManager(t, c, "a")
+			require.NoError(t, first.Close(ctx))
+			if tc.openAfterClose {
+				assert.NoError(t, a1.Health(ctx))
+			} else {
+				assert.Error(t, a1.Health(ctx))
+			}
+
+			second, a2 := newTestManager(t, c, "a")
+			require.NoError(t, second.Close(ctx))
+			_, b := newTestManager(t, c, "b")
+
+			assert.Equal(t, tc.sameRun, a1 == a2)
+			assert.Equal(t, tc.sameTask, a1 == b)
+		})
+	}
+}
+
+func TestConnectionsLeaseSharedClients(t *testing.T) {
+	ctx := context.Background()
+	c := newTestConnections(t, LifecyclePerEval)
+
+	first, client := newTestManager(t, c, "a")
+	client.SetServerRequestHandler(&nopHandler{})
+	client.SetRoots([]*mcp.Root{{URI: "file:///workspace"}})
+
+	acquired := make(chan Manager, 1)
+	go func() {
+		m, err := c.NewManager(ctx, "b")
+		if err == nil {
+			acquired <- m
+		}
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("the shared client should not be handed out while another run uses it")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err := c.NewManager(waitCtx, "c")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	require.NoError(t, first.Close(ctx))
+
+	select {
+	case second := <-acquired:
+		t.Cleanup(func() { _ = second.Close(ctx) })
+		shared, ok := second.Get("test")
+		require.True(t, ok)
+		assert.Same(t, client, shared)
+		assert.Nil(t, shared.getHandler(), "the handler of the previous run should be cleared")
+		assert.Empty(t, shared.roots, "the roots of the previous run should be cleared")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the shared client was not handed to the waiting run")
+	}
+}
+
+// nopHandler is a ServerRequestHandler that ignores everything
+type nopHandler struct{}
+
+func (nopHandler) CreateMessage(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	return nil, nil
+}
+
+func (nopHandler) Elicit(context.Context, *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	return nil, nil
+}
+
+func (nopHandler) ListRoots(context.Context, *mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	return nil, nil
+}
+
+func (nopHandler) ListChanged(context.Context, string)                                     {}
+func (nopHandler) Progress(context.Context, *mcp.ProgressNotificationParams)               {}
+func (nopHandler) Log(context.Context, *mcp.LoggingMessageParams)                          {}
+func (nopHandler) ResourceUpdated(context.Context, *mcp.ResourceUpdatedNotificationParams) {}
+
+func TestConnectionsCloseTask(t *testing.T) {
+	ctx := context.Background()
+	c := newTestConnections(t, LifecyclePerTask)
+
+	first, a := newTestManager(t, c, "a")
+	require.NoError(t, first.Close(ctx))
+	_, b := newTestManager(t, c, "b")
+
+	require.NoError(t, c.CloseTask(ctx, "a"))
+	assert.Error(t, a.Health(ctx))
+	assert.NoError(t, b.Health(ctx))
+}
+
+func TestConnectionsReconnectAfterCrash(t *testing.T) {
+	ctx := context.Background()
+	c := newTestConnections(t, LifecyclePerEval)
+
+	first, client := newTestManager(t, c, "a")
+	_, err := client.CallTool(ctx, &mcp.CallToolParams{Name: "crash"})
+	require.Error(t, err)
+
+	require.Eventually(t, func() bool {
+		return client.disconnected()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	events := first.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "test", events[0].Server)
+	assert.Equal(t, ServerEventDisconnected, events[0].Type)
+	assert.Empty(t, first.Events()[1:], "the crash should only be reported once")
+	require.NoError(t, first.Close(ctx))
+
+	second, reconnected := newTestManager(t, c, "b")
+	assert.NotSame(t, client, reconnected)
+
+	events = second.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, ServerEventReconnected, events[0].Type)
+
+	res, err := reconnected.CallTool(ctx, &mcp.CallToolParams{Name: "echo"})
+	require.NoError(t, err)
+	assert.False(t, res.IsError)
+}
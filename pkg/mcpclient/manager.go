@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"slices"
+	"sort"
 	"time"
 )
 
@@ -13,14 +15,37 @@ type Manager interface {
 	Get(name string) (*Client, bool)
 	// GetAll returns all MCP clients
 	GetAll() map[string]*Client
+	// Events returns the connection events of the servers, such as crashes and reconnects
+	Events() []ServerEvent
 	// Close closes all the MCP client connections
 	Close(ctx context.Context) error
 }
 
+// Server event types
+const (
+	// ServerEventDisconnected means the connection to the server was lost, e.g. a stdio server crashed
+	ServerEventDisconnected = "disconnected"
+	// ServerEventUnhealthy means a connected server did not answer the health check
+	ServerEventUnhealthy = "unhealthy"
+	// ServerEventReconnected means a new connection replaced a disconnected or unhealthy one
+	ServerEventReconnected = "reconnected"
+)
+
+// ServerEvent records a change in the connection to an MCP server
+type ServerEvent struct {
+	Server    string    `json:"server"`
+	Type      string    `json:"type"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
 var _ Manager = &manager{}
 
 type manager struct {
 	sessions map[string]*Client
+	owned    map[string]bool // clients closed with the manager, shared clients are closed by their Connections
+	releases []func()        // hand the shared clients back to their Connections
+	events   []ServerEvent
 }
 
 func NewManager(ctx context.Context, config *MCPConfig) (Manager, error) {
@@ -34,9 +59,7 @@ func NewManager(ctx context.Context, config *MCPConfig) (Manager, error) {
 		return nil, fmt.Errorf("no enabled mcp servers found in config")
 	}
 
-	m := &manager{
-		sessions: make(map[string]*Client, len(servers)),
-	}
+	m := newManager(len(servers))
 
 	var err error
 	for name, cfg := range servers {
@@ -47,19 +70,31 @@ func NewManager(ctx context.Context, config *MCPConfig) (Manager, error) {
 		}
 
 		m.sessions[name] = cs
+		m.owned[name] = true
 	}
 
 	if err != nil {
-		ctx, cancel := context.WithTimeout(ctx, time.Second*15)
-		err = errors.Join(err, m.Close(ctx)) // clean up any successfully made connections
-		cancel()
-
-		return nil, err
+		return nil, m.closeOnError(ctx, err)
 	}
 
 	return m, nil
 }
 
+func newManager(size int) *manager {
+	return &manager{
+		sessions: make(map[string]*Client, size),
+		owned:    make(map[string]bool, size),
+	}
+}
+
+// closeOnError cleans up any successfully made connections after err
+func (m *manager) closeOnError(ctx context.Context, err error) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*15)
+	defer cancel()
+
+	return errors.Join(err, m.Close(ctx))
+}
+
 func (m *manager) Get(name string) (*Client, bool) {
 	cs, ok := m.sessions[name]
 	return cs, ok
@@ -69,17 +104,38 @@ func (m *manager) GetAll() map[string]*Client {
 	return maps.Clone(m.sessions)
 }
 
+func (m *manager) Events() []ServerEvent {
+	for name, cs := range m.sessions {
+		if ev, ok := cs.disconnectEvent(name); ok {
+			m.events = append(m.events, ev)
+		}
+	}
+
+	sort.SliceStable(m.events, func(i, j int) bool {
+		return m.events[i].Timestamp.Before(m.events[j].Timestamp)
+	})
+
+	return slices.Clone(m.events)
+}
+
+// Close closes the clients owned by the manager and releases the shared clients to the next run
 func (m *manager) Close(ctx context.Context) error {
-	results := make(chan error, len(m.sessions))
+	for _, release := range m.releases {
+		release()
+	}
+	m.releases = nil
+
+	results := make(chan error, len(m.owned))
 
-	for _, cs := range m.sessions {
+	for name := range m.owned {
+		cs := m.sessions[name]
 		go func() {
 			results <- cs.Close()
 		}()
 	}
 
 	var err error
-	for range len(m.sessions) {
+	for range len(m.owned) {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
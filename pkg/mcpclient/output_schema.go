@@ -0,0 +1,106 @@
+package mcpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// OutputSchemaValidator validates tool results against the outputSchema of the tool.
+// Resolved schemas are cached by tool name, so one validator should be kept per server.
+// A tool whose outputSchema changed is resolved again.
+type OutputSchemaValidator struct {
+	mu       sync.Mutex
+	resolved map[string]resolvedSchema
+}
+
+type resolvedSchema struct {
+	schema   []byte // the outputSchema as JSON, to notice changes
+	resolved *jsonschema.Resolved
+}
+
+func NewOutputSchemaValidator() *OutputSchemaValidator {
+	return &OutputSchemaValidator{
+		resolved: make(map[string]resolvedSchema),
+	}
+}
+
+// Retain drops the cached schemas of all tools but the named ones, e.g. after the tools
+// of the server changed
+func (v *OutputSchemaValidator) Retain(names ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for name := range v.resolved {
+		if !slices.Contains(names, name) {
+			delete(v.resolved, name)
+		}
+	}
+}
+
+// Validate checks the structured content of res against the outputSchema of tool.
+// Tools without an outputSchema and error results are not validated. A tool that
+// declares an outputSchema must return structured content that conforms to it.
+func (v *OutputSchemaValidator) Validate(tool *mcp.Tool, res *mcp.CallToolResult) error {
+	if tool == nil || tool.OutputSchema == nil || res == nil || res.IsError {
+		return nil
+	}
+
+	if res.StructuredContent == nil {
+		return fmt.Errorf("tool %q declares an outputSchema but returned no structured content", tool.Name)
+	}
+
+	resolved, err := v.resolve(tool)
+	if err != nil {
+		return fmt.Errorf("tool %q has an invalid outputSchema: %w", tool.Name, err)
+	}
+
+	// Round trip through JSON so that the content is validated as the agent receives it
+	data, err := json.Marshal(res.StructuredContent)
+	if err != nil {
+		return fmt.Errorf("failed to encode structured content of tool %q: %w", tool.Name, err)
+	}
+
+	var instance any
+	if err := json.Unmarshal(data, &instance); err != nil {
+		return fmt.Errorf("failed to decode structured content of tool %q: %w", tool.Name, err)
+	}
+
+	if err := resolved.Validate(instance); err != nil {
+		return fmt.Errorf("structured content of tool %q does not match its outputSchema: %w", tool.Name, err)
+	}
+
+	return nil
+}
+
+func (v *OutputSchemaValidator) resolve(tool *mcp.Tool) (*jsonschema.Resolved, error) {
+	data, err := json.Marshal(tool.OutputSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if cached, ok := v.resolved[tool.Name]; ok && bytes.Equal(cached.schema, data) {
+		return cached.resolved, nil
+	}
+
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return nil, err
+	}
+	v.resolved[tool.Name] = resolvedSchema{schema: data, resolved: resolved}
+
+	return resolved, nil
+}
@@ -0,0 +1,102 @@
+package mcpclient
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputSchemaValidatorValidate(t *testing.T) {
+	countSchema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"count": map[string]any{"type": "integer"}},
+		"required":   []any{"count"},
+	}
+	withSchema := &mcp.Tool{Name: "pods_count", OutputSchema: countSchema}
+	withoutSchema := &mcp.Tool{Name: "pods_list"}
+
+	tests := map[string]struct {
+		tool        *mcp.Tool
+		res         *mcp.CallToolResult
+		expectedErr string
+	}{
+		"matching structured content": {
+			tool: withSchema,
+			res:  &mcp.CallToolResult{StructuredContent: map[string]any{"count": 3}},
+		},
+		"typed structured content": {
+			tool: withSchema,
+			res: &mcp.CallToolResult{StructuredContent: struct {
+				Count int `json:"count"`
+			}{Count: 3}},
+		},
+		"wrong type": {
+			tool:        withSchema,
+			res:         &mcp.CallToolResult{StructuredContent: map[string]any{"count": "three"}},
+			expectedErr: `structured content of tool "pods_count" does not match its outputSchema`,
+		},
+		"missing required property": {
+			tool:        withSchema,
+			res:         &mcp.CallToolResult{StructuredContent: map[string]any{}},
+			expectedErr: "does not match its outputSchema",
+		},
+		"missing structured content": {
+			tool:        withSchema,
+			res:         &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "3"}}},
+			expectedErr: "returned no structured content",
+		},
+		"error results are not validated": {
+			tool: withSchema,
+			res:  &mcp.CallToolResult{IsError: true},
+		},
+		"tools without an outputSchema are not validated": {
+			tool: withoutSchema,
+			res:  &mcp.CallToolResult{StructuredContent: map[string]any{"anything": true}},
+		},
+		"invalid outputSchema": {
+			tool:        &mcp.Tool{Name: "broken", OutputSchema: map[string]any{"type": "object", "properties": map[string]any{"n": map[string]any{"$ref": "#/$defs/missing"}}}},
+			res:         &mcp.CallToolResult{StructuredContent: map[string]any{"n": 1}},
+			expectedErr: `tool "broken" has an invalid outputSchema`,
+		},
+	}
+
+	v := NewOutputSchemaValidator()
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := v.Validate(tc.tool, tc.res)
+			if tc.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestOutputSchemaValidatorCache(t *testing.T) {
+	v := NewOutputSchemaValidator()
+	res := &mcp.CallToolResult{StructuredContent: map[string]any{"count": 3}}
+
+	integer := &mcp.Tool{Name: "pods_count", OutputSchema: map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"count": map[string]any{"type": "integer"}},
+	}}
+	assert.NoError(t, v.Validate(integer, res))
+	assert.Len(t, v.resolved, 1)
+
+	// a new tool value with the same schema reuses the resolved schema
+	assert.NoError(t, v.Validate(&mcp.Tool{Name: "pods_count", OutputSchema: integer.OutputSchema}, res))
+	assert.Len(t, v.resolved, 1)
+
+	// a changed schema is resolved again
+	str := &mcp.Tool{Name: "pods_count", OutputSchema: map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"count": map[string]any{"type": "string"}},
+	}}
+	assert.ErrorContains(t, v.Validate(str, res), "does not match its outputSchema")
+	assert.Len(t, v.resolved, 1)
+
+	v.Retain("pods_list")
+	assert.Empty(t, v.resolved)
+}
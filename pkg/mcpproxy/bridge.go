@@ -0,0 +1,191 @@
+package mcpproxy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	rootsSyncTimeout = 30 * time.Second
+	listSyncTimeout  = 30 * time.Second
+)
+
+// Sampler services sampling requests when the agent does not support MCP sampling itself
+type Sampler interface {
+	CreateMessage(ctx context.Context, params *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error)
+}
+
+// Elicitor answers elicitation requests on behalf of the user
+type Elicitor interface {
+	Elicit(ctx context.Context, params *mcp.ElicitParams) (*mcp.ElicitResult, error)
+}
+
+// requestBridge forwards requests initiated by the MCP server (sampling, elicitation
+// and roots) to the agent connected to the proxy, recording each of them.
+type requestBridge struct {
+	client   *mcpclient.Client
+	recorder Recorder
+	sampler  Sampler
+	elicitor Elicitor
+
+	// proxy is the agent-facing server, set once it has been created
+	proxy *mcp.Server
+	// registry mirrors the server's tools, prompts and resources onto proxy
+	registry *registry
+}
+
+var _ mcpclient.ServerRequestHandler = &requestBridge{}
+
+func (b *requestBridge) CreateMessage(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	start := time.Now()
+	res, handler, err := b.createMessage(ctx, req.Params)
+	b.recorder.RecordSampling(req.Params, res, handler, err, start)
+	return res, err
+}
+
+func (b *requestBridge) createMessage(ctx context.Context, params *mcp.CreateMessageParams) (*mcp.CreateMessageResult, string, error) {
+	ss := b.agentSession(func(caps *mcp.ClientCapabilities) bool {
+		return caps.Sampling != nil
+	})
+	if ss != nil {
+		res, err := ss.CreateMessage(ctx, params)
+		return res, HandlerAgent, err
+	}
+
+	if b.sampler != nil {
+		res, err := b.sampler.CreateMessage(ctx, params)
+		return res, HandlerSampler, err
+	}
+
+	return nil, "", fmt.Errorf("sampling is not supported by the agent and no sampler is configured")
+}
+
+func (b *requestBridge) Elicit(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	start := time.Now()
+
+	if b.elicitor != nil {
+		res, err := b.elicitor.Elicit(ctx, req.Params)
+		b.recorder.RecordElicitation(req.Params, res, HandlerResponder, err, start)
+		return res, err
+	}
+
+	ss := b.agentSession(func(caps *mcp.ClientCapabilities) bool {
+		return caps.Elicitation != nil
+	})
+	if ss == nil {
+		err := fmt.Errorf("elicitation is not supported by the agent and no responder is configured")
+		b.recorder.RecordElicitation(req.Params, nil, "", err, start)
+		return nil, err
+	}
+
+	res, err := ss.Elicit(ctx, req.Params)
+	b.recorder.RecordElicitation(req.Params, res, HandlerAgent, err, start)
+	return res, err
+}
+
+// ListRoots answers with the roots of the agent, or with no roots if the agent does not support them
+func (b *requestBridge) ListRoots(ctx context.Context, req *mcp.ListRootsRequest) (*mcp.ListRootsResult, error) {
+	start := time.Now()
+
+	ss := b.agentSession(func(caps *mcp.ClientCapabilities) bool {
+		return caps.RootsV2 != nil
+	})
+	if ss == nil {
+		res := &mcp.ListRootsResult{Roots: []*mcp.Root{}}
+		b.recorder.RecordRootsList(res, nil, start)
+		return res, nil
+	}
+
+	res, err := ss.ListRoots(ctx, req.Params)
+	b.recorder.RecordRootsList(res, err, start)
+	return res, err
+}
+
+// rootsChanged refreshes the roots of the MCP client from the agent session, which
+// notifies the server that the roots changed
+func (b *requestBridge) rootsChanged(ctx context.Context, ss *mcp.ServerSession) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rootsSyncTimeout)
+		defer cancel()
+
+		res, err := ss.ListRoots(ctx, &mcp.ListRootsParams{})
+		if err != nil {
+			return
+		}
+
+		b.client.SetRoots(res.Roots)
+	}()
+}
+
+// ListChanged resyncs the proxy with the server's updated list, which notifies the agent
+func (b *requestBridge) ListChanged(ctx context.Context, list string) {
+	if b.registry == nil {
+		return
+	}
+
+	// listing from within the notification handler would block the client connection
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), listSyncTimeout)
+		defer cancel()
+
+		b.registry.refresh(ctx, list)
+	}()
+}
+
+// Progress records a progress notification and routes it to the agent session whose request it is for
+func (b *requestBridge) Progress(ctx context.Context, params *mcp.ProgressNotificationParams) {
+	b.recorder.RecordProgress(params, time.Now())
+
+	if b.registry == nil {
+		return
+	}
+	if ss := b.registry.progressSession(params.ProgressToken); ss != nil {
+		_ = ss.NotifyProgress(ctx, params)
+	}
+}
+
+// Log records a log message and forwards it to the agent sessions that enabled logging
+func (b *requestBridge) Log(ctx context.Context, params *mcp.LoggingMessageParams) {
+	b.recorder.RecordLogMessage(params, time.Now())
+
+	if b.proxy == nil {
+		return
+	}
+	for ss := range b.proxy.Sessions() {
+		_ = ss.Log(ctx, params)
+	}
+}
+
+// ResourceUpdated records a resource update and forwards it to the agent sessions subscribed to the resource
+func (b *requestBridge) ResourceUpdated(ctx context.Context, params *mcp.ResourceUpdatedNotificationParams) {
+	b.recorder.RecordResourceUpdate(params.URI, time.Now())
+
+	if b.proxy == nil {
+		return
+	}
+	_ = b.proxy.ResourceUpdated(ctx, params)
+}
+
+// agentSession returns the most recently connected agent session with a matching capability
+func (b *requestBridge) agentSession(supports func(caps *mcp.ClientCapabilities) bool) *mcp.ServerSession {
+	if b.proxy == nil {
+		return nil
+	}
+
+	var found *mcp.ServerSession
+	for ss := range b.proxy.Sessions() {
+		params := ss.InitializeParams()
+		if params == nil || params.Capabilities == nil {
+			continue
+		}
+		if supports(params.Capabilities) {
+			found = ss
+		}
+	}
+
+	return found
+}
@@ -0,0 +1,238 @@
+package mcpproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticSampler struct {
+	text string
+}
+
+func (s *staticSampler) CreateMessage(ctx context.Context, params *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+	return &mcp.CreateMessageResult{
+		Content: &mcp.TextContent{Text: s.text},
+		Model:   "static",
+		Role:    "assistant",
+	}, nil
+}
+
+// newUpstreamServer starts an MCP server whose tools issue server-initiated requests
+func newUpstreamServer(t *testing.T) *mcpclient.ServerConfig {
+	t.Helper()
+
+	s := mcp.NewServer(&mcp.Implementation{Name: "upstream", Version: "0.0.1"}, nil)
+	mcp.AddTool(s, &mcp.Tool{Name: "summarize"}, func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+		res, err := req.Session.CreateMessage(ctx, &mcp.CreateMessageParams{
+			MaxTokens: 100,
+			Messages: []*mcp.SamplingMessage{
+				{Role: "user", Content: &mcp.TextContent{Text: "summarize the logs"}},
+			},
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{res.Content}}, nil, nil
+	})
+	mcp.AddTool(s, &mcp.Tool{Name: "confirm"}, func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+		res, err := req.Session.Elicit(ctx, &mcp.ElicitParams{Message: "delete the pod?"})
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: res.Action}}}, nil, nil
+	})
+	mcp.AddTool(s, &mcp.Tool{Name: "roots"}, func(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
+		res, err := req.Session.ListRoots(ctx, &mcp.ListRootsParams{})
+		if err != nil {
+			return nil, nil, err
+		}
+		content := make([]mcp.Content, 0, len(res.Roots))
+		for _, r := range res.Roots {
+			content = append(content, &mcp.TextContent{Text: r.URI})
+		}
+		return &mcp.CallToolResult{Content: content}, nil, nil
+	})
+
+	ts := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return s }, nil))
+	t.Cleanup(ts.Close)
+
+	return &mcpclient.ServerConfig{Type: mcpclient.TransportTypeHttp, URL: ts.URL}
+}
+
+// startProxy connects to the upstream server and runs a recording proxy in front of it
+func startProxy(t *testing.T, opts ProxyOptions) (Server, *mcpclient.ServerConfig) {
+	t.Helper()
+	return startProxyFor(t, newUpstreamServer(t), opts)
+}
+
+func startProxyFor(t *testing.T, upstream *mcpclient.ServerConfig, opts ProxyOptions) (Server, *mcpclient.ServerConfig) {
+	t.Helper()
+	ctx := context.Background()
+
+	client, err := mcpclient.Connect(ctx, upstream)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+
+	srv, err := NewProxyServerForClient(ctx, "upstream", client, opts)
+	require.NoError(t, err)
+
+	go func() { _ = srv.Run(ctx) }()
+	require.NoError(t, srv.WaitReady(ctx))
+	t.Cleanup(func() { _ = srv.Close() })
+
+	cfg, err := srv.GetConfig()
+	require.NoError(t, err)
+
+	return srv, cfg
+}
+
+func connectAgent(t *testing.T, cfg *mcpclient.ServerConfig, opts *mcp.ClientOptions, roots ...*mcp.Root) *mcp.ClientSession {
+	t.Helper()
+
+	c := mcp.NewClient(&mcp.Implementation{Name: "agent", Version: "0.0.1"}, opts)
+	c.AddRoots(roots...)
+
+	cs, err := c.Connect(context.Background(), &mcp.StreamableClientTransport{Endpoint: cfg.URL}, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cs.Close() })
+
+	return cs
+}
+
+func toolText(t *testing.T, res *mcp.CallToolResult) string {
+	t.Helper()
+	require.NotEmpty(t, res.Content)
+	text, ok := res.Content[0].(*mcp.TextContent)
+	require.True(t, ok)
+	return text.Text
+}
+
+func TestBridgeSamplingForwardedToAgent(t *testing.T) {
+	srv, cfg := startProxy(t, ProxyOptions{Sampler: &staticSampler{text: "from sampler"}})
+
+	agent := connectAgent(t, cfg, &mcp.ClientOptions{
+		CreateMessageHandler: func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			return &mcp.CreateMessageResult{Content: &mcp.TextContent{Text: "from agent"}, Model: "agent", Role: "assistant"}, nil
+		},
+	})
+
+	res, err := agent.CallTool(context.Background(), &mcp.CallToolParams{Name: "summarize"})
+	require.NoError(t, err)
+	assert.Equal(t, "from agent", toolText(t, res))
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.SamplingRequests, 1)
+	assert.Equal(t, HandlerAgent, history.SamplingRequests[0].Handler)
+	assert.Equal(t, "summarize the logs", history.SamplingRequests[0].Text())
+	assert.True(t, history.SamplingRequests[0].Success)
+}
+
+func TestBridgeSamplingFallsBackToSampler(t *testing.T) {
+	srv, cfg := startProxy(t, ProxyOptions{Sampler: &staticSampler{text: "from sampler"}})
+	agent := connectAgent(t, cfg, nil)
+
+	res, err := agent.CallTool(context.Background(), &mcp.CallToolParams{Name: "summarize"})
+	require.NoError(t, err)
+	assert.Equal(t, "from sampler", toolText(t, res))
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.SamplingRequests, 1)
+	assert.Equal(t, HandlerSampler, history.SamplingRequests[0].Handler)
+}
+
+func TestBridgeSamplingUnsupported(t *testing.T) {
+	srv, cfg := startProxy(t, ProxyOptions{})
+	agent := connectAgent(t, cfg, nil)
+
+	res, err := agent.CallTool(context.Background(), &mcp.CallToolParams{Name: "summarize"})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.SamplingRequests, 1)
+	assert.False(t, history.SamplingRequests[0].Success)
+	assert.Contains(t, history.SamplingRequests[0].Error, "no sampler is configured")
+}
+
+func TestBridgeElicitation(t *testing.T) {
+	srv, cfg := startProxy(t, ProxyOptions{})
+	agent := connectAgent(t, cfg, &mcp.ClientOptions{
+		ElicitationHandler: func(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			return &mcp.ElicitResult{Action: "decline"}, nil
+		},
+	})
+
+	res, err := agent.CallTool(context.Background(), &mcp.CallToolParams{Name: "confirm"})
+	require.NoError(t, err)
+	assert.Equal(t, "decline", toolText(t, res))
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.Elicitations, 1)
+	assert.Equal(t, "delete the pod?", history.Elicitations[0].Message)
+	assert.Equal(t, "decline", history.Elicitations[0].Action)
+	assert.Equal(t, HandlerAgent, history.Elicitations[0].Handler)
+}
+
+type staticElicitor struct {
+	action string
+}
+
+func (e *staticElicitor) Elicit(ctx context.Context, params *mcp.ElicitParams) (*mcp.ElicitResult, error) {
+	return &mcp.ElicitResult{Action: e.action}, nil
+}
+
+func TestBridgeElicitationAnsweredByElicitor(t *testing.T) {
+	srv, cfg := startProxy(t, ProxyOptions{Elicitor: &staticElicitor{action: "accept"}})
+	agent := connectAgent(t, cfg, &mcp.ClientOptions{
+		ElicitationHandler: func(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			return &mcp.ElicitResult{Action: "decline"}, nil
+		},
+	})
+
+	res, err := agent.CallTool(context.Background(), &mcp.CallToolParams{Name: "confirm"})
+	require.NoError(t, err)
+	assert.Equal(t, "accept", toolText(t, res))
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.Elicitations, 1)
+	assert.Equal(t, "accept", history.Elicitations[0].Action)
+	assert.Equal(t, HandlerResponder, history.Elicitations[0].Handler)
+}
+
+func TestBridgeRoots(t *testing.T) {
+	srv, cfg := startProxy(t, ProxyOptions{})
+	agent := connectAgent(t, cfg, nil, &mcp.Root{URI: "file:///workspace"})
+
+	res, err := agent.CallTool(context.Background(), &mcp.CallToolParams{Name: "roots"})
+	require.NoError(t, err)
+	assert.Equal(t, "file:///workspace", toolText(t, res))
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.RootsLists, 1)
+	require.NotNil(t, history.RootsLists[0].Result)
+	require.Len(t, history.RootsLists[0].Result.Roots, 1)
+	assert.Equal(t, "file:///workspace", history.RootsLists[0].Result.Roots[0].URI)
+}
+
+func TestBridgeRootsUnsupported(t *testing.T) {
+	srv, cfg := startProxy(t, ProxyOptions{})
+	agent := connectAgent(t, cfg, &mcp.ClientOptions{
+		Capabilities: &mcp.ClientCapabilities{},
+	})
+
+	res, err := agent.CallTool(context.Background(), &mcp.CallToolParams{Name: "roots"})
+	require.NoError(t, err)
+	assert.False(t, res.IsError)
+	assert.Empty(t, res.Content)
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.RootsLists, 1)
+	assert.True(t, history.RootsLists[0].Success)
+}
@@ -0,0 +1,60 @@
+package mcpproxy
+
+import (
+	"context"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// protocolMiddleware records the list calls and cancellations the agent sends to the
+// proxy. When the server supports logging, the log level the agent sets is forwarded
+// to the server so that its log messages reach the proxy.
+func protocolMiddleware(cs *mcp.ClientSession, r Recorder, forwardLogLevel bool) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			start := time.Now()
+
+			switch method {
+			case "notifications/cancelled":
+				if params, ok := req.GetParams().(*mcp.CancelledParams); ok {
+					r.RecordCancellation(params, start)
+				}
+			case "logging/setLevel":
+				if params, ok := req.GetParams().(*mcp.SetLoggingLevelParams); ok && forwardLogLevel {
+					if err := cs.SetLoggingLevel(ctx, params); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			res, err := next(ctx, method, req)
+
+			switch method {
+			case MethodListTools, MethodListPrompts, MethodListResources, MethodListResourceTemplates:
+				count := 0
+				if err == nil {
+					count = listCount(res)
+				}
+				r.RecordListCall(method, count, err, start)
+			}
+
+			return res, err
+		}
+	}
+}
+
+func listCount(res mcp.Result) int {
+	switch res := res.(type) {
+	case *mcp.ListToolsResult:
+		return len(res.Tools)
+	case *mcp.ListPromptsResult:
+		return len(res.Prompts)
+	case *mcp.ListResourcesResult:
+		return len(res.Resources)
+	case *mcp.ListResourceTemplatesResult:
+		return len(res.ResourceTemplates)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,152 @@
+package mcpproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newProtocolServer starts an MCP server that reports progress, logs and supports completion
+func newProtocolServer(t *testing.T, started chan<- struct{}) *mcpclient.ServerConfig {
+	t.Helper()
+
+	s := mcp.NewServer(&mcp.Implementation{Name: "upstream", Version: "0.0.1"}, &mcp.ServerOptions{
+		CompletionHandler: func(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+			return &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{Values: []string{"default", "kube-system"}}}, nil
+		},
+	})
+	s.AddTool(&mcp.Tool{Name: "drain", InputSchema: map[string]any{"type": "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := req.Session.Log(ctx, &mcp.LoggingMessageParams{Level: "info", Data: "draining node"}); err != nil {
+			return nil, err
+		}
+		if err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: req.Params.GetProgressToken(),
+			Message:       "evicted pods",
+			Progress:      1,
+			Total:         2,
+		}); err != nil {
+			return nil, err
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "drained"}}}, nil
+	})
+	s.AddTool(&mcp.Tool{Name: "wait", InputSchema: map[string]any{"type": "object"}}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		started <- struct{}{}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	s.AddResource(&mcp.Resource{URI: "file:///config.yaml", Name: "config"}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "data"}}}, nil
+	})
+
+	ts := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return s }, nil))
+	t.Cleanup(ts.Close)
+
+	return &mcpclient.ServerConfig{Type: mcpclient.TransportTypeHttp, URL: ts.URL}
+}
+
+func TestProxyRecordsListCallsAndCompletions(t *testing.T) {
+	srv, cfg := startProxyFor(t, newProtocolServer(t, make(chan struct{}, 1)), ProxyOptions{})
+	agent := connectAgent(t, cfg, nil)
+	ctx := context.Background()
+
+	_, err := agent.ListResources(ctx, &mcp.ListResourcesParams{})
+	require.NoError(t, err)
+	_, err = agent.ListTools(ctx, &mcp.ListToolsParams{})
+	require.NoError(t, err)
+
+	res, err := agent.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/resource", URI: "file:///{namespace}"},
+		Argument: mcp.CompleteParamsArgument{Name: "namespace", Value: "k"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"default", "kube-system"}, res.Completion.Values)
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.ListCalls, 2)
+	assert.Equal(t, MethodListResources, history.ListCalls[0].Method)
+	assert.Equal(t, ListResources, history.ListCalls[0].List())
+	assert.Equal(t, 1, history.ListCalls[0].Count)
+	assert.Equal(t, MethodListTools, history.ListCalls[1].Method)
+	assert.Equal(t, 2, history.ListCalls[1].Count)
+	assert.False(t, history.ListCalls[1].Timestamp.Before(history.ListCalls[0].Timestamp))
+
+	require.Len(t, history.Completions, 1)
+	assert.Equal(t, "namespace", history.Completions[0].Request.Argument.Name)
+	assert.Equal(t, res, history.Completions[0].Result)
+}
+
+func TestProxyForwardsProgressAndLogs(t *testing.T) {
+	srv, cfg := startProxyFor(t, newProtocolServer(t, make(chan struct{}, 1)), ProxyOptions{})
+
+	progress := make(chan *mcp.ProgressNotificationParams, 1)
+	logs := make(chan *mcp.LoggingMessageParams, 1)
+	agent := connectAgent(t, cfg, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(ctx context.Context, req *mcp.ProgressNotificationClientRequest) {
+			progress <- req.Params
+		},
+		LoggingMessageHandler: func(ctx context.Context, req *mcp.LoggingMessageRequest) {
+			logs <- req.Params
+		},
+	})
+	ctx := context.Background()
+
+	require.NoError(t, agent.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "debug"}))
+
+	params := &mcp.CallToolParams{Name: "drain"}
+	params.SetProgressToken("drain-1")
+	_, err := agent.CallTool(ctx, params)
+	require.NoError(t, err)
+
+	select {
+	case p := <-progress:
+		assert.Equal(t, "drain-1", p.ProgressToken)
+		assert.Equal(t, "evicted pods", p.Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent did not receive the progress notification")
+	}
+
+	select {
+	case l := <-logs:
+		assert.Equal(t, "draining node", l.Data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent did not receive the log message")
+	}
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.Progress, 1)
+	assert.Equal(t, float64(1), history.Progress[0].Params.Progress)
+	require.Len(t, history.LogMessages, 1)
+	assert.Equal(t, mcp.LoggingLevel("info"), history.LogMessages[0].Params.Level)
+}
+
+func TestProxyRecordsCancellations(t *testing.T) {
+	started := make(chan struct{}, 1)
+	srv, cfg := startProxyFor(t, newProtocolServer(t, started), ProxyOptions{})
+	agent := connectAgent(t, cfg, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := agent.CallTool(ctx, &mcp.CallToolParams{Name: "wait"})
+		errs <- err
+	}()
+
+	<-started
+	cancel()
+	require.Error(t, <-errs)
+
+	require.Eventually(t, func() bool {
+		return len(srv.GetCallHistory().Cancellations) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+
+	cancellation := srv.GetCallHistory().Cancellations[0]
+	assert.NotNil(t, cancellation.RequestID)
+	assert.Equal(t, context.Canceled.Error(), cancellation.Reason)
+}
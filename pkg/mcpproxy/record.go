@@ -3,9 +3,11 @@ package mcpproxy
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
 	"github.com/modelcontextprotocol/go-sdk/auth"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -14,9 +16,32 @@ type Recorder interface {
 	RecordToolCall(req *mcp.CallToolRequest, res *mcp.CallToolResult, err error, start time.Time)
 	RecordResourceRead(req *mcp.ReadResourceRequest, res *mcp.ReadResourceResult, err error, start time.Time)
 	RecordPromptGet(req *mcp.GetPromptRequest, res *mcp.GetPromptResult, err error, start time.Time)
+	RecordSampling(req *mcp.CreateMessageParams, res *mcp.CreateMessageResult, handler string, err error, start time.Time)
+	RecordElicitation(req *mcp.ElicitParams, res *mcp.ElicitResult, handler string, err error, start time.Time)
+	RecordRootsList(res *mcp.ListRootsResult, err error, start time.Time)
+	RecordListChange(list string, added, removed, updated []string, err error, start time.Time)
+	RecordListCall(method string, count int, err error, start time.Time)
+	RecordCompletion(req *mcp.CompleteParams, res *mcp.CompleteResult, err error, start time.Time)
+	RecordProgress(params *mcp.ProgressNotificationParams, at time.Time)
+	RecordLogMessage(params *mcp.LoggingMessageParams, at time.Time)
+	RecordCancellation(params *mcp.CancelledParams, at time.Time)
+	RecordSubscription(uri string, unsubscribe bool, err error, start time.Time)
+	RecordResourceUpdate(uri string, at time.Time)
+	RecordOutputSchemaViolation(tool string, err error, at time.Time)
 	GetHistory() CallHistory
+	// Reset drops everything recorded so far
+	Reset()
 }
 
+const (
+	// HandlerAgent means a server-initiated request was forwarded to the agent
+	HandlerAgent = "agent"
+	// HandlerSampler means a sampling request was answered by the configured sampler
+	HandlerSampler = "sampler"
+	// HandlerResponder means an elicitation request was answered by the task's elicitation responder
+	HandlerResponder = "responder"
+)
+
 // TokenCount provides token count estimates for a single MCP call.
 type TokenCount struct {
 	InputTokens  int64 `json:"inputTokens"`
@@ -135,11 +160,170 @@ func (p *PromptGet) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// SamplingRequest records a sampling/createMessage request sent by the server
+type SamplingRequest struct {
+	CallRecord
+	Handler string                   `json:"handler,omitempty"` // who answered the request, see HandlerAgent and HandlerSampler
+	Request *mcp.CreateMessageParams `json:"request"`
+	Result  *mcp.CreateMessageResult `json:"result,omitempty"`
+}
+
+// Text returns the text content of all messages in the request
+func (s *SamplingRequest) Text() string {
+	if s.Request == nil {
+		return ""
+	}
+
+	var parts []string
+	if s.Request.SystemPrompt != "" {
+		parts = append(parts, s.Request.SystemPrompt)
+	}
+	for _, m := range s.Request.Messages {
+		if m == nil {
+			continue
+		}
+		if text, ok := m.Content.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+}
+
+// Elicitation records an elicitation/create request sent by the server
+type Elicitation struct {
+	CallRecord
+	Message string            `json:"message"` // this is copied to the top level struct for convenience
+	Action  string            `json:"action,omitempty"`
+	Handler string            `json:"handler,omitempty"`
+	Request *mcp.ElicitParams `json:"request"`
+	Result  *mcp.ElicitResult `json:"result,omitempty"`
+}
+
+// RootsList records a roots/list request sent by the server
+type RootsList struct {
+	CallRecord
+	Result *mcp.ListRootsResult `json:"result,omitempty"`
+}
+
+const (
+	ListTools     = mcpclient.ListTools
+	ListPrompts   = mcpclient.ListPrompts
+	ListResources = mcpclient.ListResources
+)
+
+// ListChange records a list_changed notification from the server and how the
+// proxy's tools, prompts or resources changed as a result
+type ListChange struct {
+	CallRecord
+	// List is the list that changed: tools, prompts or resources
+	List    string   `json:"list"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+}
+
+// ListCall records a tools/list, prompts/list, resources/list or resources/templates/list request
+// from the agent. Each page of a paginated list is recorded separately.
+type ListCall struct {
+	CallRecord
+	Method string `json:"method"`
+	Count  int    `json:"count"` // number of items returned
+}
+
+// List returns the list the call was made for: tools, prompts or resources
+func (l *ListCall) List() string {
+	switch l.Method {
+	case MethodListTools:
+		return ListTools
+	case MethodListPrompts:
+		return ListPrompts
+	case MethodListResources, MethodListResourceTemplates:
+		return ListResources
+	default:
+		return ""
+	}
+}
+
+const (
+	MethodListTools             = "tools/list"
+	MethodListPrompts           = "prompts/list"
+	MethodListResources         = "resources/list"
+	MethodListResourceTemplates = "resources/templates/list"
+)
+
+// Completion records a completion/complete request from the agent
+type Completion struct {
+	CallRecord
+	Request *mcp.CompleteParams `json:"request"`
+	Result  *mcp.CompleteResult `json:"result,omitempty"`
+}
+
+// ProgressNotification records a progress notification sent by the server
+type ProgressNotification struct {
+	CallRecord
+	Params *mcp.ProgressNotificationParams `json:"params"`
+}
+
+// LogMessage records a logging message sent by the server
+type LogMessage struct {
+	CallRecord
+	Params *mcp.LoggingMessageParams `json:"params"`
+}
+
+// Cancellation records a cancelled notification sent by the agent
+type Cancellation struct {
+	CallRecord
+	RequestID any    `json:"requestId"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Subscription records a resources/subscribe or resources/unsubscribe request from the agent
+type Subscription struct {
+	CallRecord
+	URI         string `json:"uri"`
+	Unsubscribe bool   `json:"unsubscribe,omitempty"`
+}
+
+// ResourceUpdate records a resources/updated notification sent by the server
+type ResourceUpdate struct {
+	CallRecord
+	URI string `json:"uri"`
+}
+
+// OutputSchemaViolation records a tool result whose structured content did not match the outputSchema of the tool
+type OutputSchemaViolation struct {
+	CallRecord
+	ToolName string `json:"name"`
+}
+
 // CallHistory contains a complete call history for a server
 type CallHistory struct {
 	ToolCalls     []*ToolCall
 	ResourceReads []*ResourceRead
 	PromptGets    []*PromptGet
+
+	// Requests initiated by the server
+	SamplingRequests []*SamplingRequest
+	Elicitations     []*Elicitation
+	RootsLists       []*RootsList
+
+	// Changes to the tools, prompts or resources the server offers
+	ListChanges []*ListChange
+
+	// Protocol-level events
+	ListCalls     []*ListCall
+	Completions   []*Completion
+	Progress      []*ProgressNotification
+	LogMessages   []*LogMessage
+	Cancellations []*Cancellation
+
+	// Resource subscriptions and the updates sent for them
+	Subscriptions   []*Subscription
+	ResourceUpdates []*ResourceUpdate
+
+	// Tool results that did not match the outputSchema of their tool
+	OutputSchemaViolations []*OutputSchemaViolation
 }
 
 type recorder struct {
@@ -154,11 +338,28 @@ var _ Recorder = &recorder{}
 func NewRecorder(serverName string) Recorder {
 	return &recorder{
 		serverName: serverName,
-		history: &CallHistory{
-			ToolCalls:     make([]*ToolCall, 0),
-			ResourceReads: make([]*ResourceRead, 0),
-			PromptGets:    make([]*PromptGet, 0),
-		},
+		history:    newCallHistory(),
+	}
+}
+
+func newCallHistory() *CallHistory {
+	return &CallHistory{
+		ToolCalls:        make([]*ToolCall, 0),
+		ResourceReads:    make([]*ResourceRead, 0),
+		PromptGets:       make([]*PromptGet, 0),
+		SamplingRequests: make([]*SamplingRequest, 0),
+		Elicitations:     make([]*Elicitation, 0),
+		RootsLists:       make([]*RootsList, 0),
+		ListChanges:      make([]*ListChange, 0),
+		ListCalls:        make([]*ListCall, 0),
+		Completions:      make([]*Completion, 0),
+		Progress:         make([]*ProgressNotification, 0),
+		LogMessages:      make([]*LogMessage, 0),
+		Cancellations:    make([]*Cancellation, 0),
+		Subscriptions:    make([]*Subscription, 0),
+		ResourceUpdates:  make([]*ResourceUpdate, 0),
+
+		OutputSchemaViolations: make([]*OutputSchemaViolation, 0),
 	}
 }
 
@@ -213,6 +414,201 @@ func (r *recorder) RecordPromptGet(req *mcp.GetPromptRequest, res *mcp.GetPrompt
 	})
 }
 
+func (r *recorder) RecordSampling(req *mcp.CreateMessageParams, res *mcp.CreateMessageResult, handler string, err error, start time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history.SamplingRequests = append(r.history.SamplingRequests, &SamplingRequest{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  start,
+			Success:    err == nil,
+			Error:      errorToString(err),
+		},
+		Handler: handler,
+		Request: req,
+		Result:  res,
+	})
+}
+
+func (r *recorder) RecordElicitation(req *mcp.ElicitParams, res *mcp.ElicitResult, handler string, err error, start time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e := &Elicitation{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  start,
+			Success:    err == nil,
+			Error:      errorToString(err),
+		},
+		Handler: handler,
+		Request: req,
+		Result:  res,
+	}
+	if req != nil {
+		e.Message = req.Message
+	}
+	if res != nil {
+		e.Action = res.Action
+	}
+
+	r.history.Elicitations = append(r.history.Elicitations, e)
+}
+
+func (r *recorder) RecordRootsList(res *mcp.ListRootsResult, err error, start time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history.RootsLists = append(r.history.RootsLists, &RootsList{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  start,
+			Success:    err == nil,
+			Error:      errorToString(err),
+		},
+		Result: res,
+	})
+}
+
+func (r *recorder) RecordListChange(list string, added, removed, updated []string, err error, start time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history.ListChanges = append(r.history.ListChanges, &ListChange{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  start,
+			Success:    err == nil,
+			Error:      errorToString(err),
+		},
+		List:    list,
+		Added:   added,
+		Removed: removed,
+		Updated: updated,
+	})
+}
+
+func (r *recorder) RecordListCall(method string, count int, err error, start time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history.ListCalls = append(r.history.ListCalls, &ListCall{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  start,
+			Success:    err == nil,
+			Error:      errorToString(err),
+		},
+		Method: method,
+		Count:  count,
+	})
+}
+
+func (r *recorder) RecordCompletion(req *mcp.CompleteParams, res *mcp.CompleteResult, err error, start time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history.Completions = append(r.history.Completions, &Completion{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  start,
+			Success:    err == nil,
+			Error:      errorToString(err),
+		},
+		Request: req,
+		Result:  res,
+	})
+}
+
+func (r *recorder) RecordProgress(params *mcp.ProgressNotificationParams, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history.Progress = append(r.history.Progress, &ProgressNotification{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  at,
+			Success:    true,
+		},
+		Params: params,
+	})
+}
+
+func (r *recorder) RecordLogMessage(params *mcp.LoggingMessageParams, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history.LogMessages = append(r.history.LogMessages, &LogMessage{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  at,
+			Success:    true,
+		},
+		Params: params,
+	})
+}
+
+func (r *recorder) RecordCancellation(params *mcp.CancelledParams, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history.Cancellations = append(r.history.Cancellations, &Cancellation{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  at,
+			Success:    true,
+		},
+		RequestID: params.RequestID,
+		Reason:    params.Reason,
+	})
+}
+
+func (r *recorder) RecordSubscription(uri string, unsubscribe bool, err error, start time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history.Subscriptions = append(r.history.Subscriptions, &Subscription{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  start,
+			Success:    err == nil,
+			Error:      errorToString(err),
+		},
+		URI:         uri,
+		Unsubscribe: unsubscribe,
+	})
+}
+
+func (r *recorder) RecordResourceUpdate(uri string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history.ResourceUpdates = append(r.history.ResourceUpdates, &ResourceUpdate{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  at,
+			Success:    true,
+		},
+		URI: uri,
+	})
+}
+
+func (r *recorder) RecordOutputSchemaViolation(tool string, err error, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history.OutputSchemaViolations = append(r.history.OutputSchemaViolations, &OutputSchemaViolation{
+		CallRecord: CallRecord{
+			ServerName: r.serverName,
+			Timestamp:  at,
+			Success:    false,
+			Error:      errorToString(err),
+		},
+		ToolName: tool,
+	})
+}
+
 func (r *recorder) GetHistory() CallHistory {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -220,6 +616,13 @@ func (r *recorder) GetHistory() CallHistory {
 	return *r.history
 }
 
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.history = newCallHistory()
+}
+
 func errorToString(err error) string {
 	if err == nil {
 		return ""
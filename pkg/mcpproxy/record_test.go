@@ -415,6 +415,17 @@ func TestNewRecorder(t *testing.T) {
 			assert.NotNil(t, history.ToolCalls)
 			assert.NotNil(t, history.ResourceReads)
 			assert.NotNil(t, history.PromptGets)
+			assert.NotNil(t, history.SamplingRequests)
+			assert.NotNil(t, history.Elicitations)
+			assert.NotNil(t, history.RootsLists)
+			assert.NotNil(t, history.ListChanges)
+			assert.NotNil(t, history.ListCalls)
+			assert.NotNil(t, history.Completions)
+			assert.NotNil(t, history.Progress)
+			assert.NotNil(t, history.LogMessages)
+			assert.NotNil(t, history.Cancellations)
+			assert.NotNil(t, history.Subscriptions)
+			assert.NotNil(t, history.ResourceUpdates)
 			assert.Len(t, history.ToolCalls, 0)
 			assert.Len(t, history.ResourceReads, 0)
 			assert.Len(t, history.PromptGets, 0)
@@ -497,6 +508,27 @@ func TestRecorderRecordToolCallAggregation(t *testing.T) {
 	assert.Equal(t, "tool-c", history.ToolCalls[2].ToolName)
 }
 
+func TestRecorderReset(t *testing.T) {
+	rec := NewRecorder("test-server")
+	req := &mcp.ServerRequest[*mcp.CallToolParamsRaw]{
+		Params: &mcp.CallToolParamsRaw{Name: "failed-attempt"},
+	}
+	rec.RecordToolCall(req, nil, nil, time.Now())
+	before := rec.GetHistory()
+
+	rec.Reset()
+	history := rec.GetHistory()
+	assert.NotNil(t, history.ToolCalls)
+	assert.Empty(t, history.ToolCalls)
+	assert.Len(t, before.ToolCalls, 1, "histories returned before the reset are kept")
+
+	req.Params.Name = "last-attempt"
+	rec.RecordToolCall(req, nil, nil, time.Now())
+	history = rec.GetHistory()
+	require.Len(t, history.ToolCalls, 1)
+	assert.Equal(t, "last-attempt", history.ToolCalls[0].ToolName)
+}
+
 func TestRecorderRecordResourceRead(t *testing.T) {
 	fixedTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
 
@@ -609,6 +641,164 @@ func TestRecorderRecordPromptGet(t *testing.T) {
 	}
 }
 
+func TestRecorderRecordSampling(t *testing.T) {
+	fixedTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		request         *mcp.CreateMessageParams
+		result          *mcp.CreateMessageResult
+		handler         string
+		err             error
+		expectedSuccess bool
+		expectedError   string
+		expectedText    string
+	}{
+		"answered by agent": {
+			request: &mcp.CreateMessageParams{
+				SystemPrompt: "be brief",
+				Messages: []*mcp.SamplingMessage{
+					{Role: "user", Content: &mcp.TextContent{Text: "summarize"}},
+				},
+			},
+			result:          &mcp.CreateMessageResult{Content: &mcp.TextContent{Text: "ok"}},
+			handler:         HandlerAgent,
+			expectedSuccess: true,
+			expectedText:    "be brief\nsummarize",
+		},
+		"failed request": {
+			request:         &mcp.CreateMessageParams{},
+			err:             errors.New("sampling is not supported"),
+			expectedSuccess: false,
+			expectedError:   "sampling is not supported",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rec := NewRecorder("test-server")
+			rec.RecordSampling(tc.request, tc.result, tc.handler, tc.err, fixedTime)
+
+			history := rec.GetHistory()
+			require.Len(t, history.SamplingRequests, 1)
+
+			sampling := history.SamplingRequests[0]
+			assert.Equal(t, "test-server", sampling.ServerName)
+			assert.Equal(t, fixedTime, sampling.Timestamp)
+			assert.Equal(t, tc.expectedSuccess, sampling.Success)
+			assert.Equal(t, tc.expectedError, sampling.Error)
+			assert.Equal(t, tc.handler, sampling.Handler)
+			assert.Equal(t, tc.expectedText, sampling.Text())
+			assert.Equal(t, tc.result, sampling.Result)
+		})
+	}
+}
+
+func TestRecorderRecordElicitation(t *testing.T) {
+	fixedTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	rec := NewRecorder("test-server")
+	rec.RecordElicitation(&mcp.ElicitParams{Message: "delete the pod?"}, &mcp.ElicitResult{Action: "accept"}, HandlerAgent, nil, fixedTime)
+	rec.RecordElicitation(&mcp.ElicitParams{Message: "continue?"}, nil, "", errors.New("elicitation is not supported"), fixedTime)
+
+	history := rec.GetHistory()
+	require.Len(t, history.Elicitations, 2)
+
+	assert.Equal(t, "delete the pod?", history.Elicitations[0].Message)
+	assert.Equal(t, "accept", history.Elicitations[0].Action)
+	assert.True(t, history.Elicitations[0].Success)
+
+	assert.Equal(t, "continue?", history.Elicitations[1].Message)
+	assert.Empty(t, history.Elicitations[1].Action)
+	assert.False(t, history.Elicitations[1].Success)
+	assert.Equal(t, "elicitation is not supported", history.Elicitations[1].Error)
+}
+
+func TestRecorderRecordRootsList(t *testing.T) {
+	fixedTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	rec := NewRecorder("test-server")
+	res := &mcp.ListRootsResult{Roots: []*mcp.Root{{URI: "file:///workspace"}}}
+	rec.RecordRootsList(res, nil, fixedTime)
+
+	history := rec.GetHistory()
+	require.Len(t, history.RootsLists, 1)
+	assert.Equal(t, "test-server", history.RootsLists[0].ServerName)
+	assert.True(t, history.RootsLists[0].Success)
+	assert.Equal(t, res, history.RootsLists[0].Result)
+}
+
+func TestRecorderRecordListChange(t *testing.T) {
+	fixedTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	rec := NewRecorder("test-server")
+	rec.RecordListChange(ListTools, []string{"scale"}, []string{"delete"}, nil, nil, fixedTime)
+	rec.RecordListChange(ListPrompts, nil, nil, nil, errors.New("connection closed"), fixedTime.Add(time.Second))
+
+	history := rec.GetHistory()
+	require.Len(t, history.ListChanges, 2)
+	assert.Equal(t, "test-server", history.ListChanges[0].ServerName)
+	assert.Equal(t, ListTools, history.ListChanges[0].List)
+	assert.Equal(t, []string{"scale"}, history.ListChanges[0].Added)
+	assert.Equal(t, []string{"delete"}, history.ListChanges[0].Removed)
+	assert.Empty(t, history.ListChanges[0].Updated)
+	assert.True(t, history.ListChanges[0].Success)
+
+	assert.Equal(t, ListPrompts, history.ListChanges[1].List)
+	assert.False(t, history.ListChanges[1].Success)
+	assert.Equal(t, "connection closed", history.ListChanges[1].Error)
+}
+
+func TestRecorderRecordProtocolEvents(t *testing.T) {
+	fixedTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	rec := NewRecorder("test-server")
+	rec.RecordListCall(MethodListResourceTemplates, 3, nil, fixedTime)
+	rec.RecordListCall(MethodListPrompts, 0, errors.New("method not found"), fixedTime)
+	rec.RecordCompletion(&mcp.CompleteParams{Argument: mcp.CompleteParamsArgument{Name: "namespace"}}, nil, nil, fixedTime)
+	rec.RecordProgress(&mcp.ProgressNotificationParams{ProgressToken: "t1", Progress: 1}, fixedTime)
+	rec.RecordLogMessage(&mcp.LoggingMessageParams{Level: "warning", Data: "slow"}, fixedTime)
+	rec.RecordCancellation(&mcp.CancelledParams{RequestID: float64(4), Reason: "timeout"}, fixedTime)
+
+	history := rec.GetHistory()
+	require.Len(t, history.ListCalls, 2)
+	assert.Equal(t, ListResources, history.ListCalls[0].List())
+	assert.Equal(t, 3, history.ListCalls[0].Count)
+	assert.True(t, history.ListCalls[0].Success)
+	assert.Equal(t, ListPrompts, history.ListCalls[1].List())
+	assert.Equal(t, "method not found", history.ListCalls[1].Error)
+
+	require.Len(t, history.Completions, 1)
+	assert.Equal(t, "test-server", history.Completions[0].ServerName)
+	require.Len(t, history.Progress, 1)
+	assert.Equal(t, "t1", history.Progress[0].Params.ProgressToken)
+	require.Len(t, history.LogMessages, 1)
+	assert.Equal(t, "slow", history.LogMessages[0].Params.Data)
+	require.Len(t, history.Cancellations, 1)
+	assert.Equal(t, float64(4), history.Cancellations[0].RequestID)
+	assert.Equal(t, "timeout", history.Cancellations[0].Reason)
+}
+
+func TestRecorderRecordSubscription(t *testing.T) {
+	fixedTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	rec := NewRecorder("test-server")
+	rec.RecordSubscription("file:///logs", false, nil, fixedTime)
+	rec.RecordSubscription("file:///logs", true, errors.New("not subscribed"), fixedTime.Add(time.Second))
+	rec.RecordResourceUpdate("file:///logs", fixedTime)
+
+	history := rec.GetHistory()
+	require.Len(t, history.Subscriptions, 2)
+	assert.Equal(t, "test-server", history.Subscriptions[0].ServerName)
+	assert.Equal(t, "file:///logs", history.Subscriptions[0].URI)
+	assert.False(t, history.Subscriptions[0].Unsubscribe)
+	assert.True(t, history.Subscriptions[0].Success)
+	assert.True(t, history.Subscriptions[1].Unsubscribe)
+	assert.Equal(t, "not subscribed", history.Subscriptions[1].Error)
+
+	require.Len(t, history.ResourceUpdates, 1)
+	assert.Equal(t, "file:///logs", history.ResourceUpdates[0].URI)
+}
+
 func TestRecorderGetHistory(t *testing.T) {
 	fixedTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
 
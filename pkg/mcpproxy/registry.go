@@ -0,0 +1,320 @@
+package mcpproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/mcpchecker/mcpchecker/pkg/toolfilter"
+	"github.com/mcpchecker/mcpchecker/pkg/tooloverride"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const progressGracePeriod = 5 * time.Second
+
+// registry mirrors the tools, prompts and resources of the MCP server onto the
+// proxy server. Each sync lists the upstream items and adds, updates or removes
+// the proxy registrations to match, the proxy server then notifies the agent.
+type registry struct {
+	name     string
+	cs       *mcp.ClientSession
+	server   *mcp.Server
+	recorder Recorder
+	filter   *toolfilter.Filter           // hides tools from the agent
+	override *tooloverride.ServerOverride // rewrites tools as the agent sees them
+	outputs  *mcpclient.OutputSchemaValidator
+
+	mu        sync.Mutex // held for the duration of a refresh
+	tools     map[string][]byte
+	prompts   map[string][]byte
+	resources map[string][]byte
+	templates map[string][]byte
+
+	// progress maps the progress tokens of in-flight requests to the agent session that sent them
+	progress sync.Map
+}
+
+func newRegistry(name string, cs *mcp.ClientSession, s *mcp.Server, r Recorder, opts ProxyOptions) *registry {
+	return &registry{
+		name:      name,
+		cs:        cs,
+		server:    s,
+		recorder:  r,
+		filter:    opts.ToolFilter,
+		override:  opts.ToolOverrides.Server(name),
+		outputs:   mcpclient.NewOutputSchemaValidator(),
+		tools:     make(map[string][]byte),
+		prompts:   make(map[string][]byte),
+		resources: make(map[string][]byte),
+		templates: make(map[string][]byte),
+	}
+}
+
+// refresh syncs list with the MCP server and records the change. Refreshes are
+// serialized so that an older listing never overwrites a newer one.
+func (g *registry) refresh(ctx context.Context, list string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	start := time.Now()
+
+	var added, removed, updated []string
+	var err error
+	switch list {
+	case ListTools:
+		added, removed, updated, err = g.syncTools(ctx)
+	case ListPrompts:
+		added, removed, updated, err = g.syncPrompts(ctx)
+	case ListResources:
+		added, removed, updated, err = g.syncResources(ctx)
+	default:
+		return
+	}
+
+	g.recorder.RecordListChange(list, added, removed, updated, err, start)
+}
+
+func (g *registry) syncTools(ctx context.Context) (added, removed, updated []string, err error) {
+	var tools []*mcp.Tool
+	for t, err := range g.cs.Tools(ctx, &mcp.ListToolsParams{}) {
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !g.filter.Allows(g.name, t.Name) {
+			continue
+		}
+		tools = append(tools, t)
+	}
+
+	seen := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		advertised := g.override.ApplyTool(t)
+		seen[advertised.Name] = true
+		if !diff(g.tools, advertised.Name, advertised, &added, &updated) {
+			continue
+		}
+
+		tool := t
+		g.server.AddTool(advertised, func(ctx context.Context, ctr *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			defer g.trackProgress(ctr.Session, ctr.Params)()
+
+			start := time.Now()
+			res, err := g.cs.CallTool(ctx, &mcp.CallToolParams{
+				Meta:      ctr.Params.Meta,
+				Name:      tool.Name,
+				Arguments: ctr.Params.Arguments,
+			})
+			g.recorder.RecordToolCall(withToolName(ctr, tool.Name), res, err, start)
+			if err == nil {
+				if schemaErr := g.outputs.Validate(tool, res); schemaErr != nil {
+					g.recorder.RecordOutputSchemaViolation(tool.Name, schemaErr, start)
+				}
+			}
+			return res, err
+		})
+	}
+
+	removed = stale(g.tools, seen)
+	if len(removed) > 0 {
+		g.server.RemoveTools(removed...)
+	}
+
+	names := make([]string, 0, len(tools))
+	for _, t := range tools {
+		names = append(names, t.Name)
+	}
+	g.outputs.Retain(names...)
+
+	return added, removed, updated, nil
+}
+
+// withToolName returns req calling the named tool, so that calls to renamed tools
+// are recorded under the name the server knows them by
+func withToolName(req *mcp.CallToolRequest, name string) *mcp.CallToolRequest {
+	if req.Params.Name == name {
+		return req
+	}
+
+	params := *req.Params
+	params.Name = name
+	renamed := *req
+	renamed.Params = &params
+	return &renamed
+}
+
+func (g *registry) syncPrompts(ctx context.Context) (added, removed, updated []string, err error) {
+	var prompts []*mcp.Prompt
+	for p, err := range g.cs.Prompts(ctx, &mcp.ListPromptsParams{}) {
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		prompts = append(prompts, p)
+	}
+
+	seen := make(map[string]bool, len(prompts))
+	for _, p := range prompts {
+		seen[p.Name] = true
+		if !diff(g.prompts, p.Name, p, &added, &updated) {
+			continue
+		}
+
+		g.server.AddPrompt(p, func(ctx context.Context, gpr *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+			defer g.trackProgress(gpr.Session, gpr.Params)()
+
+			start := time.Now()
+			res, err := g.cs.GetPrompt(ctx, gpr.Params)
+			g.recorder.RecordPromptGet(gpr, res, err, start)
+			return res, err
+		})
+	}
+
+	removed = stale(g.prompts, seen)
+	if len(removed) > 0 {
+		g.server.RemovePrompts(removed...)
+	}
+
+	return added, removed, updated, nil
+}
+
+// syncResources syncs both resources and resource templates, which share the resources
+// list_changed notification. A failed listing of one does not stop the other from being
+// synced, and the registrations of a failed listing are kept as they were.
+func (g *registry) syncResources(ctx context.Context) (added, removed, updated []string, err error) {
+	var resources []*mcp.Resource
+	var resourcesErr error
+	for r, err := range g.cs.Resources(ctx, &mcp.ListResourcesParams{}) {
+		if err != nil {
+			resourcesErr = fmt.Errorf("failed to list resources: %w", err)
+			break
+		}
+		resources = append(resources, r)
+	}
+
+	var templates []*mcp.ResourceTemplate
+	var templatesErr error
+	for rt, err := range g.cs.ResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{}) {
+		if err != nil {
+			templatesErr = fmt.Errorf("failed to list resource templates: %w", err)
+			break
+		}
+		templates = append(templates, rt)
+	}
+
+	seen := make(map[string]bool, len(resources))
+	for _, r := range resources {
+		seen[r.URI] = true
+		if diff(g.resources, r.URI, r, &added, &updated) {
+			g.server.AddResource(r, g.readResource)
+		}
+	}
+
+	if resourcesErr == nil {
+		staleResources := stale(g.resources, seen)
+		if len(staleResources) > 0 {
+			g.server.RemoveResources(staleResources...)
+		}
+		removed = append(removed, staleResources...)
+	}
+
+	seen = make(map[string]bool, len(templates))
+	for _, rt := range templates {
+		seen[rt.URITemplate] = true
+		if diff(g.templates, rt.URITemplate, rt, &added, &updated) {
+			g.server.AddResourceTemplate(rt, g.readResource)
+		}
+	}
+
+	if templatesErr == nil {
+		staleTemplates := stale(g.templates, seen)
+		if len(staleTemplates) > 0 {
+			g.server.RemoveResourceTemplates(staleTemplates...)
+		}
+		removed = append(removed, staleTemplates...)
+	}
+
+	return added, removed, updated, errors.Join(resourcesErr, templatesErr)
+}
+
+func (g *registry) readResource(ctx context.Context, rrr *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	defer g.trackProgress(rrr.Session, rrr.Params)()
+
+	start := time.Now()
+	res, err := g.cs.ReadResource(ctx, rrr.Params)
+	g.recorder.RecordResourceRead(rrr, res, err, start)
+	return res, err
+}
+
+// trackProgress remembers which agent session a request with a progress token came
+// from, so progress notifications from the server can be routed back. The returned
+// func forgets the token once the request is done. Notifications are handled
+// asynchronously and may be processed after the response, so the token is kept
+// for a grace period.
+func (g *registry) trackProgress(ss *mcp.ServerSession, params mcp.RequestParams) func() {
+	token := params.GetProgressToken()
+	if token == nil {
+		return func() {}
+	}
+
+	key := progressKey(token)
+	g.progress.Store(key, ss)
+	return func() {
+		time.AfterFunc(progressGracePeriod, func() { g.progress.CompareAndDelete(key, ss) })
+	}
+}
+
+// progressSession returns the agent session waiting for progress on token, if any
+func (g *registry) progressSession(token any) *mcp.ServerSession {
+	ss, ok := g.progress.Load(progressKey(token))
+	if !ok {
+		return nil
+	}
+
+	return ss.(*mcp.ServerSession)
+}
+
+// progressKey normalizes tokens, which may be strings or numbers and change type on the wire
+func progressKey(token any) string {
+	return fmt.Sprint(token)
+}
+
+// diff stores the serialized item under key and reports whether it is new or changed,
+// appending key to added or updated accordingly
+func diff(registered map[string][]byte, key string, item any, added, updated *[]string) bool {
+	data, err := json.Marshal(item)
+	if err != nil {
+		// an item that can't be compared is always re-registered
+		data = nil
+	}
+
+	prev, ok := registered[key]
+	registered[key] = data
+	switch {
+	case !ok:
+		*added = append(*added, key)
+	case data == nil || string(prev) != string(data):
+		*updated = append(*updated, key)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// stale deletes and returns the registered keys that were not seen, sorted
+func stale(registered map[string][]byte, seen map[string]bool) []string {
+	var keys []string
+	for key := range registered {
+		if !seen[key] {
+			keys = append(keys, key)
+			delete(registered, key)
+		}
+	}
+	slices.Sort(keys)
+
+	return keys
+}
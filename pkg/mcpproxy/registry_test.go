@@ -0,0 +1,270 @@
+package mcpproxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/mcpchecker/mcpchecker/pkg/toolfilter"
+	"github.com/mcpchecker/mcpchecker/pkg/tooloverride"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func textTool(name, description string) (*mcp.Tool, mcp.ToolHandler) {
+	return &mcp.Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: map[string]any{"type": "object"},
+	}, func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: name}}}, nil
+	}
+}
+
+func toolNames(t *testing.T, cs *mcp.ClientSession) []string {
+	t.Helper()
+
+	res, err := cs.ListTools(context.Background(), &mcp.ListToolsParams{})
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(res.Tools))
+	for _, tool := range res.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestRegistryToolListChanged(t *testing.T) {
+	upstream := mcp.NewServer(&mcp.Implementation{Name: "upstream", Version: "0.0.1"}, nil)
+	upstream.AddTool(textTool("list_pods", "List pods"))
+	upstream.AddTool(textTool("delete_pod", "Delete a pod"))
+
+	ts := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return upstream }, nil))
+	t.Cleanup(ts.Close)
+
+	srv, cfg := startProxyFor(t, &mcpclient.ServerConfig{Type: mcpclient.TransportTypeHttp, URL: ts.URL}, ProxyOptions{})
+
+	notified := make(chan struct{}, 10)
+	agent := connectAgent(t, cfg, &mcp.ClientOptions{
+		ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+			notified <- struct{}{}
+		},
+	})
+	assert.ElementsMatch(t, []string{"list_pods", "delete_pod"}, toolNames(t, agent))
+
+	upstream.AddTool(textTool("scale_deployment", "Scale a deployment"))
+	upstream.AddTool(textTool("list_pods", "List pods in a namespace"))
+	upstream.RemoveTools("delete_pod")
+
+	select {
+	case <-notified:
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent was not notified that the tool list changed")
+	}
+
+	require.Eventually(t, func() bool {
+		return assert.ObjectsAreEqual([]string{"list_pods", "scale_deployment"}, toolNames(t, agent))
+	}, 5*time.Second, 20*time.Millisecond)
+
+	res, err := agent.CallTool(context.Background(), &mcp.CallToolParams{Name: "scale_deployment"})
+	require.NoError(t, err)
+	assert.Equal(t, "scale_deployment", toolText(t, res))
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.ToolCalls, 1)
+	assert.Equal(t, "scale_deployment", history.ToolCalls[0].ToolName)
+
+	// the change is recorded once the proxy has been updated, which may be after the agent sees it
+	var added, removed, updated []string
+	require.Eventually(t, func() bool {
+		added, removed, updated = nil, nil, nil
+		for _, change := range srv.GetCallHistory().ListChanges {
+			assert.Equal(t, ListTools, change.List)
+			assert.True(t, change.Success)
+			added = append(added, change.Added...)
+			removed = append(removed, change.Removed...)
+			updated = append(updated, change.Updated...)
+		}
+		return len(removed) > 0
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, []string{"scale_deployment"}, added)
+	assert.Equal(t, []string{"delete_pod"}, removed)
+	assert.Equal(t, []string{"list_pods"}, updated)
+}
+
+func TestRegistryResourceListChanged(t *testing.T) {
+	upstream := mcp.NewServer(&mcp.Implementation{Name: "upstream", Version: "0.0.1"}, nil)
+	read := func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "data"}}}, nil
+	}
+	upstream.AddResource(&mcp.Resource{URI: "file:///config.yaml", Name: "config"}, read)
+
+	ts := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return upstream }, nil))
+	t.Cleanup(ts.Close)
+
+	srv, cfg := startProxyFor(t, &mcpclient.ServerConfig{Type: mcpclient.TransportTypeHttp, URL: ts.URL}, ProxyOptions{})
+	agent := connectAgent(t, cfg, nil)
+
+	upstream.AddResourceTemplate(&mcp.ResourceTemplate{URITemplate: "file:///logs/{name}", Name: "logs"}, read)
+	upstream.RemoveResources("file:///config.yaml")
+
+	require.Eventually(t, func() bool {
+		templates, err := agent.ListResourceTemplates(context.Background(), &mcp.ListResourceTemplatesParams{})
+		if err != nil || len(templates.ResourceTemplates) != 1 {
+			return false
+		}
+		resources, err := agent.ListResources(context.Background(), &mcp.ListResourcesParams{})
+		return err == nil && len(resources.Resources) == 0
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		return len(srv.GetCallHistory().ListChanges) > 0
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.Equal(t, ListResources, srv.GetCallHistory().ListChanges[0].List)
+}
+
+func TestRegistryHidesFilteredTools(t *testing.T) {
+	upstream := mcp.NewServer(&mcp.Implementation{Name: "upstream", Version: "0.0.1"}, nil)
+	upstream.AddTool(textTool("list_pods", "List pods"))
+	upstream.AddTool(textTool("delete_pod", "Delete a pod"))
+
+	ts := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return upstream }, nil))
+	t.Cleanup(ts.Close)
+
+	filter, err := toolfilter.New(&toolfilter.Config{Deny: []toolfilter.Rule{{Server: "upstream", ToolPattern: "^delete_"}}})
+	require.NoError(t, err)
+
+	srv, cfg := startProxyFor(t, &mcpclient.ServerConfig{Type: mcpclient.TransportTypeHttp, URL: ts.URL, EnableAllTools: true}, ProxyOptions{ToolFilter: filter})
+	agent := connectAgent(t, cfg, nil)
+	ctx := context.Background()
+
+	assert.Equal(t, []string{"list_pods"}, toolNames(t, agent))
+
+	allowed := srv.GetAllowedTools(ctx)
+	require.Len(t, allowed, 1)
+	assert.Equal(t, "list_pods", allowed[0].Name)
+
+	_, err = agent.CallTool(ctx, &mcp.CallToolParams{Name: "delete_pod"})
+	assert.Error(t, err, "hidden tools should not be callable")
+
+	upstream.AddTool(textTool("delete_namespace", "Delete a namespace"))
+	upstream.AddTool(textTool("list_namespaces", "List namespaces"))
+	require.Eventually(t, func() bool {
+		return len(toolNames(t, agent)) == 2
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.ElementsMatch(t, []string{"list_pods", "list_namespaces"}, toolNames(t, agent))
+}
+
+func TestRegistryAppliesToolOverrides(t *testing.T) {
+	upstream := mcp.NewServer(&mcp.Implementation{Name: "upstream", Version: "0.0.1"}, &mcp.ServerOptions{Instructions: "original instructions"})
+	upstream.AddTool(textTool("pods_list", "List pods"))
+	upstream.AddTool(textTool("pods_delete", "Delete a pod"))
+
+	ts := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return upstream }, nil))
+	t.Cleanup(ts.Close)
+
+	description := "List the pods of a namespace"
+	instructions := "Use these tools to inspect the cluster"
+	variant := &tooloverride.Variant{Name: "concise", Servers: map[string]*tooloverride.ServerOverride{
+		"upstream": {
+			Instructions: &instructions,
+			Tools: map[string]*tooloverride.ToolOverride{
+				"pods_list": {Name: "list_pods", Description: &description},
+			},
+		},
+	}}
+
+	srv, cfg := startProxyFor(t, &mcpclient.ServerConfig{Type: mcpclient.TransportTypeHttp, URL: ts.URL, EnableAllTools: true}, ProxyOptions{ToolOverrides: variant})
+	agent := connectAgent(t, cfg, nil)
+	ctx := context.Background()
+
+	assert.Equal(t, instructions, agent.InitializeResult().Instructions)
+	assert.Equal(t, instructions, srv.GetInstructions())
+
+	res, err := agent.ListTools(ctx, &mcp.ListToolsParams{})
+	require.NoError(t, err)
+	advertised := make(map[string]string, len(res.Tools))
+	for _, tool := range res.Tools {
+		advertised[tool.Name] = tool.Description
+	}
+	assert.Equal(t, map[string]string{"list_pods": description, "pods_delete": "Delete a pod"}, advertised)
+
+	allowed := srv.GetAllowedTools(ctx)
+	require.Len(t, allowed, 2)
+	assert.ElementsMatch(t, []string{"list_pods", "pods_delete"}, []string{allowed[0].Name, allowed[1].Name})
+
+	result, err := agent.CallTool(ctx, &mcp.CallToolParams{Name: "list_pods"})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+	assert.Equal(t, "pods_list", result.Content[0].(*mcp.TextContent).Text, "the call should reach the original tool")
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.ToolCalls, 1)
+	assert.Equal(t, "pods_list", history.ToolCalls[0].ToolName, "calls should be recorded under the original name")
+}
+
+func TestRegistryRecordsOutputSchemaViolations(t *testing.T) {
+	upstream := mcp.NewServer(&mcp.Implementation{Name: "upstream", Version: "0.0.1"}, nil)
+	countSchema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"count": map[string]any{"type": "integer"}},
+	}
+	for name, structured := range map[string]any{
+		"pods_count":  map[string]any{"count": 3},
+		"nodes_count": map[string]any{"count": "three"},
+	} {
+		upstream.AddTool(&mcp.Tool{Name: name, InputSchema: map[string]any{"type": "object"}, OutputSchema: countSchema},
+			func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return &mcp.CallToolResult{StructuredContent: structured}, nil
+			})
+	}
+
+	ts := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return upstream }, nil))
+	t.Cleanup(ts.Close)
+
+	srv, cfg := startProxyFor(t, &mcpclient.ServerConfig{Type: mcpclient.TransportTypeHttp, URL: ts.URL, EnableAllTools: true}, ProxyOptions{})
+	agent := connectAgent(t, cfg, nil)
+	ctx := context.Background()
+
+	_, err := agent.CallTool(ctx, &mcp.CallToolParams{Name: "pods_count"})
+	require.NoError(t, err)
+	_, err = agent.CallTool(ctx, &mcp.CallToolParams{Name: "nodes_count"})
+	require.NoError(t, err, "violations are recorded, not enforced")
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.ToolCalls, 2)
+	require.Len(t, history.OutputSchemaViolations, 1)
+	assert.Equal(t, "nodes_count", history.OutputSchemaViolations[0].ToolName)
+	assert.Contains(t, history.OutputSchemaViolations[0].Error, "does not match its outputSchema")
+}
+
+func TestRegistryKeepsResourcesWhenTemplatesFail(t *testing.T) {
+	upstream := mcp.NewServer(&mcp.Implementation{Name: "upstream", Version: "0.0.1"}, nil)
+	upstream.AddResource(&mcp.Resource{URI: "file:///config.yaml", Name: "config"}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "replicas: 1"}}}, nil
+	})
+	upstream.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "resources/templates/list" {
+				return nil, fmt.Errorf("templates are not supported")
+			}
+			return next(ctx, method, req)
+		}
+	})
+
+	ts := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return upstream }, nil))
+	t.Cleanup(ts.Close)
+
+	_, cfg := startProxyFor(t, &mcpclient.ServerConfig{Type: mcpclient.TransportTypeHttp, URL: ts.URL}, ProxyOptions{})
+	agent := connectAgent(t, cfg, nil)
+
+	res, err := agent.ListResources(context.Background(), &mcp.ListResourcesParams{})
+	require.NoError(t, err)
+	require.Len(t, res.Resources, 1)
+	assert.Equal(t, "file:///config.yaml", res.Resources[0].URI)
+}
@@ -2,15 +2,28 @@ package mcpproxy
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net"
 	"net/http"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/mcpchecker/mcpchecker/pkg/toolfilter"
+	"github.com/mcpchecker/mcpchecker/pkg/tooloverride"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+const (
+	streamablePath = "/mcp"
+	ssePath        = "/sse"
+
+	unsubscribeTimeout = 5 * time.Second
+)
+
 type Server interface {
 	// Run starts the proxy server
 	Run(ctx context.Context) error
@@ -26,6 +39,8 @@ type Server interface {
 	Close() error
 	// GetCallHistory returns all the MCP calls made while the proxy server was running
 	GetCallHistory() CallHistory
+	// ResetCallHistory drops the MCP calls recorded so far, e.g. those of a failed agent run
+	ResetCallHistory()
 	// WaitReady blocks until the server has initialized and is ready to serve
 	WaitReady(ctx context.Context) error
 }
@@ -34,8 +49,11 @@ type server struct {
 	name         string
 	proxyServer  *mcp.Server
 	proxyClient  *mcpclient.Client
-	url          string
+	subs         *subscriptions // nil if the server does not support subscriptions
+	baseURL      string
 	instructions string
+	toolFilter   *toolfilter.Filter
+	toolOverride *tooloverride.ServerOverride
 
 	// Call tracking
 	recorder Recorder
@@ -45,125 +63,140 @@ type server struct {
 	startErr error // Stores any error that occurred during startup
 
 	// Shutdown signalling
-	cancel context.CancelFunc
-	done   chan error
+	cancel    context.CancelFunc
+	done      chan error
+	closeOnce sync.Once
+	closeErr  error
 }
 
 var _ Server = &server{}
 
-func NewProxyServerForClient(ctx context.Context, name string, client *mcpclient.Client) (Server, error) {
+// NewProxyServerForClient creates a recording proxy for client. Server-initiated
+// requests are forwarded to the agent unless opts configures something to answer them.
+func NewProxyServerForClient(ctx context.Context, name string, client *mcpclient.Client, opts ProxyOptions) (Server, error) {
 	r := NewRecorder(name)
+	b := &requestBridge{
+		client:   client,
+		recorder: r,
+		sampler:  opts.Sampler,
+		elicitor: opts.Elicitor,
+	}
 
-	s, err := createProxyServer(ctx, client.ClientSession, r)
+	s, subs, err := createProxyServer(ctx, name, client.ClientSession, r, b, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create proxy server for %q: %w", name, err)
 	}
 
+	b.proxy = s
+	client.SetServerRequestHandler(b)
+
 	var instructions string
 	if initResult := client.ClientSession.InitializeResult(); initResult != nil {
 		instructions = initResult.Instructions
 	}
+	override := opts.ToolOverrides.Server(name)
 
 	return &server{
 		name:         name,
 		proxyServer:  s,
 		proxyClient:  client,
-		instructions: instructions,
+		subs:         subs,
+		instructions: override.ApplyInstructions(instructions),
+		toolFilter:   opts.ToolFilter,
+		toolOverride: override,
 		recorder:     r,
 		ready:        make(chan struct{}),
 		done:         make(chan error, 1),
 	}, nil
 }
 
-func createProxyServer(ctx context.Context, cs *mcp.ClientSession, r Recorder) (*mcp.Server, error) {
+func createProxyServer(ctx context.Context, name string, cs *mcp.ClientSession, r Recorder, b *requestBridge, proxyOpts ProxyOptions) (*mcp.Server, *subscriptions, error) {
 	serverCaps := cs.InitializeResult().Capabilities
+	var subs *subscriptions
 	opts := &mcp.ServerOptions{
-		Instructions: cs.InitializeResult().Instructions,
-		Capabilities: &mcp.ServerCapabilities{
-			Prompts:   serverCaps.Prompts,
-			Resources: serverCaps.Resources,
-			Tools:     serverCaps.Tools,
-		},
+		Instructions: proxyOpts.ToolOverrides.Server(name).ApplyInstructions(cs.InitializeResult().Instructions),
+		Capabilities: &mcp.ServerCapabilities{},
+	}
+	// The proxy mirrors list changes of the server, so it always advertises them
+	if serverCaps.Prompts != nil {
+		opts.Capabilities.Prompts = &mcp.PromptCapabilities{ListChanged: true}
+	}
+	if serverCaps.Resources != nil {
+		opts.Capabilities.Resources = &mcp.ResourceCapabilities{
+			Subscribe:   serverCaps.Resources.Subscribe,
+			ListChanged: true,
+		}
+		if serverCaps.Resources.Subscribe {
+			subs = newSubscriptions(cs, r)
+			opts.SubscribeHandler = subs.subscribe
+			opts.UnsubscribeHandler = subs.unsubscribe
+		}
+	}
+	if serverCaps.Tools != nil {
+		opts.Capabilities.Tools = &mcp.ToolCapabilities{ListChanged: true}
+	}
+	if serverCaps.Logging != nil {
+		opts.Capabilities.Logging = &mcp.LoggingCapabilities{}
+	}
+	if serverCaps.Completions != nil {
+		opts.CompletionHandler = func(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+			start := time.Now()
+			res, err := cs.Complete(ctx, req.Params)
+			r.RecordCompletion(req.Params, res, err, start)
+			return res, err
+		}
+	}
+	if b != nil {
+		// roots/list is forwarded to the agent, so only changes need to be propagated
+		opts.RootsListChangedHandler = func(ctx context.Context, req *mcp.RootsListChangedRequest) {
+			b.rootsChanged(ctx, req.Session)
+		}
 	}
 	s := mcp.NewServer(
 		cs.InitializeResult().ServerInfo,
 		opts,
 	)
+	s.AddReceivingMiddleware(protocolMiddleware(cs, r, serverCaps.Logging != nil))
 
+	// a server failing to list some items is still proxied with the items it did list,
+	// as agents would see the same server without the proxy
+	g := newRegistry(name, cs, s, r, proxyOpts)
 	if opts.Capabilities.Prompts != nil {
-		for p, err := range cs.Prompts(ctx, &mcp.ListPromptsParams{}) {
-			if err != nil {
-				continue
-			}
-			s.AddPrompt(p, func(ctx context.Context, gpr *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
-				start := time.Now()
-				res, err := cs.GetPrompt(ctx, gpr.Params)
-				r.RecordPromptGet(gpr, res, err, start)
-				return res, err
-			})
+		if _, _, _, err := g.syncPrompts(ctx); err != nil {
+			log.Printf("Warning: failed to sync prompts of %q: %v", name, err)
 		}
 	}
-
 	if opts.Capabilities.Resources != nil {
-		for rr, err := range cs.Resources(ctx, &mcp.ListResourcesParams{}) {
-			if err != nil {
-				continue
-			}
-			s.AddResource(rr, func(ctx context.Context, rrr *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
-				start := time.Now()
-				res, err := cs.ReadResource(ctx, rrr.Params)
-				r.RecordResourceRead(rrr, res, err, start)
-				return res, err
-			})
-		}
-
-		for rt, err := range cs.ResourceTemplates(ctx, &mcp.ListResourceTemplatesParams{}) {
-			if err != nil {
-				continue
-			}
-			s.AddResourceTemplate(rt, func(ctx context.Context, rrr *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
-				start := time.Now()
-				res, err := cs.ReadResource(ctx, rrr.Params)
-				r.RecordResourceRead(rrr, res, err, start)
-				return res, err
-			})
+		if _, _, _, err := g.syncResources(ctx); err != nil {
+			log.Printf("Warning: failed to sync resources of %q: %v", name, err)
 		}
 	}
-
 	if opts.Capabilities.Tools != nil {
-		for t, err := range cs.Tools(ctx, &mcp.ListToolsParams{}) {
-			if err != nil {
-				continue
-			}
-			s.AddTool(t, func(ctx context.Context, ctr *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-				start := time.Now()
-				res, err := cs.CallTool(ctx, &mcp.CallToolParams{
-					Meta:      ctr.Params.Meta,
-					Name:      ctr.Params.Name,
-					Arguments: ctr.Params.Arguments,
-				})
-				r.RecordToolCall(ctr, res, err, start)
-				return res, err
-			})
+		if _, _, _, err := g.syncTools(ctx); err != nil {
+			log.Printf("Warning: failed to sync tools of %q: %v", name, err)
 		}
 	}
+	if b != nil {
+		b.registry = g
+	}
 
-	return s, nil
+	return s, subs, nil
 }
 
 // Run is a blocking call until ctx is cancelled or Close is called
-// Run will start the server in streamablehttp transport
-// TODO(Cali0707): update this to support other transports
+// Run will start the server with both the streamablehttp (/mcp) and sse (/sse) transports,
+// stdio is served by the proxy shim which relays to the sse endpoint
 func (s *server) Run(ctx context.Context) error {
 	ctx, s.cancel = context.WithCancel(ctx)
 
 	mux := http.NewServeMux()
 
-	handler := mcp.NewStreamableHTTPHandler(func(r *http.Request) *mcp.Server {
+	getServer := func(r *http.Request) *mcp.Server {
 		return s.proxyServer
-	}, &mcp.StreamableHTTPOptions{})
+	}
 
-	mux.Handle("/mcp", handler)
+	mux.Handle(streamablePath, mcp.NewStreamableHTTPHandler(getServer, &mcp.StreamableHTTPOptions{}))
+	mux.Handle(ssePath, mcp.NewSSEHandler(getServer, nil))
 
 	listener, err := net.Listen("tcp", "localhost:0")
 	if err != nil {
@@ -173,7 +206,7 @@ func (s *server) Run(ctx context.Context) error {
 		return s.startErr
 	}
 
-	s.url = fmt.Sprintf("http://%s/mcp", listener.Addr().String())
+	s.baseURL = fmt.Sprintf("http://%s", listener.Addr().String())
 
 	// Signal that the server is ready (URL is set and listener is ready)
 	close(s.ready)
@@ -207,21 +240,44 @@ func (s *server) Run(ctx context.Context) error {
 }
 
 func (s *server) GetConfig() (*mcpclient.ServerConfig, error) {
-	if s.url == "" {
+	if s.baseURL == "" {
 		return nil, fmt.Errorf("url must be set for config to be valid, ensure Run() is called before GetConfig()")
 	}
 
-	cfg := &mcpclient.ServerConfig{
-		Type: mcpclient.TransportTypeHttp,
-		URL:  s.url,
-	}
-
+	var headers map[string]string
+	var transport string
 	clientCfg := s.proxyClient.GetConfig()
 	if clientCfg != nil {
-		cfg.Headers = clientCfg.Headers
+		headers = clientCfg.Headers
+		transport = clientCfg.ProxyTransport
 	}
 
-	return cfg, nil
+	switch transport {
+	case mcpclient.TransportTypeSse:
+		return &mcpclient.ServerConfig{
+			Type:    mcpclient.TransportTypeSse,
+			URL:     s.baseURL + ssePath,
+			Headers: headers,
+		}, nil
+	case mcpclient.TransportTypeStdio:
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("failed to find executable for the stdio proxy shim: %w", err)
+		}
+
+		return &mcpclient.ServerConfig{
+			Type:    mcpclient.TransportTypeStdio,
+			Command: exe,
+			Args:    ShimArgs(s.baseURL + ssePath),
+			Env:     ShimEnv(headers),
+		}, nil
+	default:
+		return &mcpclient.ServerConfig{
+			Type:    mcpclient.TransportTypeHttp,
+			URL:     s.baseURL + streamablePath,
+			Headers: headers,
+		}, nil
+	}
 }
 
 func (s *server) GetName() string {
@@ -229,7 +285,18 @@ func (s *server) GetName() string {
 }
 
 func (s *server) GetAllowedTools(ctx context.Context) []*mcp.Tool {
-	return s.proxyClient.GetAllowedTools(ctx)
+	tools := s.proxyClient.GetAllowedTools(ctx)
+	if s.toolFilter != nil {
+		allowed := make([]*mcp.Tool, 0, len(tools))
+		for _, t := range tools {
+			if s.toolFilter.Allows(s.name, t.Name) {
+				allowed = append(allowed, t)
+			}
+		}
+		tools = allowed
+	}
+
+	return s.toolOverride.ApplyTools(tools)
 }
 
 func (s *server) GetInstructions() string {
@@ -237,18 +304,35 @@ func (s *server) GetInstructions() string {
 }
 
 func (s *server) Close() error {
+	s.closeOnce.Do(func() {
+		s.closeErr = s.close()
+	})
+	return s.closeErr
+}
+
+func (s *server) close() error {
+	s.proxyClient.SetServerRequestHandler(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
+	defer cancel()
+	subsErr := s.subs.close(ctx)
+
 	if s.cancel == nil {
-		return nil
+		return subsErr
 	}
 
 	s.cancel()
-	return <-s.done
+	return errors.Join(subsErr, <-s.done)
 }
 
 func (s *server) GetCallHistory() CallHistory {
 	return s.recorder.GetHistory()
 }
 
+func (s *server) ResetCallHistory() {
+	s.recorder.Reset()
+}
+
 func (s *server) WaitReady(ctx context.Context) error {
 	select {
 	case <-s.ready:
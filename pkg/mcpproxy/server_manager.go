@@ -12,6 +12,8 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/mcpchecker/mcpchecker/pkg/toolfilter"
+	"github.com/mcpchecker/mcpchecker/pkg/tooloverride"
 )
 
 const (
@@ -29,6 +31,8 @@ type ServerManager interface {
 	// aggregate call tracking
 	GetAllCallHistory() *CallHistory
 	GetCallHistoryForServer(serverName string) (CallHistory, bool)
+	// ResetCallHistory drops the MCP calls recorded so far by all servers
+	ResetCallHistory()
 }
 
 type serverManager struct {
@@ -39,11 +43,23 @@ type serverManager struct {
 	eg     *errgroup.Group
 }
 
-func NewServerManager(ctx context.Context, manager mcpclient.Manager) (ServerManager, error) {
+// ProxyOptions configures how proxy servers answer requests initiated by the MCP servers
+type ProxyOptions struct {
+	// Sampler answers sampling requests from servers when the agent does not support sampling
+	Sampler Sampler
+	// Elicitor answers elicitation requests from servers instead of the agent
+	Elicitor Elicitor
+	// ToolFilter hides tools from the agent, hidden tools are neither listed nor callable
+	ToolFilter *toolfilter.Filter
+	// ToolOverrides rewrites the tools and instructions of the servers as the agent sees them
+	ToolOverrides *tooloverride.Variant
+}
+
+func NewServerManager(ctx context.Context, manager mcpclient.Manager, proxyOpts ProxyOptions) (ServerManager, error) {
 	clients := manager.GetAll()
 	servers := make(map[string]Server, len(clients))
 	for name, client := range clients {
-		s, err := NewProxyServerForClient(ctx, name, client)
+		s, err := NewProxyServerForClient(ctx, name, client, proxyOpts)
 		if err != nil {
 			return nil, err
 		}
@@ -155,6 +171,18 @@ func (m *serverManager) GetAllCallHistory() *CallHistory {
 		combined.PromptGets = append(combined.PromptGets, history.PromptGets...)
 		combined.ResourceReads = append(combined.ResourceReads, history.ResourceReads...)
 		combined.ToolCalls = append(combined.ToolCalls, history.ToolCalls...)
+		combined.SamplingRequests = append(combined.SamplingRequests, history.SamplingRequests...)
+		combined.Elicitations = append(combined.Elicitations, history.Elicitations...)
+		combined.RootsLists = append(combined.RootsLists, history.RootsLists...)
+		combined.ListChanges = append(combined.ListChanges, history.ListChanges...)
+		combined.ListCalls = append(combined.ListCalls, history.ListCalls...)
+		combined.Completions = append(combined.Completions, history.Completions...)
+		combined.Progress = append(combined.Progress, history.Progress...)
+		combined.LogMessages = append(combined.LogMessages, history.LogMessages...)
+		combined.Cancellations = append(combined.Cancellations, history.Cancellations...)
+		combined.Subscriptions = append(combined.Subscriptions, history.Subscriptions...)
+		combined.ResourceUpdates = append(combined.ResourceUpdates, history.ResourceUpdates...)
+		combined.OutputSchemaViolations = append(combined.OutputSchemaViolations, history.OutputSchemaViolations...)
 	}
 
 	// sort all by timestamp for chronological order
@@ -167,6 +195,42 @@ func (m *serverManager) GetAllCallHistory() *CallHistory {
 	sort.Slice(combined.PromptGets, func(i, j int) bool {
 		return combined.PromptGets[i].Timestamp.Before(combined.PromptGets[j].Timestamp)
 	})
+	sort.Slice(combined.SamplingRequests, func(i, j int) bool {
+		return combined.SamplingRequests[i].Timestamp.Before(combined.SamplingRequests[j].Timestamp)
+	})
+	sort.Slice(combined.Elicitations, func(i, j int) bool {
+		return combined.Elicitations[i].Timestamp.Before(combined.Elicitations[j].Timestamp)
+	})
+	sort.Slice(combined.RootsLists, func(i, j int) bool {
+		return combined.RootsLists[i].Timestamp.Before(combined.RootsLists[j].Timestamp)
+	})
+	sort.Slice(combined.ListChanges, func(i, j int) bool {
+		return combined.ListChanges[i].Timestamp.Before(combined.ListChanges[j].Timestamp)
+	})
+	sort.Slice(combined.ListCalls, func(i, j int) bool {
+		return combined.ListCalls[i].Timestamp.Before(combined.ListCalls[j].Timestamp)
+	})
+	sort.Slice(combined.Completions, func(i, j int) bool {
+		return combined.Completions[i].Timestamp.Before(combined.Completions[j].Timestamp)
+	})
+	sort.Slice(combined.Progress, func(i, j int) bool {
+		return combined.Progress[i].Timestamp.Before(combined.Progress[j].Timestamp)
+	})
+	sort.Slice(combined.LogMessages, func(i, j int) bool {
+		return combined.LogMessages[i].Timestamp.Before(combined.LogMessages[j].Timestamp)
+	})
+	sort.Slice(combined.Cancellations, func(i, j int) bool {
+		return combined.Cancellations[i].Timestamp.Before(combined.Cancellations[j].Timestamp)
+	})
+	sort.Slice(combined.Subscriptions, func(i, j int) bool {
+		return combined.Subscriptions[i].Timestamp.Before(combined.Subscriptions[j].Timestamp)
+	})
+	sort.Slice(combined.ResourceUpdates, func(i, j int) bool {
+		return combined.ResourceUpdates[i].Timestamp.Before(combined.ResourceUpdates[j].Timestamp)
+	})
+	sort.Slice(combined.OutputSchemaViolations, func(i, j int) bool {
+		return combined.OutputSchemaViolations[i].Timestamp.Before(combined.OutputSchemaViolations[j].Timestamp)
+	})
 
 	return &combined
 }
@@ -180,6 +244,12 @@ func (m *serverManager) GetCallHistoryForServer(serverName string) (CallHistory,
 	return srv.GetCallHistory(), true
 }
 
+func (m *serverManager) ResetCallHistory() {
+	for _, srv := range m.servers {
+		srv.ResetCallHistory()
+	}
+}
+
 func (m *serverManager) getMcpServers() (*mcpclient.MCPConfig, error) {
 	cfg := &mcpclient.MCPConfig{
 		MCPServers: make(map[string]*mcpclient.ServerConfig),
@@ -0,0 +1,106 @@
+package mcpproxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// ShimCommand is the hidden mcpchecker subcommand agents launch to reach a proxy over stdio
+	ShimCommand = "proxy-shim"
+	// ShimHeadersEnv is the environment variable holding the headers the shim sends to the proxy
+	ShimHeadersEnv = "MCPCHECKER_SHIM_HEADERS"
+)
+
+// ShimArgs returns the mcpchecker arguments that start a stdio shim relaying to the sse endpoint at url
+func ShimArgs(url string) []string {
+	return []string{ShimCommand, "--url", url}
+}
+
+// ShimEnv returns the environment that passes headers to the shim. Headers often hold
+// credentials, so they are kept out of the shim's arguments, which any user can see.
+func ShimEnv(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	// a map of strings always marshals
+	data, _ := json.Marshal(headers)
+	return map[string]string{ShimHeadersEnv: string(data)}
+}
+
+// ParseShimHeaders parses the headers given to the shim in ShimHeadersEnv, a JSON object
+// of header names to values
+func ParseShimHeaders(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(value), &headers); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ShimHeadersEnv, err)
+	}
+
+	return headers, nil
+}
+
+// RunShim relays MCP messages between local (usually stdio) and the sse endpoint of a
+// recording proxy at url. Messages are copied as-is, so the agent's session is with the
+// proxy itself. RunShim returns when either side closes.
+func RunShim(ctx context.Context, url string, headers map[string]string, local mcp.Transport) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	hdrs := make(http.Header, len(headers))
+	for k, v := range headers {
+		hdrs.Set(k, v)
+	}
+
+	remote := &mcp.SSEClientTransport{
+		Endpoint:   url,
+		HTTPClient: &http.Client{Transport: mcpclient.NewHeaderRoundTripper(hdrs, nil)},
+	}
+
+	remoteConn, err := remote.Connect(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to proxy at %s: %w", url, err)
+	}
+	defer func() { _ = remoteConn.Close() }()
+
+	localConn, err := local.Connect(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open local transport: %w", err)
+	}
+	defer func() { _ = localConn.Close() }()
+
+	errs := make(chan error, 2)
+	go func() { errs <- relay(ctx, localConn, remoteConn) }()
+	go func() { errs <- relay(ctx, remoteConn, localConn) }()
+
+	err = <-errs
+	if errors.Is(err, io.EOF) || errors.Is(err, context.Canceled) {
+		return nil
+	}
+
+	return err
+}
+
+func relay(ctx context.Context, from, to mcp.Connection) error {
+	for {
+		msg, err := from.Read(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := to.Write(ctx, msg); err != nil {
+			return err
+		}
+	}
+}
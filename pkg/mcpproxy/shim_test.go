@@ -0,0 +1,118 @@
+package mcpproxy
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyTransportConfig(t *testing.T) {
+	tests := map[string]struct {
+		transport    string
+		expectType   string
+		expectSuffix string
+	}{
+		"default is streamable http": {
+			expectType:   mcpclient.TransportTypeHttp,
+			expectSuffix: streamablePath,
+		},
+		"sse": {
+			transport:    mcpclient.TransportTypeSse,
+			expectType:   mcpclient.TransportTypeSse,
+			expectSuffix: ssePath,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			upstream := newUpstreamServer(t)
+			upstream.ProxyTransport = tc.transport
+			srv, cfg := startProxyFor(t, upstream, ProxyOptions{})
+
+			assert.Equal(t, tc.expectType, cfg.Type)
+			assert.Regexp(t, "^http://.*"+tc.expectSuffix+"$", cfg.URL)
+
+			// the agent-facing config must be usable by a client
+			client, err := mcpclient.Connect(context.Background(), cfg)
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = client.Close() })
+
+			_, err = client.CallTool(context.Background(), &mcp.CallToolParams{Name: "roots"})
+			require.NoError(t, err)
+			assert.Len(t, srv.GetCallHistory().ToolCalls, 1)
+		})
+	}
+}
+
+func TestProxyTransportStdioConfig(t *testing.T) {
+	upstream := newUpstreamServer(t)
+	upstream.ProxyTransport = mcpclient.TransportTypeStdio
+	upstream.Headers = map[string]string{"X-Token": "abc"}
+	_, cfg := startProxyFor(t, upstream, ProxyOptions{})
+
+	assert.Equal(t, mcpclient.TransportTypeStdio, cfg.Type)
+	assert.NotEmpty(t, cfg.Command)
+	require.Len(t, cfg.Args, 3)
+	assert.Equal(t, ShimCommand, cfg.Args[0])
+	assert.Equal(t, "--url", cfg.Args[1])
+	assert.Regexp(t, "^http://.*"+ssePath+"$", cfg.Args[2])
+	assert.Equal(t, map[string]string{ShimHeadersEnv: `{"X-Token":"abc"}`}, cfg.Env)
+}
+
+func TestRunShim(t *testing.T) {
+	srv, _ := startProxy(t, ProxyOptions{})
+	sseURL := srv.(*server).baseURL + ssePath
+
+	// wire the agent to the shim through pipes, standing in for the shim's stdio
+	agentToShim, shimIn := io.Pipe()
+	shimOut, shimToAgent := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- RunShim(context.Background(), sseURL, nil, &mcp.IOTransport{Reader: agentToShim, Writer: shimToAgent})
+	}()
+
+	agent := mcp.NewClient(&mcp.Implementation{Name: "agent", Version: "0.0.1"}, &mcp.ClientOptions{
+		CreateMessageHandler: func(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			return &mcp.CreateMessageResult{Content: &mcp.TextContent{Text: "via shim"}, Model: "agent", Role: "assistant"}, nil
+		},
+	})
+	cs, err := agent.Connect(context.Background(), &mcp.IOTransport{Reader: shimOut, Writer: shimIn}, nil)
+	require.NoError(t, err)
+
+	// sampling exercises server-initiated requests flowing back through the shim
+	res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{Name: "summarize"})
+	require.NoError(t, err)
+	assert.Equal(t, "via shim", toolText(t, res))
+
+	require.NoError(t, cs.Close())
+	require.NoError(t, <-done)
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.ToolCalls, 1)
+	require.Len(t, history.SamplingRequests, 1)
+	assert.Equal(t, HandlerAgent, history.SamplingRequests[0].Handler)
+}
+
+func TestShimHeadersRoundTrip(t *testing.T) {
+	headers := map[string]string{"Authorization": "Bearer a=b", "X-Trace": "1"}
+	env := ShimEnv(headers)
+	require.Contains(t, env, ShimHeadersEnv)
+
+	parsed, err := ParseShimHeaders(env[ShimHeadersEnv])
+	require.NoError(t, err)
+	assert.Equal(t, headers, parsed)
+
+	assert.Nil(t, ShimEnv(nil))
+	parsed, err = ParseShimHeaders("")
+	require.NoError(t, err)
+	assert.Empty(t, parsed)
+
+	_, err = ParseShimHeaders("Authorization=Bearer")
+	assert.Error(t, err)
+}
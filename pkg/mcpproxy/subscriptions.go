@@ -0,0 +1,103 @@
+package mcpproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// subscriptions passes resource subscriptions of agent sessions through to the MCP
+// server. The server sees a single subscription per URI, which is held while any
+// agent session is subscribed, the proxy server fans updates out to the sessions.
+type subscriptions struct {
+	cs       *mcp.ClientSession
+	recorder Recorder
+
+	mu       sync.Mutex
+	sessions map[string]map[*mcp.ServerSession]bool
+}
+
+func newSubscriptions(cs *mcp.ClientSession, r Recorder) *subscriptions {
+	return &subscriptions{
+		cs:       cs,
+		recorder: r,
+		sessions: make(map[string]map[*mcp.ServerSession]bool),
+	}
+}
+
+func (s *subscriptions) subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	start := time.Now()
+	err := s.add(ctx, req.Session, req.Params.URI)
+	s.recorder.RecordSubscription(req.Params.URI, false, err, start)
+	return err
+}
+
+func (s *subscriptions) unsubscribe(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+	start := time.Now()
+	err := s.remove(ctx, req.Session, req.Params.URI)
+	s.recorder.RecordSubscription(req.Params.URI, true, err, start)
+	return err
+}
+
+func (s *subscriptions) add(ctx context.Context, ss *mcp.ServerSession, uri string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.sessions[uri]) == 0 {
+		if err := s.cs.Subscribe(ctx, &mcp.SubscribeParams{URI: uri}); err != nil {
+			return err
+		}
+		s.sessions[uri] = make(map[*mcp.ServerSession]bool)
+	}
+	s.sessions[uri][ss] = true
+
+	return nil
+}
+
+func (s *subscriptions) remove(ctx context.Context, ss *mcp.ServerSession, uri string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscribed := s.sessions[uri]
+	if !subscribed[ss] {
+		return nil
+	}
+
+	if len(subscribed) == 1 {
+		if err := s.cs.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: uri}); err != nil {
+			return err
+		}
+		delete(s.sessions, uri)
+		return nil
+	}
+	delete(subscribed, ss)
+
+	return nil
+}
+
+// close drops the server subscriptions that agent sessions still hold, so that they do
+// not outlive the proxy on a shared client
+func (s *subscriptions) close(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	for _, uri := range slices.Sorted(maps.Keys(s.sessions)) {
+		if unsubErr := s.cs.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: uri}); unsubErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to unsubscribe from %s: %w", uri, unsubErr))
+		}
+		delete(s.sessions, uri)
+	}
+
+	return err
+}
@@ -0,0 +1,129 @@
+package mcpproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const logsURI = "file:///var/log/app.log"
+
+// subscribableServer is an MCP server with a resource clients can subscribe to
+type subscribableServer struct {
+	*mcp.Server
+
+	mu         sync.Mutex
+	subscribed map[string]int
+}
+
+func newSubscribableServer(t *testing.T) (*subscribableServer, *mcpclient.ServerConfig) {
+	t.Helper()
+
+	upstream := &subscribableServer{subscribed: make(map[string]int)}
+	upstream.Server = mcp.NewServer(&mcp.Implementation{Name: "upstream", Version: "0.0.1"}, &mcp.ServerOptions{
+		SubscribeHandler: func(ctx context.Context, req *mcp.SubscribeRequest) error {
+			upstream.mu.Lock()
+			defer upstream.mu.Unlock()
+			upstream.subscribed[req.Params.URI]++
+			return nil
+		},
+		UnsubscribeHandler: func(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+			upstream.mu.Lock()
+			defer upstream.mu.Unlock()
+			upstream.subscribed[req.Params.URI]--
+			return nil
+		},
+	})
+	upstream.AddResource(&mcp.Resource{URI: logsURI, Name: "logs"}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{URI: req.Params.URI, Text: "started"}}}, nil
+	})
+
+	ts := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return upstream.Server }, nil))
+	t.Cleanup(ts.Close)
+
+	return upstream, &mcpclient.ServerConfig{Type: mcpclient.TransportTypeHttp, URL: ts.URL}
+}
+
+func (s *subscribableServer) subscriptions(uri string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.subscribed[uri]
+}
+
+func TestProxyResourceSubscriptions(t *testing.T) {
+	upstream, upstreamCfg := newSubscribableServer(t)
+	srv, cfg := startProxyFor(t, upstreamCfg, ProxyOptions{})
+	ctx := context.Background()
+
+	updates := make(chan string, 1)
+	agent := connectAgent(t, cfg, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(ctx context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			updates <- req.Params.URI
+		},
+	})
+	other := connectAgent(t, cfg, nil)
+
+	init := agent.InitializeResult()
+	require.NotNil(t, init.Capabilities.Resources)
+	assert.True(t, init.Capabilities.Resources.Subscribe)
+
+	require.NoError(t, agent.Subscribe(ctx, &mcp.SubscribeParams{URI: logsURI}))
+	require.NoError(t, other.Subscribe(ctx, &mcp.SubscribeParams{URI: logsURI}))
+	assert.Equal(t, 1, upstream.subscriptions(logsURI), "the server should see a single subscription")
+
+	require.NoError(t, upstream.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: logsURI}))
+	select {
+	case uri := <-updates:
+		assert.Equal(t, logsURI, uri)
+	case <-time.After(5 * time.Second):
+		t.Fatal("agent did not receive the resource update")
+	}
+
+	require.NoError(t, other.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: logsURI}))
+	assert.Equal(t, 1, upstream.subscriptions(logsURI), "the server subscription is held while an agent is subscribed")
+	require.NoError(t, agent.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: logsURI}))
+	assert.Equal(t, 0, upstream.subscriptions(logsURI))
+
+	history := srv.GetCallHistory()
+	require.Len(t, history.Subscriptions, 4)
+	assert.Equal(t, logsURI, history.Subscriptions[0].URI)
+	assert.False(t, history.Subscriptions[0].Unsubscribe)
+	assert.True(t, history.Subscriptions[3].Unsubscribe)
+	require.Len(t, history.ResourceUpdates, 1)
+	assert.Equal(t, logsURI, history.ResourceUpdates[0].URI)
+}
+
+func TestProxyWithoutSubscriptionSupport(t *testing.T) {
+	_, cfg := startProxyFor(t, newProtocolServer(t, make(chan struct{}, 1)), ProxyOptions{})
+	agent := connectAgent(t, cfg, nil)
+
+	init := agent.InitializeResult()
+	require.NotNil(t, init.Capabilities.Resources)
+	assert.False(t, init.Capabilities.Resources.Subscribe)
+
+	err := agent.Subscribe(context.Background(), &mcp.SubscribeParams{URI: "file:///config.yaml"})
+	assert.Error(t, err)
+}
+
+func TestProxyCloseReleasesSubscriptions(t *testing.T) {
+	upstream, upstreamCfg := newSubscribableServer(t)
+	srv, cfg := startProxyFor(t, upstreamCfg, ProxyOptions{})
+	agent := connectAgent(t, cfg, nil)
+
+	require.NoError(t, agent.Subscribe(context.Background(), &mcp.SubscribeParams{URI: logsURI}))
+	assert.Equal(t, 1, upstream.subscriptions(logsURI))
+
+	// the client may be shared with the next run, which must not inherit the subscription
+	// of an agent that went away without unsubscribing
+	require.NoError(t, agent.Close())
+	require.NoError(t, srv.Close())
+	assert.Equal(t, 0, upstream.subscriptions(logsURI))
+}
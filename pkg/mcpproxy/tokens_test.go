@@ -26,6 +26,7 @@ func (s *testServer) GetAllowedTools(_ context.Context) []*mcp.Tool { return s.a
 func (s *testServer) GetInstructions() string                       { return s.instructions }
 func (s *testServer) Close() error                                  { return nil }
 func (s *testServer) GetCallHistory() CallHistory                   { return CallHistory{} }
+func (s *testServer) ResetCallHistory()                             {}
 func (s *testServer) WaitReady(_ context.Context) error             { return nil }
 
 func TestComputeCallHistoryTokens_NilHistory(t *testing.T) {
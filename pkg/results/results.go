@@ -23,6 +23,7 @@ type Stats struct {
 	TotalTokens       int64   `json:"totalTokens"`
 	McpSchemaTokens   int64   `json:"mcpSchemaTokens"`
 	TasksWithTokens   int     `json:"tasksWithTokens"` // number of tasks that have token data
+	InfraFailures     int     `json:"infraFailures"`   // number of tasks that failed with an infra failure
 }
 
 // Load reads a JSON results file and returns the parsed evaluations.
@@ -105,6 +106,10 @@ func CalculateStats(resultsFile string, results []*eval.EvalResult) Stats {
 			stats.TasksPassed++
 		}
 
+		if result.FailureKind == eval.FailureInfra {
+			stats.InfraFailures++
+		}
+
 		if result.AssertionResults != nil {
 			stats.AssertionsTotal += result.AssertionResults.TotalAssertions()
 			stats.AssertionsPassed += result.AssertionResults.PassedAssertions()
@@ -128,6 +133,58 @@ func CalculateStats(resultsFile string, results []*eval.EvalResult) Stats {
 	return stats
 }
 
+// ExcludeInfraFailures returns the results without the tasks that failed with an infra
+// failure, e.g. a rate limited model API, and the number of results it excluded.
+func ExcludeInfraFailures(results []*eval.EvalResult) ([]*eval.EvalResult, int) {
+	filtered := make([]*eval.EvalResult, 0, len(results))
+	for _, r := range results {
+		if r.FailureKind != eval.FailureInfra {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered, len(results) - len(filtered)
+}
+
+// VariantStats holds the statistics of the results of a single toolOverrides variant.
+type VariantStats struct {
+	Variant string `json:"variant"`
+	Stats
+}
+
+// CalculateVariantStats computes statistics per toolOverrides variant, in the order the
+// variants first appear. It returns nil if no result ran with a variant.
+func CalculateVariantStats(resultsFile string, results []*eval.EvalResult) []VariantStats {
+	var order []string
+	byVariant := make(map[string][]*eval.EvalResult)
+	for _, r := range results {
+		if r.Variant == "" {
+			continue
+		}
+		if _, ok := byVariant[r.Variant]; !ok {
+			order = append(order, r.Variant)
+		}
+		byVariant[r.Variant] = append(byVariant[r.Variant], r)
+	}
+
+	var stats []VariantStats
+	for _, variant := range order {
+		stats = append(stats, VariantStats{
+			Variant: variant,
+			Stats:   CalculateStats(resultsFile, byVariant[variant]),
+		})
+	}
+
+	return stats
+}
+
+// TaskLabel returns the task name, followed by the toolOverrides variant the task ran with.
+func TaskLabel(r *eval.EvalResult) string {
+	if r.Variant == "" {
+		return r.TaskName
+	}
+	return fmt.Sprintf("%s [%s]", r.TaskName, r.Variant)
+}
+
 // PassedAssertions returns the number of passed assertions for a result.
 func PassedAssertions(r *eval.EvalResult) int {
 	if r.AssertionResults == nil {
@@ -186,6 +243,48 @@ func FailureReason(r *eval.EvalResult) string {
 	if a.NoDuplicateCalls != nil && !a.NoDuplicateCalls.Passed {
 		return a.NoDuplicateCalls.Reason
 	}
+	if a.SamplingUsed != nil && !a.SamplingUsed.Passed {
+		return a.SamplingUsed.Reason
+	}
+	if a.SamplingNotUsed != nil && !a.SamplingNotUsed.Passed {
+		return a.SamplingNotUsed.Reason
+	}
+	if a.ElicitationUsed != nil && !a.ElicitationUsed.Passed {
+		return a.ElicitationUsed.Reason
+	}
+	if a.ElicitationNotUsed != nil && !a.ElicitationNotUsed.Passed {
+		return a.ElicitationNotUsed.Reason
+	}
+	if a.RootsListed != nil && !a.RootsListed.Passed {
+		return a.RootsListed.Reason
+	}
+	if a.ListsCalled != nil && !a.ListsCalled.Passed {
+		return a.ListsCalled.Reason
+	}
+	if a.ListedBeforeUse != nil && !a.ListedBeforeUse.Passed {
+		return a.ListedBeforeUse.Reason
+	}
+	if a.ResourcesSubscribed != nil && !a.ResourcesSubscribed.Passed {
+		return a.ResourcesSubscribed.Reason
+	}
+	if a.ResourcesNotSubscribed != nil && !a.ResourcesNotSubscribed.Passed {
+		return a.ResourcesNotSubscribed.Reason
+	}
+	if a.ToolOutputSchemaValid != nil && !a.ToolOutputSchemaValid.Passed {
+		return a.ToolOutputSchemaValid.Reason
+	}
+	if a.CommandsRun != nil && !a.CommandsRun.Passed {
+		return a.CommandsRun.Reason
+	}
+	if a.CommandsNotRun != nil && !a.CommandsNotRun.Passed {
+		return a.CommandsNotRun.Reason
+	}
+	if a.NoDeniedPermissions != nil && !a.NoDeniedPermissions.Passed {
+		return a.NoDeniedPermissions.Reason
+	}
+	if a.PermissionsNotRequested != nil && !a.PermissionsNotRequested.Passed {
+		return a.PermissionsNotRequested.Reason
+	}
 	return ""
 }
 
@@ -210,6 +309,20 @@ func CollectFailedAssertions(results *eval.CompositeAssertionResult) []string {
 	addFailure("PromptsNotUsed", results.PromptsNotUsed)
 	addFailure("CallOrder", results.CallOrder)
 	addFailure("NoDuplicateCalls", results.NoDuplicateCalls)
+	addFailure("SamplingUsed", results.SamplingUsed)
+	addFailure("SamplingNotUsed", results.SamplingNotUsed)
+	addFailure("ElicitationUsed", results.ElicitationUsed)
+	addFailure("ElicitationNotUsed", results.ElicitationNotUsed)
+	addFailure("RootsListed", results.RootsListed)
+	addFailure("ListsCalled", results.ListsCalled)
+	addFailure("ListedBeforeUse", results.ListedBeforeUse)
+	addFailure("ResourcesSubscribed", results.ResourcesSubscribed)
+	addFailure("ResourcesNotSubscribed", results.ResourcesNotSubscribed)
+	addFailure("ToolOutputSchemaValid", results.ToolOutputSchemaValid)
+	addFailure("CommandsRun", results.CommandsRun)
+	addFailure("CommandsNotRun", results.CommandsNotRun)
+	addFailure("NoDeniedPermissions", results.NoDeniedPermissions)
+	addFailure("PermissionsNotRequested", results.PermissionsNotRequested)
 
 	return failures
 }
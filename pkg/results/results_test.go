@@ -115,6 +115,42 @@ func TestCalculateStatsEmptyResults(t *testing.T) {
 	}
 }
 
+func TestCalculateVariantStats(t *testing.T) {
+	if stats := CalculateVariantStats("test.json", sampleResults()); stats != nil {
+		t.Errorf("CalculateVariantStats() = %v, want nil without variants", stats)
+	}
+
+	evalResults := []*eval.EvalResult{
+		{TaskName: "task-1", Variant: "baseline", TaskPassed: true},
+		{TaskName: "task-1", Variant: "concise", TaskPassed: false},
+		{TaskName: "task-2", Variant: "baseline", TaskPassed: true},
+		{TaskName: "task-2", Variant: "concise", TaskPassed: true},
+	}
+
+	stats := CalculateVariantStats("test.json", evalResults)
+	if len(stats) != 2 {
+		t.Fatalf("len(stats) = %d, want 2", len(stats))
+	}
+
+	if stats[0].Variant != "baseline" || stats[0].TasksPassed != 2 || stats[0].TasksTotal != 2 {
+		t.Errorf("stats[0] = %+v, want baseline with 2/2 tasks passed", stats[0])
+	}
+
+	if stats[1].Variant != "concise" || stats[1].TasksPassed != 1 || stats[1].TasksTotal != 2 {
+		t.Errorf("stats[1] = %+v, want concise with 1/2 tasks passed", stats[1])
+	}
+}
+
+func TestTaskLabel(t *testing.T) {
+	if label := TaskLabel(&eval.EvalResult{TaskName: "task-1"}); label != "task-1" {
+		t.Errorf("TaskLabel() = %q, want %q", label, "task-1")
+	}
+
+	if label := TaskLabel(&eval.EvalResult{TaskName: "task-1", Variant: "concise"}); label != "task-1 [concise]" {
+		t.Errorf("TaskLabel() = %q, want %q", label, "task-1 [concise]")
+	}
+}
+
 func TestLoad(t *testing.T) {
 	evalResults := sampleResults()
 	filePath := createTestResultsFile(t, evalResults)
@@ -179,6 +215,36 @@ func TestFilter(t *testing.T) {
 	}
 }
 
+func TestExcludeInfraFailures(t *testing.T) {
+	evalResults := append(sampleResults(),
+		&eval.EvalResult{TaskName: "task-4", TaskError: "429 Too Many Requests", AgentExecutionError: true, FailureKind: eval.FailureInfra},
+		&eval.EvalResult{TaskName: "task-5", TaskError: "agent crashed", AgentExecutionError: true, FailureKind: eval.FailureAgent},
+	)
+
+	stats := CalculateStats("test.json", evalResults)
+	if stats.InfraFailures != 1 {
+		t.Errorf("InfraFailures = %d, want 1", stats.InfraFailures)
+	}
+
+	filtered, excluded := ExcludeInfraFailures(evalResults)
+	if excluded != 1 {
+		t.Errorf("excluded = %d, want 1", excluded)
+	}
+	if len(filtered) != 4 {
+		t.Fatalf("ExcludeInfraFailures returned %d results, want 4", len(filtered))
+	}
+	for _, r := range filtered {
+		if r.TaskName == "task-4" {
+			t.Errorf("ExcludeInfraFailures kept infra failure %s", r.TaskName)
+		}
+	}
+
+	stats = CalculateStats("test.json", filtered)
+	if stats.TaskPassRate != 0.5 {
+		t.Errorf("TaskPassRate = %f, want 0.5", stats.TaskPassRate)
+	}
+}
+
 func TestParseOutput(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,464 @@
+// Package serverlint statically checks the tools, prompts and resources an MCP server
+// advertises for problems that commonly make agents fail, such as invalid schemas
+// and missing or overly long descriptions.
+package serverlint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/mcpchecker/mcpchecker/pkg/tokenizer"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Severities of an issue
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Rules an issue can be reported for
+const (
+	RuleInvalidSchema       = "invalid-schema"
+	RuleSchemaType          = "schema-type"
+	RuleMissingDescription  = "missing-description"
+	RuleLongDescription     = "long-description"
+	RuleDuplicateName       = "duplicate-name"
+	RuleMissingOutputSchema = "missing-output-schema"
+	RuleConnectFailed       = "connect-failed"
+	RuleListFailed          = "list-failed"
+)
+
+// Kinds of items that are checked
+const (
+	KindServer           = "server"
+	KindTool             = "tool"
+	KindPrompt           = "prompt"
+	KindResource         = "resource"
+	KindResourceTemplate = "resourceTemplate"
+)
+
+// DefaultMaxDescriptionTokens is the description length above which a warning is reported
+const DefaultMaxDescriptionTokens = 500
+
+type Options struct {
+	// MaxDescriptionTokens is the longest description that is not reported, defaults to DefaultMaxDescriptionTokens
+	MaxDescriptionTokens int
+}
+
+// Issue is a single problem found in a server
+type Issue struct {
+	Server   string `json:"server"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Report is the result of checking a set of servers
+type Report struct {
+	Servers []ServerStats `json:"servers"`
+	Issues  []Issue       `json:"issues"`
+
+	checked []Server
+}
+
+// ServerStats counts what a server advertises
+type ServerStats struct {
+	Name              string `json:"name"`
+	Tools             int    `json:"tools"`
+	Prompts           int    `json:"prompts"`
+	Resources         int    `json:"resources"`
+	ResourceTemplates int    `json:"resourceTemplates"`
+}
+
+// Server is what a single server advertises
+type Server struct {
+	Name              string
+	Tools             []*mcp.Tool
+	Prompts           []*mcp.Prompt
+	Resources         []*mcp.Resource
+	ResourceTemplates []*mcp.ResourceTemplate
+	// Failures are the kinds of items that could not be listed, or KindServer if the
+	// server could not be connected to
+	Failures []Failure
+}
+
+// Failure is an error listing the items of one kind from a server
+type Failure struct {
+	Kind string
+	Err  error
+}
+
+// Errors returns the number of issues with error severity
+func (r *Report) Errors() int {
+	return r.count(SeverityError)
+}
+
+// Warnings returns the number of issues with warning severity
+func (r *Report) Warnings() int {
+	return r.count(SeverityWarning)
+}
+
+func (r *Report) count(severity string) int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// Lint connects to the enabled servers of config, lists what they advertise and checks it.
+// Servers that cannot be connected to or listed are reported as issues.
+func Lint(ctx context.Context, config *mcpclient.MCPConfig, opts Options) (*Report, error) {
+	if config == nil {
+		return nil, fmt.Errorf("no config provided")
+	}
+
+	enabled := config.GetEnabledServers()
+	if len(enabled) == 0 {
+		return nil, fmt.Errorf("no enabled mcp servers found in config")
+	}
+
+	clients := make(map[string]*mcpclient.Client, len(enabled))
+	defer func() {
+		for _, cs := range clients {
+			_ = cs.Close()
+		}
+	}()
+
+	var unreachable []Server
+	for name, cfg := range enabled {
+		cs, err := mcpclient.Connect(ctx, cfg)
+		if err != nil {
+			unreachable = append(unreachable, Server{
+				Name:     name,
+				Failures: []Failure{{Kind: KindServer, Err: err}},
+			})
+			continue
+		}
+		clients[name] = cs
+	}
+
+	return Check(append(List(ctx, clients), unreachable...), opts), nil
+}
+
+// List lists the tools, prompts and resources of all connected servers. Prompts and
+// resources are only listed when the server advertises the capability. A failed listing
+// is recorded in the Failures of the server, and the other listings still run.
+func List(ctx context.Context, clients map[string]*mcpclient.Client) []Server {
+	servers := make([]Server, 0, len(clients))
+	for name, cs := range clients {
+		srv := Server{Name: name}
+
+		var caps *mcp.ServerCapabilities
+		if init := cs.InitializeResult(); init != nil {
+			caps = init.Capabilities
+		}
+
+		for t, err := range cs.Tools(ctx, nil) {
+			if err != nil {
+				srv.Failures = append(srv.Failures, Failure{Kind: KindTool, Err: err})
+				break
+			}
+			srv.Tools = append(srv.Tools, t)
+		}
+
+		if caps != nil && caps.Prompts != nil {
+			for p, err := range cs.Prompts(ctx, nil) {
+				if err != nil {
+					srv.Failures = append(srv.Failures, Failure{Kind: KindPrompt, Err: err})
+					break
+				}
+				srv.Prompts = append(srv.Prompts, p)
+			}
+		}
+
+		if caps != nil && caps.Resources != nil {
+			for r, err := range cs.Resources(ctx, nil) {
+				if err != nil {
+					srv.Failures = append(srv.Failures, Failure{Kind: KindResource, Err: err})
+					break
+				}
+				srv.Resources = append(srv.Resources, r)
+			}
+			for r, err := range cs.ResourceTemplates(ctx, nil) {
+				if err != nil {
+					srv.Failures = append(srv.Failures, Failure{Kind: KindResourceTemplate, Err: err})
+					break
+				}
+				srv.ResourceTemplates = append(srv.ResourceTemplates, r)
+			}
+		}
+
+		servers = append(servers, srv)
+	}
+
+	return servers
+}
+
+// Check reports the problems found in the servers
+func Check(servers []Server, opts Options) *Report {
+	if opts.MaxDescriptionTokens <= 0 {
+		opts.MaxDescriptionTokens = DefaultMaxDescriptionTokens
+	}
+
+	servers = slices.Clone(servers)
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].Name < servers[j].Name
+	})
+
+	report := &Report{
+		Servers: make([]ServerStats, 0, len(servers)),
+		Issues:  []Issue{},
+		checked: servers,
+	}
+
+	toolServers := make(map[string][]string)
+	promptServers := make(map[string][]string)
+	for _, srv := range servers {
+		report.Servers = append(report.Servers, ServerStats{
+			Name:              srv.Name,
+			Tools:             len(srv.Tools),
+			Prompts:           len(srv.Prompts),
+			Resources:         len(srv.Resources),
+			ResourceTemplates: len(srv.ResourceTemplates),
+		})
+
+		c := &checker{report: report, server: srv.Name, opts: opts}
+		for _, f := range srv.Failures {
+			c.failure(f)
+		}
+		for _, t := range srv.Tools {
+			c.tool(t)
+			toolServers[t.Name] = append(toolServers[t.Name], srv.Name)
+		}
+		for _, p := range srv.Prompts {
+			c.description(KindPrompt, p.Name, p.Description)
+			promptServers[p.Name] = append(promptServers[p.Name], srv.Name)
+		}
+		for _, r := range srv.Resources {
+			c.description(KindResource, r.URI, r.Description)
+		}
+		for _, r := range srv.ResourceTemplates {
+			c.description(KindResourceTemplate, r.URITemplate, r.Description)
+		}
+	}
+
+	report.duplicates(KindTool, toolServers)
+	report.duplicates(KindPrompt, promptServers)
+
+	return report
+}
+
+// CheckStructuredResults reports tools that returned structured content without declaring
+// an outputSchema. structured maps server names to the tools seen returning structured content.
+func (r *Report) CheckStructuredResults(structured map[string][]string) {
+	for _, srv := range r.checked {
+		for _, t := range srv.Tools {
+			if t.OutputSchema != nil || !slices.Contains(structured[srv.Name], t.Name) {
+				continue
+			}
+
+			r.Issues = append(r.Issues, Issue{
+				Server:   srv.Name,
+				Kind:     KindTool,
+				Name:     t.Name,
+				Rule:     RuleMissingOutputSchema,
+				Severity: SeverityWarning,
+				Message:  "returned structured content but declares no outputSchema, clients cannot validate or describe it",
+			})
+		}
+	}
+}
+
+func (r *Report) duplicates(kind string, servers map[string][]string) {
+	names := make([]string, 0, len(servers))
+	for name, s := range servers {
+		if len(s) > 1 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := servers[name]
+		for _, server := range s {
+			r.Issues = append(r.Issues, Issue{
+				Server:   server,
+				Kind:     kind,
+				Name:     name,
+				Rule:     RuleDuplicateName,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("name is also used by %s, agents that do not prefix names with the server cannot tell them apart", strings.Join(others(s, server), ", ")),
+			})
+		}
+	}
+}
+
+func others(servers []string, server string) []string {
+	var o []string
+	for _, s := range servers {
+		if s != server {
+			o = append(o, s)
+		}
+	}
+	return o
+}
+
+type checker struct {
+	report *Report
+	server string
+	opts   Options
+}
+
+func (c *checker) add(kind, name, rule, severity, message string) {
+	c.report.Issues = append(c.report.Issues, Issue{
+		Server:   c.server,
+		Kind:     kind,
+		Name:     name,
+		Rule:     rule,
+		Severity: severity,
+		Message:  message,
+	})
+}
+
+var failureItems = map[string]string{
+	KindTool:             "tools",
+	KindPrompt:           "prompts",
+	KindResource:         "resources",
+	KindResourceTemplate: "resource templates",
+}
+
+func (c *checker) failure(f Failure) {
+	if f.Kind == KindServer {
+		c.add(KindServer, c.server, RuleConnectFailed, SeverityError, fmt.Sprintf("failed to connect: %v", f.Err))
+		return
+	}
+
+	c.add(f.Kind, "", RuleListFailed, SeverityError, fmt.Sprintf("failed to list %s: %v", failureItems[f.Kind], f.Err))
+}
+
+func (c *checker) tool(t *mcp.Tool) {
+	c.description(KindTool, t.Name, t.Description)
+	c.schema(t.Name, "inputSchema", t.InputSchema)
+	if t.OutputSchema != nil {
+		c.schema(t.Name, "outputSchema", t.OutputSchema)
+	}
+}
+
+func (c *checker) description(kind, name, description string) {
+	if strings.TrimSpace(description) == "" {
+		c.add(kind, name, RuleMissingDescription, SeverityWarning, "has no description, agents have to guess what it does")
+		return
+	}
+
+	if n := countTokens(description); n > c.opts.MaxDescriptionTokens {
+		c.add(kind, name, RuleLongDescription, SeverityWarning,
+			fmt.Sprintf("description is %d tokens, longer than %d, and is sent to the model on every turn", n, c.opts.MaxDescriptionTokens))
+	}
+}
+
+// schema checks that a tool schema is a valid JSON Schema describing an object
+func (c *checker) schema(tool, field string, schema any) {
+	if schema == nil {
+		c.add(KindTool, tool, RuleInvalidSchema, SeverityError, fmt.Sprintf("has no %s", field))
+		return
+	}
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		c.add(KindTool, tool, RuleInvalidSchema, SeverityError, fmt.Sprintf("%s cannot be encoded: %v", field, err))
+		return
+	}
+
+	var s jsonschema.Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		c.add(KindTool, tool, RuleInvalidSchema, SeverityError, fmt.Sprintf("%s is not a JSON Schema: %v", field, err))
+		return
+	}
+
+	if _, err := s.Resolve(&jsonschema.ResolveOptions{ValidateDefaults: true}); err != nil {
+		c.add(KindTool, tool, RuleInvalidSchema, SeverityError, fmt.Sprintf("%s is not a valid JSON Schema: %v", field, err))
+		return
+	}
+
+	var raw any
+	_ = json.Unmarshal(data, &raw)
+	if bad := unknownTypes(raw); len(bad) > 0 {
+		c.add(KindTool, tool, RuleInvalidSchema, SeverityError, fmt.Sprintf("%s uses unknown types %s", field, strings.Join(bad, ", ")))
+		return
+	}
+
+	if s.Type != "object" && !slices.Equal(s.Types, []string{"object"}) {
+		c.add(KindTool, tool, RuleSchemaType, SeverityError, fmt.Sprintf("%s must have type object, got %q", field, schemaType(&s)))
+	}
+}
+
+var jsonTypes = []string{"array", "boolean", "integer", "null", "number", "object", "string"}
+
+// unknownTypes returns the type names in a raw schema that are not JSON Schema types,
+// which the schema resolver accepts
+func unknownTypes(schema any) []string {
+	var bad []string
+	switch v := schema.(type) {
+	case map[string]any:
+		for key, value := range v {
+			switch key {
+			case "type":
+				switch t := value.(type) {
+				case string:
+					if !slices.Contains(jsonTypes, t) {
+						bad = append(bad, fmt.Sprintf("%q", t))
+					}
+				case []any:
+					for _, name := range t {
+						if s, ok := name.(string); ok && !slices.Contains(jsonTypes, s) {
+							bad = append(bad, fmt.Sprintf("%q", s))
+						}
+					}
+				default:
+					// a property called "type"
+					bad = append(bad, unknownTypes(value)...)
+				}
+			case "enum", "const", "default", "examples":
+				// values, not schemas
+			default:
+				bad = append(bad, unknownTypes(value)...)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			bad = append(bad, unknownTypes(item)...)
+		}
+	}
+
+	sort.Strings(bad)
+	return bad
+}
+
+func schemaType(s *jsonschema.Schema) string {
+	if len(s.Types) > 0 {
+		return strings.Join(s.Types, ",")
+	}
+	return s.Type
+}
+
+// countTokens counts the tokens of text, falling back to an estimate of four
+// characters per token when the tokenizer is not available
+func countTokens(text string) int {
+	n, err := tokenizer.EstimateTokens(text)
+	if err != nil {
+		return len(text) / 4
+	}
+	return n
+}
@@ -0,0 +1,225 @@
+package serverlint
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var objectSchema = map[string]any{
+	"type":       "object",
+	"properties": map[string]any{"name": map[string]any{"type": "string"}},
+}
+
+func tool(name, description string, input any) *mcp.Tool {
+	return &mcp.Tool{Name: name, Description: description, InputSchema: input}
+}
+
+// rulesOf returns "server/name:rule" for every issue, which is what most tests care about
+func rulesOf(r *Report) []string {
+	rules := make([]string, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		rules = append(rules, issue.Server+"/"+issue.Name+":"+issue.Rule)
+	}
+	return rules
+}
+
+func TestCheck(t *testing.T) {
+	tests := map[string]struct {
+		servers  []Server
+		expected []string
+		errors   int
+	}{
+		"clean server": {
+			servers: []Server{{
+				Name:    "k8s",
+				Tools:   []*mcp.Tool{tool("pods_list", "List pods", objectSchema)},
+				Prompts: []*mcp.Prompt{{Name: "debug", Description: "Debug a pod"}},
+			}},
+			expected: []string{},
+		},
+		"missing descriptions": {
+			servers: []Server{{
+				Name:              "k8s",
+				Tools:             []*mcp.Tool{tool("pods_list", " ", objectSchema)},
+				Prompts:           []*mcp.Prompt{{Name: "debug"}},
+				Resources:         []*mcp.Resource{{URI: "k8s://pods"}},
+				ResourceTemplates: []*mcp.ResourceTemplate{{URITemplate: "k8s://pods/{name}", Description: "A pod"}},
+			}},
+			expected: []string{
+				"k8s/pods_list:missing-description",
+				"k8s/debug:missing-description",
+				"k8s/k8s://pods:missing-description",
+			},
+		},
+		"long description": {
+			servers: []Server{{
+				Name:  "k8s",
+				Tools: []*mcp.Tool{tool("pods_list", strings.Repeat("lists all the pods ", 200), objectSchema)},
+			}},
+			expected: []string{"k8s/pods_list:long-description"},
+		},
+		"non-object input schema": {
+			servers: []Server{{
+				Name:  "k8s",
+				Tools: []*mcp.Tool{tool("pods_list", "List pods", map[string]any{"type": "string"})},
+			}},
+			expected: []string{"k8s/pods_list:schema-type"},
+			errors:   1,
+		},
+		"invalid input schema": {
+			servers: []Server{{
+				Name: "k8s",
+				Tools: []*mcp.Tool{
+					tool("bad_type", "Bad type", map[string]any{"type": "object", "properties": map[string]any{"n": map[string]any{"type": "integr"}}}),
+					tool("bad_ref", "Bad ref", map[string]any{"type": "object", "properties": map[string]any{"n": map[string]any{"$ref": "#/$defs/missing"}}}),
+					tool("bad_default", "Bad default", map[string]any{"type": "object", "properties": map[string]any{"n": map[string]any{"type": "integer", "default": "one"}}}),
+					tool("no_schema", "No schema", nil),
+				},
+			}},
+			expected: []string{
+				"k8s/bad_type:invalid-schema",
+				"k8s/bad_ref:invalid-schema",
+				"k8s/bad_default:invalid-schema",
+				"k8s/no_schema:invalid-schema",
+			},
+			errors: 4,
+		},
+		"type as a list": {
+			servers: []Server{{
+				Name:  "k8s",
+				Tools: []*mcp.Tool{tool("pods_list", "List pods", map[string]any{"type": []any{"object"}})},
+			}},
+			expected: []string{},
+		},
+		"failed listings": {
+			servers: []Server{
+				{
+					Name:     "k8s",
+					Tools:    []*mcp.Tool{tool("pods_list", "List pods", objectSchema)},
+					Failures: []Failure{{Kind: KindPrompt, Err: errors.New("method not found")}},
+				},
+				{Name: "down", Failures: []Failure{{Kind: KindServer, Err: errors.New("connection refused")}}},
+			},
+			expected: []string{
+				"down/down:connect-failed",
+				"k8s/:list-failed",
+			},
+			errors: 2,
+		},
+		"invalid output schema": {
+			servers: []Server{{
+				Name:  "k8s",
+				Tools: []*mcp.Tool{{Name: "pods_list", Description: "List pods", InputSchema: objectSchema, OutputSchema: map[string]any{"type": "array"}}},
+			}},
+			expected: []string{"k8s/pods_list:schema-type"},
+			errors:   1,
+		},
+		"duplicate names across servers": {
+			servers: []Server{
+				{Name: "prod", Tools: []*mcp.Tool{tool("pods_list", "List pods", objectSchema)}},
+				{Name: "dev", Tools: []*mcp.Tool{tool("pods_list", "List pods", objectSchema)}},
+			},
+			expected: []string{
+				"dev/pods_list:duplicate-name",
+				"prod/pods_list:duplicate-name",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			report := Check(tc.servers, Options{MaxDescriptionTokens: 100})
+			assert.Equal(t, tc.expected, rulesOf(report))
+			assert.Equal(t, tc.errors, report.Errors())
+			assert.Equal(t, len(tc.expected)-tc.errors, report.Warnings())
+		})
+	}
+}
+
+func TestCheckStructuredResults(t *testing.T) {
+	servers := []Server{{
+		Name: "k8s",
+		Tools: []*mcp.Tool{
+			tool("pods_list", "List pods", objectSchema),
+			{Name: "pods_get", Description: "Get a pod", InputSchema: objectSchema, OutputSchema: objectSchema},
+			tool("pods_delete", "Delete a pod", objectSchema),
+		},
+	}}
+
+	report := Check(servers, Options{})
+	report.CheckStructuredResults(map[string][]string{"k8s": {"pods_list", "pods_get"}})
+
+	assert.Equal(t, []string{"k8s/pods_list:missing-output-schema"}, rulesOf(report))
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	server.AddTool(tool("echo", "Echo", map[string]any{"type": "object"}), func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	server.AddPrompt(&mcp.Prompt{Name: "greet"}, func(context.Context, *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ss.Close() })
+
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0.0.1"}, nil).Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cs.Close() })
+
+	servers := List(ctx, map[string]*mcpclient.Client{"test": {ClientSession: cs}})
+	require.Len(t, servers, 1)
+	assert.Equal(t, "test", servers[0].Name)
+	assert.Len(t, servers[0].Tools, 1)
+	assert.Len(t, servers[0].Prompts, 1)
+	assert.Empty(t, servers[0].Resources, "resources are not listed without the capability")
+	assert.Empty(t, servers[0].Failures)
+}
+
+func TestListRecordsFailures(t *testing.T) {
+	ctx := context.Background()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "0.0.1"}, nil)
+	server.AddTool(tool("echo", "Echo", map[string]any{"type": "object"}), func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	server.AddPrompt(&mcp.Prompt{Name: "greet"}, func(context.Context, *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	})
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "prompts/list" {
+				return nil, errors.New("prompts are broken")
+			}
+			return next(ctx, method, req)
+		}
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ss.Close() })
+
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "0.0.1"}, nil).Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cs.Close() })
+
+	servers := List(ctx, map[string]*mcpclient.Client{"test": {ClientSession: cs}})
+	require.Len(t, servers, 1)
+	assert.Len(t, servers[0].Tools, 1, "tools are still listed")
+	require.Len(t, servers[0].Failures, 1)
+	assert.Equal(t, KindPrompt, servers[0].Failures[0].Kind)
+	assert.ErrorContains(t, servers[0].Failures[0].Err, "prompts are broken")
+}
@@ -2,7 +2,7 @@ package steps
 
 import "fmt"
 
-// AgentResolver resolves {agent.output} and {agent.prompt} template variables.
+// AgentResolver resolves {agent.output}, {agent.prompt} and {agent.workdir} template variables.
 // It returns an error if the agent context has not been set yet (i.e. the step
 // is running before the agent phase).
 type AgentResolver struct {
@@ -16,7 +16,7 @@ func NewAgentResolver(agent *AgentContext) *AgentResolver {
 }
 
 // Resolve returns the value for an agent template variable.
-// Supported fields: "output" and "prompt".
+// Supported fields: "output", "prompt" and "workdir".
 func (r *AgentResolver) Resolve(fieldName string) (string, error) {
 	if r.agent == nil {
 		return "", fmt.Errorf("agent context is not available: agent has not run yet")
@@ -27,7 +27,9 @@ func (r *AgentResolver) Resolve(fieldName string) (string, error) {
 		return r.agent.Output, nil
 	case "prompt":
 		return r.agent.Prompt, nil
+	case "workdir":
+		return r.agent.workdir()
 	default:
-		return "", fmt.Errorf("unknown agent field %q: supported fields are \"output\" and \"prompt\"", fieldName)
+		return "", fmt.Errorf("unknown agent field %q: supported fields are \"output\", \"prompt\" and \"workdir\"", fieldName)
 	}
 }
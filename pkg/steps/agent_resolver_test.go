@@ -24,6 +24,18 @@ func TestAgentResolver(t *testing.T) {
 			field: "prompt",
 			want:  "do something",
 		},
+		{
+			name:  "resolve workdir",
+			agent: &AgentContext{Prompt: "p", Output: "o", Workdir: "/tmp/workspace"},
+			field: "workdir",
+			want:  "/tmp/workspace",
+		},
+		{
+			name:      "workdir not set",
+			agent:     &AgentContext{Prompt: "p", Output: "o"},
+			field:     "workdir",
+			expectErr: true,
+		},
 		{
 			name:      "nil agent context",
 			agent:     nil,
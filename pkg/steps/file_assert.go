@@ -0,0 +1,237 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/genmcp/gen-mcp/pkg/template"
+)
+
+// FileAssertStepConfig checks a file the agent left in its working directory.
+// Every configured check must pass for the step to succeed.
+type FileAssertStepConfig struct {
+	// Path of the file, relative to the agent workdir (supports templates)
+	Path string `json:"path"`
+	// Exists asserts the file exists (default) or, when false, that it does not
+	Exists *bool `json:"exists,omitempty"`
+	// Match is a list of regexes that must all match the file content
+	Match []string `json:"match,omitempty"`
+	// NotMatch is a list of regexes that must not match the file content
+	NotMatch []string `json:"notMatch,omitempty"`
+	// JSON parses the file as JSON and validates fields
+	JSON *StructuredOutputMatch `json:"json,omitempty"`
+	// YAML parses the file as YAML and validates fields
+	YAML *StructuredOutputMatch `json:"yaml,omitempty"`
+	// Golden is a file, relative to the task file, the content must equal.
+	// Line endings and trailing whitespace at the end of the file are ignored.
+	Golden string `json:"golden,omitempty"`
+}
+
+type FileAssertStep struct {
+	path     *template.TemplateBuilder
+	exists   bool
+	match    []*regexp.Regexp
+	notMatch []*regexp.Regexp
+	json     *StructuredOutputMatch
+	yaml     *StructuredOutputMatch
+	golden   string
+}
+
+var _ StepRunner = &FileAssertStep{}
+
+func ParseFileAssertStep(raw json.RawMessage) (StepRunner, error) {
+	cfg := &FileAssertStepConfig{}
+
+	err := json.Unmarshal(raw, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFileAssertStep(cfg)
+}
+
+func (cfg *FileAssertStepConfig) Validate() error {
+	if cfg.Path == "" {
+		return fmt.Errorf("fileAssert step must define a path")
+	}
+
+	if cfg.JSON != nil && cfg.YAML != nil {
+		return fmt.Errorf("fileAssert step cannot define both json and yaml")
+	}
+
+	hasContentChecks := len(cfg.Match) > 0 || len(cfg.NotMatch) > 0 || cfg.JSON != nil || cfg.YAML != nil || cfg.Golden != ""
+	if cfg.Exists != nil && !*cfg.Exists && hasContentChecks {
+		return fmt.Errorf("fileAssert step cannot check the content of a file that must not exist")
+	}
+
+	return nil
+}
+
+func NewFileAssertStep(cfg *FileAssertStepConfig) (*FileAssertStep, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	step := &FileAssertStep{
+		exists: cfg.Exists == nil || *cfg.Exists,
+		json:   cfg.JSON,
+		yaml:   cfg.YAML,
+		golden: cfg.Golden,
+	}
+
+	paths, err := parseSubstringTemplates("path", []string{cfg.Path})
+	if err != nil {
+		return nil, err
+	}
+	step.path = paths[0]
+
+	if step.match, err = compilePatterns("match", cfg.Match); err != nil {
+		return nil, err
+	}
+	if step.notMatch, err = compilePatterns("notMatch", cfg.NotMatch); err != nil {
+		return nil, err
+	}
+
+	return step, nil
+}
+
+func (s *FileAssertStep) Execute(ctx context.Context, input *StepInput) (*StepOutput, error) {
+	if input.Agent == nil {
+		return nil, fmt.Errorf("cannot run fileAssert step before agent (must be in verification)")
+	}
+
+	resolved, err := resolveTemplates([]*template.TemplateBuilder{s.path}, input)
+	if err != nil {
+		return nil, err
+	}
+
+	path := resolved[0]
+	if !filepath.IsAbs(path) {
+		workdir, err := input.Agent.workdir()
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve %q: %w", path, err)
+		}
+		path = filepath.Join(workdir, path)
+	}
+
+	out := &StepOutput{
+		Type:    "fileAssert",
+		Outputs: map[string]string{"path": path},
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	found := err == nil
+
+	if !s.exists {
+		out.Success = !found
+		if out.Success {
+			out.Message = fmt.Sprintf("%s does not exist", resolved[0])
+		} else {
+			out.Error = fmt.Sprintf("%s exists but must not", resolved[0])
+		}
+		return out, nil
+	}
+
+	if !found {
+		out.Error = fmt.Sprintf("%s does not exist", resolved[0])
+		return out, nil
+	}
+
+	content := string(data)
+	out.Outputs["content"] = content
+
+	var errors []string
+
+	for _, re := range s.match {
+		m := re.FindStringSubmatch(content)
+		if m == nil {
+			errors = append(errors, fmt.Sprintf("content did not match pattern %q", re.String()))
+			continue
+		}
+		for i, name := range re.SubexpNames() {
+			if name != "" {
+				out.Outputs[name] = m[i]
+			}
+		}
+	}
+
+	for _, re := range s.notMatch {
+		if re.MatchString(content) {
+			errors = append(errors, fmt.Sprintf("content matched forbidden pattern %q", re.String()))
+		}
+	}
+
+	if s.json != nil {
+		errors = append(errors, validateFileFields(s.json, content, "json", parseJSONOutput)...)
+	}
+	if s.yaml != nil {
+		errors = append(errors, validateFileFields(s.yaml, content, "yaml", parseYAMLOutput)...)
+	}
+
+	if s.golden != "" {
+		golden := s.golden
+		if !filepath.IsAbs(golden) {
+			golden = filepath.Join(input.Workdir, golden)
+		}
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read golden file: %w", err)
+		}
+		if diff := diffLines(string(expected), content); diff != "" {
+			errors = append(errors, fmt.Sprintf("content differs from %s: %s", s.golden, diff))
+		}
+	}
+
+	out.Success = len(errors) == 0
+	if out.Success {
+		out.Message = fmt.Sprintf("%s passed all checks", resolved[0])
+	} else {
+		out.Error = fmt.Sprintf("%s failed validation check: %s", resolved[0], strings.Join(errors, "; "))
+	}
+
+	return out, nil
+}
+
+func validateFileFields(m *StructuredOutputMatch, content, format string, parse func(string) (any, error)) []string {
+	data, err := parse(content)
+	if err != nil {
+		return []string{fmt.Sprintf("content is not valid %s: %s", format, err)}
+	}
+
+	var errors []string
+	for _, field := range m.Fields {
+		errors = append(errors, field.Validate(data)...)
+	}
+	return errors
+}
+
+// diffLines describes the first line where actual differs from expected, or returns
+// an empty string if they are equal. Line endings and trailing whitespace are ignored.
+func diffLines(expected, actual string) string {
+	normalize := func(s string) []string {
+		s = strings.TrimRight(strings.ReplaceAll(s, "\r\n", "\n"), " \t\n")
+		return strings.Split(s, "\n")
+	}
+
+	exp, act := normalize(expected), normalize(actual)
+	for i := 0; i < len(exp) || i < len(act); i++ {
+		switch {
+		case i >= len(act):
+			return fmt.Sprintf("line %d: expected %q, got end of file", i+1, exp[i])
+		case i >= len(exp):
+			return fmt.Sprintf("line %d: expected end of file, got %q", i+1, act[i])
+		case exp[i] != act[i]:
+			return fmt.Sprintf("line %d: expected %q, got %q", i+1, exp[i], act[i])
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,181 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileAssertStep(t *testing.T) {
+	manifest := "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: web\nspec:\n  replicas: 3\n"
+
+	tt := map[string]struct {
+		config      string
+		files       map[string]string
+		golden      string
+		success     bool
+		errContains string
+		outputs     map[string]string
+	}{
+		"file exists": {
+			config:  `{"path": "deploy.yaml"}`,
+			files:   map[string]string{"deploy.yaml": manifest},
+			success: true,
+			outputs: map[string]string{"content": manifest},
+		},
+		"file missing": {
+			config:      `{"path": "deploy.yaml"}`,
+			errContains: "deploy.yaml does not exist",
+		},
+		"file must not exist": {
+			config:  `{"path": "secret.txt", "exists": false}`,
+			success: true,
+		},
+		"file exists but must not": {
+			config:      `{"path": "secret.txt", "exists": false}`,
+			files:       map[string]string{"secret.txt": "token"},
+			errContains: "exists but must not",
+		},
+		"workdir template": {
+			config:  `{"path": "{agent.workdir}/out/report.md"}`,
+			files:   map[string]string{"out/report.md": "# Report"},
+			success: true,
+		},
+		"content match with named group": {
+			config:  `{"path": "deploy.yaml", "match": ["name: (?P<name>\\S+)"]}`,
+			files:   map[string]string{"deploy.yaml": manifest},
+			success: true,
+			outputs: map[string]string{"name": "web"},
+		},
+		"content does not match": {
+			config:      `{"path": "deploy.yaml", "match": ["kind: Service"]}`,
+			files:       map[string]string{"deploy.yaml": manifest},
+			errContains: `content did not match pattern "kind: Service"`,
+		},
+		"content matches forbidden pattern": {
+			config:      `{"path": "deploy.yaml", "notMatch": ["replicas: \\d+"]}`,
+			files:       map[string]string{"deploy.yaml": manifest},
+			errContains: "forbidden pattern",
+		},
+		"yaml fields": {
+			config:  `{"path": "deploy.yaml", "yaml": {"fields": [{"path": "kind", "equals": "Deployment"}, {"path": "spec.replicas", "equals": 3}]}}`,
+			files:   map[string]string{"deploy.yaml": manifest},
+			success: true,
+		},
+		"yaml field mismatch": {
+			config:      `{"path": "deploy.yaml", "yaml": {"fields": [{"path": "spec.replicas", "equals": 2}]}}`,
+			files:       map[string]string{"deploy.yaml": manifest},
+			errContains: "spec.replicas",
+		},
+		"invalid json": {
+			config:      `{"path": "deploy.yaml", "json": {"fields": [{"path": "kind", "exists": true}]}}`,
+			files:       map[string]string{"deploy.yaml": manifest},
+			errContains: "content is not valid json",
+		},
+		"golden matches ignoring trailing newline and line endings": {
+			config:  `{"path": "deploy.yaml", "golden": "expected.yaml"}`,
+			files:   map[string]string{"deploy.yaml": manifest + "\n\n"},
+			golden:  "apiVersion: apps/v1\r\nkind: Deployment\r\nmetadata:\r\n  name: web\r\nspec:\r\n  replicas: 3",
+			success: true,
+		},
+		"golden differs": {
+			config:      `{"path": "deploy.yaml", "golden": "expected.yaml"}`,
+			files:       map[string]string{"deploy.yaml": manifest},
+			golden:      "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: api\n",
+			errContains: `line 4: expected "  name: api", got "  name: web"`,
+		},
+		"golden shorter than content": {
+			config:      `{"path": "deploy.yaml", "golden": "expected.yaml"}`,
+			files:       map[string]string{"deploy.yaml": manifest},
+			golden:      "apiVersion: apps/v1\n",
+			errContains: `line 2: expected end of file, got "kind: Deployment"`,
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			workdir := t.TempDir()
+			for name, content := range tc.files {
+				path := filepath.Join(workdir, name)
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+			}
+
+			taskDir := t.TempDir()
+			if tc.golden != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(taskDir, "expected.yaml"), []byte(tc.golden), 0o644))
+			}
+
+			step, err := ParseFileAssertStep(json.RawMessage(tc.config))
+			require.NoError(t, err)
+
+			out, err := step.Execute(context.Background(), &StepInput{
+				Workdir: taskDir,
+				Agent:   &AgentContext{Prompt: "prompt", Output: "output", Workdir: workdir},
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, "fileAssert", out.Type)
+			assert.Equal(t, tc.success, out.Success, out.Error)
+			if tc.errContains != "" {
+				assert.Contains(t, out.Error, tc.errContains)
+			}
+			for k, v := range tc.outputs {
+				assert.Equal(t, v, out.Outputs[k])
+			}
+		})
+	}
+}
+
+func TestFileAssertStepConfigValidation(t *testing.T) {
+	tt := map[string]struct {
+		config      string
+		errContains string
+	}{
+		"no path": {
+			config:      `{}`,
+			errContains: "must define a path",
+		},
+		"json and yaml": {
+			config:      `{"path": "a", "json": {}, "yaml": {}}`,
+			errContains: "both json and yaml",
+		},
+		"content checks on missing file": {
+			config:      `{"path": "a", "exists": false, "match": ["x"]}`,
+			errContains: "must not exist",
+		},
+		"invalid regex": {
+			config:      `{"path": "a", "match": ["("]}`,
+			errContains: "invalid regex",
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			_, err := ParseFileAssertStep(json.RawMessage(tc.config))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errContains)
+		})
+	}
+}
+
+func TestFileAssertStepRequiresWorkdir(t *testing.T) {
+	step, err := ParseFileAssertStep(json.RawMessage(`{"path": "report.md"}`))
+	require.NoError(t, err)
+
+	_, err = step.Execute(context.Background(), &StepInput{})
+	require.Error(t, err)
+
+	_, err = step.Execute(context.Background(), &StepInput{Agent: &AgentContext{}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "agent workdir is not available")
+
+	_, err = step.Execute(context.Background(), &StepInput{Agent: &AgentContext{Rejudge: true}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "agent workdir is unavailable during rejudge")
+}
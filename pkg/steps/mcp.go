@@ -13,13 +13,17 @@ import (
 type McpStep struct {
 	serverName string
 	toolName   string
+	tool       *mcp.Tool
 	Args       map[string]any `json:"args,omitempty"`
 	Expect     *McpExpect     `json:"expect,omitempty"`
 }
 
 type McpExpect struct {
-	IsError *bool       `json:"isError,omitempty"`
-	Content *ExpectBody `json:"content,omitempty"`
+	IsError           *bool       `json:"isError,omitempty"`
+	Content           *ExpectBody `json:"content,omitempty"`
+	StructuredContent *ExpectBody `json:"structuredContent,omitempty"`
+	// OutputSchemaValid fails the step if the result does not match the outputSchema of the tool
+	OutputSchemaValid bool `json:"outputSchemaValid,omitempty"`
 }
 
 var _ StepRunner = &McpStep{}
@@ -36,18 +40,18 @@ func NewMcpServerParser(ctx context.Context, serverName string) PrefixParser {
 			return nil, fmt.Errorf("no mcp server registered that matches name %q", serverName)
 		}
 
-		found := false
+		var tool *mcp.Tool
 		for _, t := range client.GetAllowedTools(ctx) {
 			if t.Name == toolName {
-				found = true
+				tool = t
 			}
 		}
 
-		if !found {
+		if tool == nil {
 			return nil, fmt.Errorf("no tool named %q registered on mcp server %q", toolName, serverName)
 		}
 
-		step := &McpStep{serverName: serverName, toolName: toolName}
+		step := &McpStep{serverName: serverName, toolName: toolName, tool: tool}
 
 		if err := json.Unmarshal(raw, &step); err != nil {
 			return nil, fmt.Errorf("failed to unmarshal mcp step config: %w", err)
@@ -88,12 +92,29 @@ func (s *McpStep) Execute(ctx context.Context, input *StepInput) (*StepOutput, e
 		return out, nil
 	}
 
-	// TODO: improve output handling to store more detailed output (not just string type)
 	serializedOut, marshalErr := json.Marshal(res.Content)
 	if marshalErr == nil {
 		out.Outputs["content"] = string(serializedOut)
 	}
 
+	// Structured content is kept typed, and its top-level fields are also exposed as outputs
+	// so that later steps can reference them as {steps.<server>.<tool>.<field>}
+	var serializedStructured []byte
+	if res.StructuredContent != nil {
+		serializedStructured, marshalErr = json.Marshal(res.StructuredContent)
+		if marshalErr == nil {
+			var structured any
+			_ = json.Unmarshal(serializedStructured, &structured)
+			out.Structured = structured
+			out.Outputs["structuredContent"] = string(serializedStructured)
+			for k, v := range structuredOutputs(structured) {
+				if _, ok := out.Outputs[k]; !ok {
+					out.Outputs[k] = v
+				}
+			}
+		}
+	}
+
 	if res.IsError {
 		if s.Expect != nil && s.Expect.IsError != nil && *s.Expect.IsError {
 			out.Success = true
@@ -102,6 +123,14 @@ func (s *McpStep) Execute(ctx context.Context, input *StepInput) (*StepOutput, e
 		}
 	}
 
+	if s.Expect != nil && s.Expect.OutputSchemaValid {
+		if err := client.OutputSchemas().Validate(s.tool, res); err != nil {
+			out.Success = false
+			out.Message = err.Error()
+			return out, nil
+		}
+	}
+
 	if s.Expect != nil && s.Expect.Content != nil {
 		errors := s.Expect.Content.Validate(serializedOut)
 		if len(errors) > 0 {
@@ -110,5 +139,44 @@ func (s *McpStep) Execute(ctx context.Context, input *StepInput) (*StepOutput, e
 		}
 	}
 
+	if s.Expect != nil && s.Expect.StructuredContent != nil {
+		if serializedStructured == nil {
+			out.Success = false
+			out.Message = "response has no structured content to validate"
+			return out, nil
+		}
+
+		errors := s.Expect.StructuredContent.Validate(serializedStructured)
+		if len(errors) > 0 {
+			out.Success = false
+			out.Message = fmt.Sprintf("response failed structured content validation: %s", strings.Join(errors, ";"))
+		}
+	}
+
 	return out, nil
 }
+
+// structuredOutputs flattens the top-level fields of an object into step outputs.
+// Strings are used as they are, other values as JSON.
+func structuredOutputs(structured any) map[string]string {
+	obj, ok := structured.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	outputs := make(map[string]string, len(obj))
+	for k, v := range obj {
+		if str, ok := v.(string); ok {
+			outputs[k] = str
+			continue
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		outputs[k] = string(data)
+	}
+
+	return outputs
+}
@@ -0,0 +1,41 @@
+package steps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructuredOutputs(t *testing.T) {
+	tests := map[string]struct {
+		structured any
+		expected   map[string]string
+	}{
+		"object fields": {
+			structured: map[string]any{
+				"name":   "nginx",
+				"count":  float64(3),
+				"ready":  true,
+				"labels": map[string]any{"app": "web"},
+				"pods":   []any{"a", "b"},
+			},
+			expected: map[string]string{
+				"name":   "nginx",
+				"count":  "3",
+				"ready":  "true",
+				"labels": `{"app":"web"}`,
+				"pods":   `["a","b"]`,
+			},
+		},
+		"non-object content has no fields": {
+			structured: []any{"a", "b"},
+			expected:   nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, structuredOutputs(tc.structured))
+		})
+	}
+}
@@ -0,0 +1,413 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/genmcp/gen-mcp/pkg/template"
+	"sigs.k8s.io/yaml"
+)
+
+// OutputMatchStepConfig checks the agent output without calling an LLM judge.
+// Every configured check must pass for the step to succeed.
+type OutputMatchStepConfig struct {
+	// Match is a list of regexes that must all match the output
+	Match []string `json:"match,omitempty"`
+	// NotMatch is a list of regexes that must not match the output
+	NotMatch []string `json:"notMatch,omitempty"`
+	// Contains lists substrings that must all appear in the output (supports templates)
+	Contains []string `json:"contains,omitempty"`
+	// ContainsAny lists substrings of which at least one must appear in the output (supports templates)
+	ContainsAny []string `json:"containsAny,omitempty"`
+	// NotContains lists substrings that must not appear in the output (supports templates)
+	NotContains []string `json:"notContains,omitempty"`
+	// IgnoreCase makes substring checks case-insensitive
+	IgnoreCase bool `json:"ignoreCase,omitempty"`
+
+	// JSON parses the output as JSON and validates fields
+	JSON *StructuredOutputMatch `json:"json,omitempty"`
+	// YAML parses the output as YAML and validates fields
+	YAML *StructuredOutputMatch `json:"yaml,omitempty"`
+
+	// Numbers extracts numeric values from the output and compares them
+	Numbers []NumberMatch `json:"numbers,omitempty"`
+}
+
+// StructuredOutputMatch validates fields of a JSON or YAML document in the output.
+// If the whole output does not parse, the first fenced code block is used instead.
+type StructuredOutputMatch struct {
+	Fields []FieldAssertion `json:"fields,omitempty"`
+}
+
+// NumberMatch extracts a number from the output and checks its value
+type NumberMatch struct {
+	// Pattern is a regex whose first capture group (or whole match if it has none)
+	// is parsed as the number. Defaults to the first number in the output.
+	Pattern   string   `json:"pattern,omitempty"`
+	Equals    *float64 `json:"equals,omitempty"`
+	Tolerance float64  `json:"tolerance,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+}
+
+type OutputMatchStep struct {
+	match       []*regexp.Regexp
+	notMatch    []*regexp.Regexp
+	contains    []*template.TemplateBuilder
+	containsAny []*template.TemplateBuilder
+	notContains []*template.TemplateBuilder
+	ignoreCase  bool
+	json        *StructuredOutputMatch
+	yaml        *StructuredOutputMatch
+	numbers     []numberMatcher
+}
+
+type numberMatcher struct {
+	NumberMatch
+	pattern *regexp.Regexp
+}
+
+var _ StepRunner = &OutputMatchStep{}
+
+var (
+	defaultNumberPattern = regexp.MustCompile(`-?\d+(?:\.\d+)?`)
+	fencedBlockPattern   = regexp.MustCompile("(?s)```[a-zA-Z]*\\s*\n(.*?)```")
+)
+
+func ParseOutputMatchStep(raw json.RawMessage) (StepRunner, error) {
+	cfg := &OutputMatchStepConfig{}
+
+	err := json.Unmarshal(raw, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOutputMatchStep(cfg)
+}
+
+func (cfg *OutputMatchStepConfig) Validate() error {
+	if len(cfg.Match) == 0 && len(cfg.NotMatch) == 0 && len(cfg.Contains) == 0 &&
+		len(cfg.ContainsAny) == 0 && len(cfg.NotContains) == 0 && cfg.JSON == nil &&
+		cfg.YAML == nil && len(cfg.Numbers) == 0 {
+		return fmt.Errorf("outputMatch step must define at least one check")
+	}
+
+	if cfg.JSON != nil && cfg.YAML != nil {
+		return fmt.Errorf("outputMatch step cannot define both json and yaml")
+	}
+
+	for i, n := range cfg.Numbers {
+		if n.Equals == nil && n.Min == nil && n.Max == nil {
+			return fmt.Errorf("numbers[%d]: one of equals, min or max must be set", i)
+		}
+		if n.Tolerance < 0 {
+			return fmt.Errorf("numbers[%d]: tolerance must not be negative", i)
+		}
+	}
+
+	return nil
+}
+
+func NewOutputMatchStep(cfg *OutputMatchStepConfig) (*OutputMatchStep, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	step := &OutputMatchStep{
+		ignoreCase: cfg.IgnoreCase,
+		json:       cfg.JSON,
+		yaml:       cfg.YAML,
+	}
+
+	var err error
+	if step.match, err = compilePatterns("match", cfg.Match); err != nil {
+		return nil, err
+	}
+	if step.notMatch, err = compilePatterns("notMatch", cfg.NotMatch); err != nil {
+		return nil, err
+	}
+	if step.contains, err = parseSubstringTemplates("contains", cfg.Contains); err != nil {
+		return nil, err
+	}
+	if step.containsAny, err = parseSubstringTemplates("containsAny", cfg.ContainsAny); err != nil {
+		return nil, err
+	}
+	if step.notContains, err = parseSubstringTemplates("notContains", cfg.NotContains); err != nil {
+		return nil, err
+	}
+
+	for i, n := range cfg.Numbers {
+		pattern := defaultNumberPattern
+		if n.Pattern != "" {
+			pattern, err = regexp.Compile(n.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("numbers[%d]: invalid pattern %q: %w", i, n.Pattern, err)
+			}
+		}
+		step.numbers = append(step.numbers, numberMatcher{NumberMatch: n, pattern: pattern})
+	}
+
+	return step, nil
+}
+
+func compilePatterns(field string, patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for i, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%d]: invalid regex %q: %w", field, i, p, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+func parseSubstringTemplates(field string, values []string) ([]*template.TemplateBuilder, error) {
+	sources := map[string]template.SourceFactory{
+		"agent":  template.NewSourceFactory("agent"),
+		"steps":  template.NewSourceFactory("steps"),
+		"random": template.NewSourceFactory("random"),
+	}
+	parseOpts := template.TemplateParserOptions{Sources: sources}
+
+	res := make([]*template.TemplateBuilder, 0, len(values))
+	for i, v := range values {
+		parsed, err := template.ParseTemplate(v, parseOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s[%d] template: %w", field, i, err)
+		}
+		builder, err := template.NewTemplateBuilder(parsed, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create template builder for %s[%d]: %w", field, i, err)
+		}
+		res = append(res, builder)
+	}
+	return res, nil
+}
+
+func (s *OutputMatchStep) Execute(ctx context.Context, input *StepInput) (*StepOutput, error) {
+	if input.Agent == nil {
+		return nil, fmt.Errorf("cannot run outputMatch step before agent (must be in verification)")
+	}
+
+	output := input.Agent.Output
+
+	contains, err := resolveTemplates(s.contains, input)
+	if err != nil {
+		return nil, err
+	}
+	containsAny, err := resolveTemplates(s.containsAny, input)
+	if err != nil {
+		return nil, err
+	}
+	notContains, err := resolveTemplates(s.notContains, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var errors []string
+	outputs := make(map[string]string)
+
+	for _, re := range s.match {
+		m := re.FindStringSubmatch(output)
+		if m == nil {
+			errors = append(errors, fmt.Sprintf("output did not match pattern %q", re.String()))
+			continue
+		}
+		for i, name := range re.SubexpNames() {
+			if name != "" {
+				outputs[name] = m[i]
+			}
+		}
+	}
+
+	for _, re := range s.notMatch {
+		if re.MatchString(output) {
+			errors = append(errors, fmt.Sprintf("output matched forbidden pattern %q", re.String()))
+		}
+	}
+
+	for _, sub := range contains {
+		if !s.containsSubstring(output, sub) {
+			errors = append(errors, fmt.Sprintf("output does not contain %q", sub))
+		}
+	}
+
+	if len(containsAny) > 0 {
+		found := false
+		for _, sub := range containsAny {
+			if s.containsSubstring(output, sub) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errors = append(errors, fmt.Sprintf("output does not contain any of %q", containsAny))
+		}
+	}
+
+	for _, sub := range notContains {
+		if s.containsSubstring(output, sub) {
+			errors = append(errors, fmt.Sprintf("output contains forbidden substring %q", sub))
+		}
+	}
+
+	if s.json != nil {
+		errors = append(errors, s.json.validate(output, "json", parseJSONOutput)...)
+	}
+	if s.yaml != nil {
+		errors = append(errors, s.yaml.validate(output, "yaml", parseYAMLOutput)...)
+	}
+
+	for i, n := range s.numbers {
+		value, err := n.extract(output)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("numbers[%d]: %s", i, err))
+			continue
+		}
+		outputs[fmt.Sprintf("number%d", i)] = strconv.FormatFloat(value, 'f', -1, 64)
+		errors = append(errors, n.check(i, value)...)
+	}
+
+	out := &StepOutput{
+		Type:    "outputMatch",
+		Success: len(errors) == 0,
+	}
+	if len(outputs) > 0 {
+		out.Outputs = outputs
+	}
+
+	if out.Success {
+		out.Message = "output passed all checks"
+	} else {
+		out.Error = fmt.Sprintf("output failed validation check: %s", strings.Join(errors, "; "))
+	}
+
+	return out, nil
+}
+
+// resolveTemplates resolves templates built by parseSubstringTemplates for a step input
+func resolveTemplates(builders []*template.TemplateBuilder, input *StepInput) ([]string, error) {
+	stepOutputs := input.StepOutputs
+	if stepOutputs == nil {
+		stepOutputs = make(map[string]map[string]string)
+	}
+
+	res := make([]string, 0, len(builders))
+	for _, b := range builders {
+		b.SetSourceResolver("steps", NewStepOutputResolver(stepOutputs))
+		b.SetSourceResolver("agent", NewAgentResolver(input.Agent))
+		if input.Random != nil {
+			b.SetSourceResolver("random", input.Random)
+		}
+
+		result, err := b.GetResult()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve substring template: %w", err)
+		}
+		str, ok := result.(string)
+		if !ok {
+			return nil, fmt.Errorf("substring template resolved to non-string type: %T", result)
+		}
+		res = append(res, str)
+	}
+
+	return res, nil
+}
+
+func (s *OutputMatchStep) containsSubstring(output, sub string) bool {
+	if s.ignoreCase {
+		return strings.Contains(strings.ToLower(output), strings.ToLower(sub))
+	}
+	return strings.Contains(output, sub)
+}
+
+func (m *StructuredOutputMatch) validate(output, format string, parse func(string) (any, error)) []string {
+	data, err := parse(strings.TrimSpace(output))
+	if err != nil {
+		block, ok := firstFencedBlock(output)
+		if !ok {
+			return []string{fmt.Sprintf("output is not valid %s: %s", format, err)}
+		}
+		data, err = parse(block)
+		if err != nil {
+			return []string{fmt.Sprintf("code block in output is not valid %s: %s", format, err)}
+		}
+	}
+
+	var errors []string
+	for _, field := range m.Fields {
+		errors = append(errors, field.Validate(data)...)
+	}
+	return errors
+}
+
+func parseJSONOutput(s string) (any, error) {
+	var data any
+	if err := json.Unmarshal([]byte(s), &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// parseYAMLOutput converts YAML to JSON first so numbers decode as float64,
+// matching the types FieldAssertion expects.
+func parseYAMLOutput(s string) (any, error) {
+	raw, err := yaml.YAMLToJSON([]byte(s))
+	if err != nil {
+		return nil, err
+	}
+	return parseJSONOutput(string(raw))
+}
+
+func firstFencedBlock(output string) (string, bool) {
+	m := fencedBlockPattern.FindStringSubmatch(output)
+	if m == nil {
+		return "", false
+	}
+	return strings.TrimSpace(m[1]), true
+}
+
+func (n *numberMatcher) extract(output string) (float64, error) {
+	m := n.pattern.FindStringSubmatch(output)
+	if m == nil {
+		return 0, fmt.Errorf("no number found matching pattern %q", n.pattern.String())
+	}
+
+	raw := m[0]
+	if len(m) > 1 {
+		raw = m[1]
+	}
+
+	value, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(raw), ",", ""), 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %q as a number: %w", raw, err)
+	}
+
+	return value, nil
+}
+
+func (n *numberMatcher) check(i int, value float64) []string {
+	var errors []string
+
+	if n.Equals != nil && math.Abs(value-*n.Equals) > n.Tolerance {
+		if n.Tolerance > 0 {
+			errors = append(errors, fmt.Sprintf("numbers[%d]: expected %v ± %v, got %v", i, *n.Equals, n.Tolerance, value))
+		} else {
+			errors = append(errors, fmt.Sprintf("numbers[%d]: expected %v, got %v", i, *n.Equals, value))
+		}
+	}
+	if n.Min != nil && value < *n.Min {
+		errors = append(errors, fmt.Sprintf("numbers[%d]: expected at least %v, got %v", i, *n.Min, value))
+	}
+	if n.Max != nil && value > *n.Max {
+		errors = append(errors, fmt.Sprintf("numbers[%d]: expected at most %v, got %v", i, *n.Max, value))
+	}
+
+	return errors
+}
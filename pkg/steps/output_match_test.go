@@ -0,0 +1,187 @@
+package steps
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputMatchStep(t *testing.T) {
+	tt := map[string]struct {
+		config      string
+		output      string
+		stepOutputs map[string]map[string]string
+		success     bool
+		errContains string
+		outputs     map[string]string
+	}{
+		"regex match with named group": {
+			config:  `{"match": ["image (?P<image>\\S+)"]}`,
+			output:  "The pod runs image nginx:1.25",
+			success: true,
+			outputs: map[string]string{"image": "nginx:1.25"},
+		},
+		"regex does not match": {
+			config:      `{"match": ["^ok$"]}`,
+			output:      "not ok",
+			errContains: `output did not match pattern "^ok$"`,
+		},
+		"not match": {
+			config:      `{"notMatch": ["(?i)error"]}`,
+			output:      "an Error occurred",
+			errContains: "forbidden pattern",
+		},
+		"contains all": {
+			config:  `{"contains": ["web-1", "web-2"]}`,
+			output:  "pods: web-1, web-2",
+			success: true,
+		},
+		"contains missing one": {
+			config:      `{"contains": ["web-1", "web-3"]}`,
+			output:      "pods: web-1, web-2",
+			errContains: `output does not contain "web-3"`,
+		},
+		"contains ignore case": {
+			config:  `{"contains": ["RUNNING"], "ignoreCase": true}`,
+			output:  "pod is running",
+			success: true,
+		},
+		"contains with step template": {
+			config:      `{"contains": ["{steps.script.name}"]}`,
+			output:      "pod web is running",
+			stepOutputs: map[string]map[string]string{"script": {"name": "web"}},
+			success:     true,
+		},
+		"contains any": {
+			config:  `{"containsAny": ["Running", "Ready"]}`,
+			output:  "pod is Ready",
+			success: true,
+		},
+		"contains none of any": {
+			config:      `{"containsAny": ["Running", "Ready"]}`,
+			output:      "pod is Pending",
+			errContains: "does not contain any of",
+		},
+		"not contains": {
+			config:      `{"notContains": ["secret"]}`,
+			output:      "the secret is 42",
+			errContains: `forbidden substring "secret"`,
+		},
+		"json fields": {
+			config:  `{"json": {"fields": [{"path": "items[0].name", "equals": "web"}, {"path": "count", "equals": 2}]}}`,
+			output:  `{"items": [{"name": "web"}], "count": 2}`,
+			success: true,
+		},
+		"json in fenced block": {
+			config:  `{"json": {"fields": [{"path": "status", "equals": "ok"}]}}`,
+			output:  "Here is the result:\n```json\n{\"status\": \"ok\"}\n```\n",
+			success: true,
+		},
+		"invalid json": {
+			config:      `{"json": {"fields": [{"path": "status", "exists": true}]}}`,
+			output:      "no json here",
+			errContains: "output is not valid json",
+		},
+		"yaml fields": {
+			config:  `{"yaml": {"fields": [{"path": "spec.replicas", "equals": 3}, {"path": "metadata.name", "match": "^web"}]}}`,
+			output:  "```yaml\nmetadata:\n  name: web-app\nspec:\n  replicas: 3\n```",
+			success: true,
+		},
+		"yaml field mismatch": {
+			config:      `{"yaml": {"fields": [{"path": "spec.replicas", "equals": 5}]}}`,
+			output:      "spec:\n  replicas: 3\n",
+			errContains: `field "spec.replicas": expected 5, got 3`,
+		},
+		"number within tolerance": {
+			config:  `{"numbers": [{"pattern": "uses ([\\d.]+)% CPU", "equals": 42, "tolerance": 0.5}]}`,
+			output:  "The pod uses 42.3% CPU",
+			success: true,
+			outputs: map[string]string{"number0": "42.3"},
+		},
+		"number outside tolerance": {
+			config:      `{"numbers": [{"equals": 10, "tolerance": 1}]}`,
+			output:      "there are 12 pods",
+			errContains: "expected 10 ± 1, got 12",
+		},
+		"number range with thousands separator": {
+			config:  `{"numbers": [{"pattern": "total: ([\\d,]+)", "min": 1000, "max": 2000}]}`,
+			output:  "total: 1,500",
+			success: true,
+		},
+		"number not found": {
+			config:      `{"numbers": [{"equals": 1}]}`,
+			output:      "no digits",
+			errContains: "no number found",
+		},
+		"multiple failures are joined": {
+			config:      `{"contains": ["a1"], "notContains": ["b"]}`,
+			output:      "b",
+			errContains: `output does not contain "a1"; output contains forbidden substring "b"`,
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			step, err := ParseOutputMatchStep(json.RawMessage(tc.config))
+			require.NoError(t, err)
+
+			out, err := step.Execute(context.Background(), &StepInput{
+				Agent:       &AgentContext{Prompt: "prompt", Output: tc.output},
+				StepOutputs: tc.stepOutputs,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, "outputMatch", out.Type)
+			assert.Equal(t, tc.success, out.Success, out.Error)
+			if tc.errContains != "" {
+				assert.Contains(t, out.Error, tc.errContains)
+			}
+			for k, v := range tc.outputs {
+				assert.Equal(t, v, out.Outputs[k])
+			}
+		})
+	}
+}
+
+func TestOutputMatchStepConfigValidation(t *testing.T) {
+	tt := map[string]struct {
+		config      string
+		errContains string
+	}{
+		"no checks": {
+			config:      `{}`,
+			errContains: "at least one check",
+		},
+		"json and yaml": {
+			config:      `{"json": {}, "yaml": {}}`,
+			errContains: "both json and yaml",
+		},
+		"number without bounds": {
+			config:      `{"numbers": [{"pattern": "(\\d+)"}]}`,
+			errContains: "one of equals, min or max",
+		},
+		"invalid regex": {
+			config:      `{"match": ["("]}`,
+			errContains: "invalid regex",
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			_, err := ParseOutputMatchStep(json.RawMessage(tc.config))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errContains)
+		})
+	}
+}
+
+func TestOutputMatchStepRequiresAgent(t *testing.T) {
+	step, err := ParseOutputMatchStep(json.RawMessage(`{"contains": ["x"]}`))
+	require.NoError(t, err)
+
+	_, err = step.Execute(context.Background(), &StepInput{})
+	require.Error(t, err)
+}
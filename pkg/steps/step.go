@@ -3,6 +3,7 @@ package steps
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/mcpchecker/mcpchecker/pkg/tokens"
@@ -35,13 +36,31 @@ type StepOutput struct {
 	Success bool              `json:"success"`
 	Message string            `json:"message,omitempty"`
 	Outputs map[string]string `json:"outputs,omitempty"`
-	Error   string            `json:"error,omitempty"`
-	Usage   *tokens.Usage     `json:"usage,omitempty"`
+	// Structured is typed output of the step, such as the structured content of an MCP tool result
+	Structured any           `json:"structured,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Usage      *tokens.Usage `json:"usage,omitempty"`
 }
 
 type AgentContext struct {
 	Prompt string
 	Output string
+	// Workdir is the working directory the agent ran in, with the files it left behind
+	Workdir string
+	// Rejudge is set when steps run against a recorded run, whose workdir no longer exists
+	Rejudge bool
+}
+
+// workdir returns the working directory the agent ran in
+func (a *AgentContext) workdir() (string, error) {
+	switch {
+	case a.Workdir != "":
+		return a.Workdir, nil
+	case a.Rejudge:
+		return "", fmt.Errorf("agent workdir is unavailable during rejudge")
+	default:
+		return "", fmt.Errorf("agent workdir is not available")
+	}
 }
 
 type StepConfig struct {
@@ -94,4 +113,6 @@ func init() {
 	DefaultRegistry.Register("http", ParseHttpStep)
 	DefaultRegistry.Register("script", ParseScriptStep)
 	DefaultRegistry.Register("llmJudge", ParseLLMJudgeStep)
+	DefaultRegistry.Register("outputMatch", ParseOutputMatchStep)
+	DefaultRegistry.Register("fileAssert", ParseFileAssertStep)
 }
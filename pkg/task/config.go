@@ -5,8 +5,10 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/mcpchecker/mcpchecker/pkg/elicitation"
 	"github.com/mcpchecker/mcpchecker/pkg/llmjudge"
 	"github.com/mcpchecker/mcpchecker/pkg/steps"
+	"github.com/mcpchecker/mcpchecker/pkg/toolfilter"
 	"github.com/mcpchecker/mcpchecker/pkg/util"
 	"sigs.k8s.io/yaml"
 )
@@ -41,6 +43,12 @@ type TaskSpec struct {
 	Cleanup  []*steps.StepConfig `json:"cleanup,omitempty"`
 	Verify   []*steps.StepConfig `json:"verify,omitempty"`
 	Prompt   *util.Step          `json:"prompt,omitempty"`
+	// Elicitation answers elicitation requests from MCP servers during the task
+	Elicitation *elicitation.Config `json:"elicitation,omitempty"`
+	// Tools restricts the MCP server tools the agent can see during the task
+	Tools *toolfilter.Config `json:"tools,omitempty"`
+	// Workspace lists files and directories copied into the working directory of the agent
+	Workspace []WorkspaceEntry `json:"workspace,omitempty"`
 }
 
 type Requirements struct {
@@ -146,6 +154,24 @@ func Read(data []byte, basePath string) (*TaskConfig, error) {
 		return nil, fmt.Errorf("failed to resolve prompt path: %w", err)
 	}
 
+	if spec.Spec.Elicitation != nil {
+		if err := spec.Spec.Elicitation.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid elicitation config: %w", err)
+		}
+	}
+
+	if spec.Spec.Tools != nil {
+		if err := spec.Spec.Tools.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid tools config: %w", err)
+		}
+	}
+
+	for i := range spec.Spec.Workspace {
+		if err := spec.Spec.Workspace[i].Validate(); err != nil {
+			return nil, fmt.Errorf("invalid workspace[%d]: %w", i, err)
+		}
+	}
+
 	return spec, nil
 }
 
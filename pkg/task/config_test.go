@@ -7,7 +7,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/mcpchecker/mcpchecker/pkg/elicitation"
 	"github.com/mcpchecker/mcpchecker/pkg/steps"
+	"github.com/mcpchecker/mcpchecker/pkg/toolfilter"
 	"github.com/mcpchecker/mcpchecker/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -174,3 +176,168 @@ kubectl delete namespace create-pod-test --ignore-not-found`,
 		})
 	}
 }
+
+func TestReadElicitation(t *testing.T) {
+	tt := map[string]struct {
+		elicitation string
+		expected    *elicitation.Config
+		errContains string
+	}{
+		"scripted responses and policy": {
+			elicitation: `
+    responses:
+      - match: "(?i)delete"
+        action: accept
+        content:
+          confirm: true
+    policy: cancel`,
+			expected: &elicitation.Config{
+				Responses: []elicitation.Response{{
+					Match:   "(?i)delete",
+					Action:  elicitation.ActionAccept,
+					Content: map[string]any{"confirm": true},
+				}},
+				Policy: elicitation.ActionCancel,
+			},
+		},
+		"invalid policy": {
+			elicitation: `
+    policy: ignore`,
+			errContains: "invalid elicitation config",
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			data := fmt.Sprintf(`kind: Task
+apiVersion: mcpchecker/v1alpha2
+metadata:
+  name: elicitation
+spec:
+  prompt:
+    inline: Delete the web-server pod
+  elicitation:%s
+`, tc.elicitation)
+
+			cfg, err := Read([]byte(data), t.TempDir())
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg.Spec.Elicitation)
+		})
+	}
+}
+
+func TestReadTools(t *testing.T) {
+	tt := map[string]struct {
+		tools       string
+		expected    *toolfilter.Config
+		errContains string
+	}{
+		"allow and deny rules": {
+			tools: `
+    allow:
+      - server: kubernetes
+    deny:
+      - server: kubernetes
+        toolPattern: "^(pods_delete|pods_exec)$"`,
+			expected: &toolfilter.Config{
+				Allow: []toolfilter.Rule{{Server: "kubernetes"}},
+				Deny:  []toolfilter.Rule{{Server: "kubernetes", ToolPattern: "^(pods_delete|pods_exec)$"}},
+			},
+		},
+		"invalid pattern": {
+			tools: `
+    deny:
+      - toolPattern: "("`,
+			errContains: "invalid tools config",
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			data := fmt.Sprintf(`kind: Task
+apiVersion: mcpchecker/v1alpha2
+metadata:
+  name: tools
+spec:
+  prompt:
+    inline: List the pods in the default namespace
+  tools:%s
+`, tc.tools)
+
+			cfg, err := Read([]byte(data), t.TempDir())
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg.Spec.Tools)
+		})
+	}
+}
+
+func TestReadWorkspace(t *testing.T) {
+	inline := "replicas: 1"
+	tt := map[string]struct {
+		workspace   string
+		expected    []WorkspaceEntry
+		errContains string
+	}{
+		"path and inline entries": {
+			workspace: `
+    - path: fixtures/app
+      dest: app
+    - inline: "replicas: 1"
+      dest: values.yaml`,
+			expected: []WorkspaceEntry{
+				{Path: "fixtures/app", Dest: "app"},
+				{Inline: &inline, Dest: "values.yaml"},
+			},
+		},
+		"both path and inline": {
+			workspace: `
+    - path: a
+      inline: b`,
+			errContains: "exactly one of path or inline",
+		},
+		"inline without dest": {
+			workspace: `
+    - inline: b`,
+			errContains: "dest must be set",
+		},
+		"dest outside of workspace": {
+			workspace: `
+    - path: a
+      dest: ../a`,
+			errContains: "outside of the workspace",
+		},
+	}
+
+	for tn, tc := range tt {
+		t.Run(tn, func(t *testing.T) {
+			data := fmt.Sprintf(`kind: Task
+apiVersion: mcpchecker/v1alpha2
+metadata:
+  name: workspace
+spec:
+  prompt:
+    inline: Write a deployment manifest
+  workspace:%s
+`, tc.workspace)
+
+			cfg, err := Read([]byte(data), t.TempDir())
+			if tc.errContains != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errContains)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cfg.Spec.Workspace)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mcpchecker/mcpchecker/pkg/steps"
+)
+
+const llmJudgeStepType = "llmJudge"
+
+// RecordedRun holds the artifacts of a previous task run that are needed to
+// re-run its llmJudge verify steps without running the agent again.
+type RecordedRun struct {
+	// Output is the final agent message that was judged
+	Output string
+	// SetupOutput is used to resolve {steps.*} templates in the prompt
+	SetupOutput *PhaseOutput
+	// VerifyOutput provides the outputs of the non-judge verify steps
+	VerifyOutput *PhaseOutput
+}
+
+// HasLLMJudgeSteps returns true if any verify step of the task is an llmJudge step
+func (cfg *TaskConfig) HasLLMJudgeSteps() bool {
+	if cfg.Spec == nil {
+		return false
+	}
+
+	for _, stepCfg := range cfg.Spec.Verify {
+		if isLLMJudgeStep(stepCfg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Rejudge re-runs only the llmJudge verify steps of a task against a recorded run.
+// Outputs of all other verify steps are reused from the recording, so they are still
+// available to {steps.*} templates in the judge config. The judge is taken from ctx.
+func Rejudge(ctx context.Context, cfg *TaskConfig, run *RecordedRun) (*PhaseOutput, error) {
+	if cfg.Spec == nil || cfg.Spec.Prompt.IsEmpty() {
+		return nil, fmt.Errorf("prompt.inline or prompt.file must be set on a task to rejudge it")
+	}
+
+	prompt, err := cfg.Spec.Prompt.GetValue()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prompt for task: %w", err)
+	}
+
+	r := &taskRunner{
+		baseDir:      cfg.basePath,
+		output:       run.Output,
+		setupOutputs: stepOutputsFromPhase(run.SetupOutput),
+		random:       steps.NewRandomResolver(),
+	}
+	r.prompt = r.resolvePromptTemplates(prompt)
+
+	out := &PhaseOutput{
+		Steps:   make([]*steps.StepOutput, 0),
+		Success: true,
+	}
+
+	stepOutputs := make(map[string]map[string]string)
+
+	for i, stepCfg := range cfg.Spec.Verify {
+		var res *steps.StepOutput
+
+		if isLLMJudgeStep(stepCfg) {
+			runner, err := steps.DefaultRegistry.Parse(stepCfg)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse verify[%d]: %w", i, err)
+			}
+
+			res, err = runner.Execute(ctx, &steps.StepInput{
+				Agent: &steps.AgentContext{
+					Prompt:  r.prompt,
+					Output:  r.output,
+					Rejudge: true,
+				},
+				Workdir:     r.baseDir,
+				StepOutputs: stepOutputs,
+				Random:      r.random,
+			})
+			if err != nil {
+				out.Steps = append(out.Steps, res)
+				out.Success = false
+				out.Error = err.Error()
+				return out, fmt.Errorf("verify[%d] failed: %w", i, err)
+			}
+		} else {
+			res = recordedStep(run.VerifyOutput, i)
+			if res == nil {
+				// The step failed hard or never ran in the recorded run, so later
+				// steps have nothing to build on and cannot be reproduced offline
+				out.Success = false
+				out.Error = fmt.Sprintf("no recorded output for verify[%d]", i)
+				if run.VerifyOutput != nil && run.VerifyOutput.Error != "" {
+					out.Error = run.VerifyOutput.Error
+				}
+				return out, fmt.Errorf("verify[%d] failed: %s", i, out.Error)
+			}
+		}
+
+		out.Steps = append(out.Steps, res)
+		if res != nil && !res.Success {
+			out.Success = false
+		}
+
+		// Accumulate outputs from this step
+		if res != nil && res.Success && len(res.Outputs) > 0 && res.Type != "" {
+			stepOutputs[res.Type] = res.Outputs
+		}
+	}
+
+	return out, nil
+}
+
+func isLLMJudgeStep(stepCfg *steps.StepConfig) bool {
+	if stepCfg == nil {
+		return false
+	}
+
+	_, ok := stepCfg.Config[llmJudgeStepType]
+	return ok
+}
+
+func recordedStep(phase *PhaseOutput, idx int) *steps.StepOutput {
+	if phase == nil || idx >= len(phase.Steps) {
+		return nil
+	}
+
+	return phase.Steps[idx]
+}
+
+// stepOutputsFromPhase rebuilds the step output map accumulated while the phase ran
+func stepOutputsFromPhase(phase *PhaseOutput) map[string]map[string]string {
+	stepOutputs := make(map[string]map[string]string)
+	if phase == nil {
+		return stepOutputs
+	}
+
+	for _, res := range phase.Steps {
+		if res != nil && res.Success && len(res.Outputs) > 0 && res.Type != "" {
+			stepOutputs[res.Type] = res.Outputs
+		}
+	}
+
+	return stepOutputs
+}
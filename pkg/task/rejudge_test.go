@@ -0,0 +1,113 @@
+package task
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mcpchecker/mcpchecker/pkg/llmjudge"
+	"github.com/mcpchecker/mcpchecker/pkg/steps"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingJudge struct {
+	passed    bool
+	reference string
+	prompt    string
+	output    string
+}
+
+func (j *recordingJudge) EvaluateText(ctx context.Context, cfg *llmjudge.LLMJudgeStepConfig, prompt, output string) (*llmjudge.LLMJudgeResult, error) {
+	j.reference = cfg.ReferenceAnswer()
+	j.prompt = prompt
+	j.output = output
+	return &llmjudge.LLMJudgeResult{Passed: j.passed, Reason: "rejudged", FailureCategory: "n/a"}, nil
+}
+
+func (j *recordingJudge) ModelName() string { return "recording" }
+func (j *recordingJudge) Close() error      { return nil }
+
+const rejudgeTask = `kind: Task
+apiVersion: mcpchecker/v1alpha2
+metadata:
+  name: rejudge-me
+spec:
+  verify:
+    - script:
+        inline: echo ok
+    - llmJudge:
+        contains: "pod {steps.script.name}"
+  prompt:
+    inline: "List pods in {steps.script.namespace}"
+`
+
+func TestRejudge(t *testing.T) {
+	cfg, err := Read([]byte(rejudgeTask), t.TempDir())
+	require.NoError(t, err)
+	assert.True(t, cfg.HasLLMJudgeSteps())
+
+	judge := &recordingJudge{passed: true}
+	ctx := llmjudge.WithJudge(context.Background(), judge)
+
+	out, err := Rejudge(ctx, cfg, &RecordedRun{
+		Output: "pod web is running",
+		SetupOutput: &PhaseOutput{
+			Success: true,
+			Steps: []*steps.StepOutput{
+				{Type: "script", Success: true, Outputs: map[string]string{"namespace": "ns-1"}},
+			},
+		},
+		VerifyOutput: &PhaseOutput{
+			Success: false,
+			Steps: []*steps.StepOutput{
+				{Type: "script", Success: true, Outputs: map[string]string{"name": "web"}},
+				{Type: "llmJudge", Success: false, Message: "old verdict"},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, out.Success)
+	require.Len(t, out.Steps, 2)
+	assert.Equal(t, "web", out.Steps[0].Outputs["name"], "non-judge step output should be reused")
+	assert.Equal(t, "rejudged", out.Steps[1].Message)
+
+	assert.Equal(t, "pod web", judge.reference)
+	assert.Equal(t, "List pods in ns-1", judge.prompt)
+	assert.Equal(t, "pod web is running", judge.output)
+}
+
+func TestRejudgeMissingRecordedStep(t *testing.T) {
+	cfg, err := Read([]byte(rejudgeTask), t.TempDir())
+	require.NoError(t, err)
+
+	ctx := llmjudge.WithJudge(context.Background(), &recordingJudge{passed: true})
+
+	out, err := Rejudge(ctx, cfg, &RecordedRun{
+		Output: "pod web is running",
+		VerifyOutput: &PhaseOutput{
+			Error: "script exited 1",
+			Steps: []*steps.StepOutput{nil},
+		},
+	})
+	require.Error(t, err)
+	require.NotNil(t, out)
+	assert.False(t, out.Success)
+	assert.Equal(t, "script exited 1", out.Error)
+}
+
+func TestHasLLMJudgeSteps(t *testing.T) {
+	cfg, err := Read([]byte(`kind: Task
+apiVersion: mcpchecker/v1alpha2
+metadata:
+  name: no-judge
+spec:
+  verify:
+    - script:
+        inline: echo ok
+  prompt:
+    inline: hi
+`), t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, cfg.HasLLMJudgeSteps())
+}
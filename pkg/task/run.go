@@ -4,14 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/genmcp/gen-mcp/pkg/template"
+	"github.com/mcpchecker/mcpchecker/pkg/acpclient"
 	"github.com/mcpchecker/mcpchecker/pkg/agent"
 	"github.com/mcpchecker/mcpchecker/pkg/extension/client"
 	"github.com/mcpchecker/mcpchecker/pkg/mcpclient"
 	"github.com/mcpchecker/mcpchecker/pkg/steps"
 	"github.com/mcpchecker/mcpchecker/pkg/tokens"
+	"github.com/mcpchecker/mcpchecker/pkg/util"
 )
 
 // AgentDetails captures structured information from the agent execution.
@@ -19,6 +23,14 @@ type AgentDetails struct {
 	TokenEstimate *tokens.Estimate        `json:"tokenEstimate,omitempty"`
 	ToolCalls     []agent.ToolCallSummary `json:"toolCalls,omitempty"`
 	OutputSteps   []agent.OutputStep      `json:"outputSteps,omitempty"`
+	// FileOperations are the filesystem requests the agent made through the client
+	FileOperations []acpclient.FileOperation `json:"fileOperations,omitempty"`
+	// TerminalCommands are the commands the agent ran in terminals of the client
+	TerminalCommands []acpclient.TerminalCommand `json:"terminalCommands,omitempty"`
+	// PermissionRequests are the permission requests of the agent and how the client decided them
+	PermissionRequests []acpclient.PermissionRequest `json:"permissionRequests,omitempty"`
+	// ModeChanges are the modes the ACP session started in and switched to
+	ModeChanges []acpclient.ModeChange `json:"modeChanges,omitempty"`
 }
 
 // PhaseOutput represents the output from a task phase (setup, agent, verify, or cleanup).
@@ -54,6 +66,11 @@ type taskRunner struct {
 	output  string
 	baseDir string
 
+	// workspace is seeded into workdir, the working directory of the agent, which
+	// is kept for the verify steps and removed on cleanup
+	workspace []WorkspaceEntry
+	workdir   string
+
 	setupOutputs map[string]map[string]string
 	random       *steps.RandomResolver
 }
@@ -65,11 +82,12 @@ func NewTaskRunner(ctx context.Context, cfg *TaskConfig) (TaskRunner, error) {
 
 	var err error
 	r := &taskRunner{
-		setup:   make([]steps.StepRunner, len(cfg.Spec.Setup)),
-		verify:  make([]steps.StepRunner, len(cfg.Spec.Verify)),
-		cleanup: make([]steps.StepRunner, len(cfg.Spec.Cleanup)),
-		baseDir: cfg.basePath,
-		random:  steps.NewRandomResolver(),
+		setup:     make([]steps.StepRunner, len(cfg.Spec.Setup)),
+		verify:    make([]steps.StepRunner, len(cfg.Spec.Verify)),
+		cleanup:   make([]steps.StepRunner, len(cfg.Spec.Cleanup)),
+		baseDir:   cfg.basePath,
+		workspace: cfg.Spec.Workspace,
+		random:    steps.NewRandomResolver(),
 	}
 
 	extensionManager, ok := client.ManagerFromContext(ctx)
@@ -226,6 +244,8 @@ func (r *taskRunner) Setup(ctx context.Context) (*PhaseOutput, error) {
 }
 
 func (r *taskRunner) Cleanup(ctx context.Context) (*PhaseOutput, error) {
+	defer r.removeWorkdir()
+
 	out := &PhaseOutput{
 		Steps:   make([]*steps.StepOutput, 0),
 		Success: true,
@@ -306,6 +326,22 @@ func (r *taskRunner) resolvePromptTemplates(prompt string) string {
 
 func (r *taskRunner) RunAgent(ctx context.Context, agentRunner agent.Runner) (*PhaseOutput, error) {
 	r.prompt = r.resolvePromptTemplates(r.prompt)
+
+	if err := r.createWorkdir(); err != nil {
+		detailErr := fmt.Errorf("failed to prepare agent workspace: %w", err)
+		return &PhaseOutput{
+			Success: false,
+			Error:   detailErr.Error(),
+			Steps: []*steps.StepOutput{{
+				Type:    "agent",
+				Success: false,
+				Error:   detailErr.Error(),
+			}},
+		}, detailErr
+	}
+
+	ctx = util.WithWorkDir(ctx, r.workdir)
+	ctx = agent.WithRetryReset(ctx, r.resetWorkdir)
 	result, err := agentRunner.RunTask(ctx, r.prompt)
 	if err != nil {
 		detailErr := fmt.Errorf("failed to run agent: %w", err)
@@ -334,6 +370,12 @@ func (r *taskRunner) RunAgent(ctx context.Context, agentRunner agent.Runner) (*P
 		ToolCalls:     result.GetToolCalls(),
 		OutputSteps:   outputSteps,
 	}
+	if activity, ok := result.(agent.ClientActivityResult); ok {
+		agentDetails.FileOperations = activity.GetFileOperations()
+		agentDetails.TerminalCommands = activity.GetTerminalCommands()
+		agentDetails.PermissionRequests = activity.GetPermissionRequests()
+		agentDetails.ModeChanges = activity.GetModeChanges()
+	}
 
 	// Convert each OutputStep to a StepOutput for the phase
 	phaseSteps := make([]*steps.StepOutput, 0, len(outputSteps))
@@ -381,8 +423,9 @@ func (r *taskRunner) Verify(ctx context.Context) (*PhaseOutput, error) {
 	for i, s := range r.verify {
 		res, err := s.Execute(ctx, &steps.StepInput{
 			Agent: &steps.AgentContext{
-				Prompt: r.prompt,
-				Output: r.output,
+				Prompt:  r.prompt,
+				Output:  r.output,
+				Workdir: r.workdir,
 			},
 			Workdir:     r.baseDir,
 			StepOutputs: stepOutputs,
@@ -407,3 +450,46 @@ func (r *taskRunner) Verify(ctx context.Context) (*PhaseOutput, error) {
 
 	return out, nil
 }
+
+// createWorkdir creates the working directory of the agent and seeds it with the task workspace
+func (r *taskRunner) createWorkdir() error {
+	dir, err := os.MkdirTemp("", "mcpchecker-workspace-")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	r.workdir = dir
+
+	return seedWorkspace(r.workspace, r.baseDir, dir)
+}
+
+// resetWorkdir empties the working directory of the agent and seeds it again, so that a
+// retried agent starts from the same workspace as the first attempt
+func (r *taskRunner) resetWorkdir() error {
+	entries, err := os.ReadDir(r.workdir)
+	if err != nil {
+		return fmt.Errorf("failed to read agent workspace: %w", err)
+	}
+
+	for _, e := range entries {
+		if err := os.RemoveAll(filepath.Join(r.workdir, e.Name())); err != nil {
+			return fmt.Errorf("failed to clear agent workspace: %w", err)
+		}
+	}
+
+	return seedWorkspace(r.workspace, r.baseDir, r.workdir)
+}
+
+// removeWorkdir removes the working directory of the agent, unless MCPCHECKER_DEBUG is set
+func (r *taskRunner) removeWorkdir() {
+	if r.workdir == "" {
+		return
+	}
+
+	if os.Getenv("MCPCHECKER_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "Preserving agent workspace %s because MCPCHECKER_DEBUG is set\n", r.workdir)
+		return
+	}
+
+	_ = os.RemoveAll(r.workdir)
+	r.workdir = ""
+}
@@ -0,0 +1,78 @@
+package task
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mcpchecker/mcpchecker/pkg/util"
+)
+
+// WorkspaceEntry is a file or directory placed in the working directory of the agent
+// before it runs. Exactly one of Path or Inline must be set.
+type WorkspaceEntry struct {
+	// Path is a file or directory to copy, relative to the task file
+	Path string `json:"path,omitempty"`
+	// Inline is the content of a file created at Dest
+	Inline *string `json:"inline,omitempty"`
+	// Dest is the location in the workspace, relative to it. Defaults to Path.
+	Dest string `json:"dest,omitempty"`
+}
+
+func (e *WorkspaceEntry) Validate() error {
+	if (e.Path == "") == (e.Inline == nil) {
+		return fmt.Errorf("exactly one of path or inline must be set")
+	}
+
+	if e.Inline != nil && e.Dest == "" {
+		return fmt.Errorf("dest must be set for inline files")
+	}
+
+	dest := e.dest()
+	if filepath.IsAbs(dest) {
+		return fmt.Errorf("dest %q must be relative to the workspace", dest)
+	}
+	if clean := filepath.Clean(dest); clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("dest %q is outside of the workspace", dest)
+	}
+
+	return nil
+}
+
+func (e *WorkspaceEntry) dest() string {
+	if e.Dest != "" {
+		return e.Dest
+	}
+	return e.Path
+}
+
+// seedWorkspace copies the workspace entries of a task from taskDir into workDir
+func seedWorkspace(entries []WorkspaceEntry, taskDir, workDir string) error {
+	for i, e := range entries {
+		dest := filepath.Join(workDir, e.dest())
+
+		if e.Inline != nil {
+			if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+				return fmt.Errorf("workspace[%d]: %w", i, err)
+			}
+			if err := os.WriteFile(dest, []byte(*e.Inline), 0o644); err != nil {
+				return fmt.Errorf("workspace[%d]: failed to write %s: %w", i, e.Dest, err)
+			}
+			continue
+		}
+
+		src := e.Path
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(taskDir, src)
+		}
+		if _, err := os.Stat(src); err != nil {
+			return fmt.Errorf("workspace[%d]: %w", i, err)
+		}
+		if err := util.CopyPath(src, dest); err != nil {
+			return fmt.Errorf("workspace[%d]: failed to copy %s into the agent workspace: %w", i, e.Path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package task
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeedWorkspace(t *testing.T) {
+	taskDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(taskDir, "fixtures", "app"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(taskDir, "fixtures", "app", "main.go"), []byte("package main"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(taskDir, "README.md"), []byte("# App"), 0o644))
+
+	inline := "replicas: 1"
+	entries := []WorkspaceEntry{
+		{Path: "fixtures/app", Dest: "app"},
+		{Path: "README.md"},
+		{Inline: &inline, Dest: "config/values.yaml"},
+	}
+
+	workDir := t.TempDir()
+	require.NoError(t, seedWorkspace(entries, taskDir, workDir))
+
+	for path, expected := range map[string]string{
+		"app/main.go":        "package main",
+		"README.md":          "# App",
+		"config/values.yaml": "replicas: 1",
+	} {
+		data, err := os.ReadFile(filepath.Join(workDir, path))
+		require.NoError(t, err, path)
+		assert.Equal(t, expected, string(data), path)
+	}
+
+	err := seedWorkspace([]WorkspaceEntry{{Path: "missing"}}, taskDir, t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "workspace[0]")
+}
+
+func TestTaskRunnerWorkdir(t *testing.T) {
+	taskDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(taskDir, "input.txt"), []byte("input"), 0o644))
+
+	r := &taskRunner{
+		baseDir:   taskDir,
+		workspace: []WorkspaceEntry{{Path: "input.txt"}},
+	}
+
+	require.NoError(t, r.createWorkdir())
+	workdir := r.workdir
+	assert.FileExists(t, filepath.Join(workdir, "input.txt"))
+
+	// a retried agent gets the workspace as it was seeded
+	require.NoError(t, os.WriteFile(filepath.Join(workdir, "input.txt"), []byte("changed"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(workdir, "out"), 0o755))
+	require.NoError(t, r.resetWorkdir())
+	data, err := os.ReadFile(filepath.Join(workdir, "input.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "input", string(data))
+	assert.NoDirExists(t, filepath.Join(workdir, "out"))
+
+	t.Setenv("MCPCHECKER_DEBUG", "")
+	_, err = r.Cleanup(t.Context())
+	require.NoError(t, err)
+	assert.NoDirExists(t, workdir)
+	assert.Empty(t, r.workdir)
+}
@@ -0,0 +1,155 @@
+package toolfilter
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Config restricts the tools of the MCP servers that the agent can see.
+// A tool is visible if it matches an allow rule (or there are none) and no deny rule.
+type Config struct {
+	// Allow lists the only tools the agent sees
+	Allow []Rule `json:"allow,omitempty"`
+	// Deny hides tools from the agent
+	Deny []Rule `json:"deny,omitempty"`
+}
+
+// Rule matches tools by server and name
+type Rule struct {
+	// Server restricts the rule to one server, if unset the rule applies to all servers
+	Server string `json:"server,omitempty"`
+
+	// At most one of Tool or ToolPattern should be set
+	// If neither is set, the rule matches every tool of the server
+	Tool        string `json:"tool,omitempty"`
+	ToolPattern string `json:"toolPattern,omitempty"` // regex pattern
+}
+
+func (c *Config) Validate() error {
+	for i, r := range c.Allow {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("allow[%d]: %w", i, err)
+		}
+	}
+	for i, r := range c.Deny {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("deny[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (r Rule) validate() error {
+	if r.Server == "" && r.Tool == "" && r.ToolPattern == "" {
+		return fmt.Errorf("one of server, tool or toolPattern is required")
+	}
+	if r.Tool != "" && r.ToolPattern != "" {
+		return fmt.Errorf("only one of tool or toolPattern can be set")
+	}
+	if r.ToolPattern != "" {
+		if _, err := regexp.Compile(r.ToolPattern); err != nil {
+			return fmt.Errorf("invalid toolPattern %q: %w", r.ToolPattern, err)
+		}
+	}
+
+	return nil
+}
+
+// Filter decides which tools are visible. It combines several configs, for example
+// from a task and the task sets it belongs to, and a tool must be allowed by all of them.
+// A nil Filter allows every tool.
+type Filter struct {
+	configs []compiledConfig
+}
+
+type compiledConfig struct {
+	allow []compiledRule
+	deny  []compiledRule
+}
+
+type compiledRule struct {
+	server  string
+	tool    string
+	pattern *regexp.Regexp
+}
+
+// New creates a filter from configs, skipping nil ones. It returns nil if no config restricts tools.
+func New(configs ...*Config) (*Filter, error) {
+	f := &Filter{}
+	for _, cfg := range configs {
+		if cfg == nil || (len(cfg.Allow) == 0 && len(cfg.Deny) == 0) {
+			continue
+		}
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+
+		f.configs = append(f.configs, compiledConfig{
+			allow: compileRules(cfg.Allow),
+			deny:  compileRules(cfg.Deny),
+		})
+	}
+
+	if len(f.configs) == 0 {
+		return nil, nil
+	}
+
+	return f, nil
+}
+
+func compileRules(rules []Rule) []compiledRule {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, r := range rules {
+		c := compiledRule{server: r.Server, tool: r.Tool}
+		if r.ToolPattern != "" {
+			c.pattern = regexp.MustCompile(r.ToolPattern)
+		}
+		compiled = append(compiled, c)
+	}
+
+	return compiled
+}
+
+// Allows reports whether the tool of the server is visible to the agent
+func (f *Filter) Allows(server, tool string) bool {
+	if f == nil {
+		return true
+	}
+
+	for _, cfg := range f.configs {
+		if len(cfg.allow) > 0 && !matchesAny(cfg.allow, server, tool) {
+			return false
+		}
+		if matchesAny(cfg.deny, server, tool) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesAny(rules []compiledRule, server, tool string) bool {
+	for _, r := range rules {
+		if r.matches(server, tool) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r compiledRule) matches(server, tool string) bool {
+	if r.server != "" && r.server != server {
+		return false
+	}
+
+	switch {
+	case r.tool != "":
+		return r.tool == tool
+	case r.pattern != nil:
+		return r.pattern.MatchString(tool)
+	default:
+		return true
+	}
+}
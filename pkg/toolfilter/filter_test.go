@@ -0,0 +1,118 @@
+package toolfilter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidate(t *testing.T) {
+	tests := map[string]struct {
+		config      Config
+		errContains string
+	}{
+		"empty config is valid": {},
+		"server only rule": {
+			config: Config{Deny: []Rule{{Server: "kubernetes"}}},
+		},
+		"empty rule": {
+			config:      Config{Allow: []Rule{{}}},
+			errContains: "allow[0]: one of server, tool or toolPattern is required",
+		},
+		"tool and pattern": {
+			config:      Config{Deny: []Rule{{Tool: "a", ToolPattern: "b"}}},
+			errContains: "deny[0]: only one of tool or toolPattern",
+		},
+		"invalid pattern": {
+			config:      Config{Deny: []Rule{{ToolPattern: "("}}},
+			errContains: "invalid toolPattern",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.config.Validate()
+			if tc.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errContains)
+		})
+	}
+}
+
+func TestFilterAllows(t *testing.T) {
+	tests := map[string]struct {
+		configs []*Config
+		server  string
+		tool    string
+		allowed bool
+	}{
+		"nil filter allows everything": {
+			server:  "kubernetes",
+			tool:    "exec_in_pod",
+			allowed: true,
+		},
+		"denied by name": {
+			configs: []*Config{{Deny: []Rule{{Server: "kubernetes", Tool: "exec_in_pod"}}}},
+			server:  "kubernetes",
+			tool:    "exec_in_pod",
+			allowed: false,
+		},
+		"deny on other server": {
+			configs: []*Config{{Deny: []Rule{{Server: "github", Tool: "exec_in_pod"}}}},
+			server:  "kubernetes",
+			tool:    "exec_in_pod",
+			allowed: true,
+		},
+		"deny without server applies to all servers": {
+			configs: []*Config{{Deny: []Rule{{ToolPattern: "^delete_"}}}},
+			server:  "github",
+			tool:    "delete_repo",
+			allowed: false,
+		},
+		"allow list hides other tools": {
+			configs: []*Config{{Allow: []Rule{{Server: "kubernetes", ToolPattern: "^(pods|namespaces)_list$"}}}},
+			server:  "kubernetes",
+			tool:    "pods_delete",
+			allowed: false,
+		},
+		"allow list keeps matching tools": {
+			configs: []*Config{{Allow: []Rule{{Server: "kubernetes", ToolPattern: "^(pods|namespaces)_list$"}}}},
+			server:  "kubernetes",
+			tool:    "pods_list",
+			allowed: true,
+		},
+		"deny wins over allow": {
+			configs: []*Config{{Allow: []Rule{{Server: "kubernetes"}}, Deny: []Rule{{Tool: "pods_list"}}}},
+			server:  "kubernetes",
+			tool:    "pods_list",
+			allowed: false,
+		},
+		"every config must allow": {
+			configs: []*Config{
+				{Allow: []Rule{{Server: "kubernetes"}}},
+				{Deny: []Rule{{Server: "kubernetes", Tool: "pods_exec"}}},
+			},
+			server:  "kubernetes",
+			tool:    "pods_exec",
+			allowed: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := New(tc.configs...)
+			require.NoError(t, err)
+			assert.Equal(t, tc.allowed, f.Allows(tc.server, tc.tool))
+		})
+	}
+}
+
+func TestNewWithoutRestrictions(t *testing.T) {
+	f, err := New(nil, &Config{})
+	require.NoError(t, err)
+	assert.Nil(t, f)
+}
@@ -0,0 +1,140 @@
+package tooloverride
+
+import (
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Variant is a named set of overrides of how the proxy advertises the MCP servers to the agent.
+// A variant without servers runs the servers unchanged, and is useful as a baseline.
+type Variant struct {
+	Name string `json:"name"`
+	// Servers maps MCP server names to their overrides
+	Servers map[string]*ServerOverride `json:"servers,omitempty"`
+}
+
+// ServerOverride rewrites the instructions and tools of a single MCP server
+type ServerOverride struct {
+	// Instructions replaces the server instructions from the initialize result
+	Instructions *string `json:"instructions,omitempty"`
+	// Tools maps the tool names of the server to their overrides
+	Tools map[string]*ToolOverride `json:"tools,omitempty"`
+}
+
+// ToolOverride rewrites a tool as the agent sees it. Calls are still made with the
+// original tool name, and are recorded under it so assertions work across variants.
+type ToolOverride struct {
+	Name        string         `json:"name,omitempty"`
+	Description *string        `json:"description,omitempty"`
+	InputSchema map[string]any `json:"inputSchema,omitempty"`
+}
+
+// Validate checks a list of variants, every variant needs a unique name
+func Validate(variants []Variant) error {
+	seen := make(map[string]bool, len(variants))
+	for i, v := range variants {
+		if v.Name == "" {
+			return fmt.Errorf("toolOverrides[%d]: name is required", i)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("toolOverrides[%d]: duplicate variant name %q", i, v.Name)
+		}
+		seen[v.Name] = true
+
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("toolOverrides[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (v *Variant) Validate() error {
+	for server, so := range v.Servers {
+		if so == nil {
+			continue
+		}
+
+		names := make(map[string]string, len(so.Tools))
+		for tool, to := range so.Tools {
+			if to == nil {
+				continue
+			}
+			if to.InputSchema != nil && to.InputSchema["type"] != "object" {
+				return fmt.Errorf("servers.%s.tools.%s: inputSchema must have type object", server, tool)
+			}
+
+			advertised := to.advertisedName(tool)
+			if other, ok := names[advertised]; ok {
+				return fmt.Errorf("servers.%s.tools: %q and %q are both advertised as %q", server, other, tool, advertised)
+			}
+			names[advertised] = tool
+		}
+	}
+
+	return nil
+}
+
+func (o *ToolOverride) advertisedName(tool string) string {
+	if o == nil || o.Name == "" {
+		return tool
+	}
+
+	return o.Name
+}
+
+// Server returns the overrides for the named server, or nil if there are none
+func (v *Variant) Server(name string) *ServerOverride {
+	if v == nil {
+		return nil
+	}
+
+	return v.Servers[name]
+}
+
+// ApplyInstructions returns the server instructions as the agent should see them
+func (s *ServerOverride) ApplyInstructions(instructions string) string {
+	if s == nil || s.Instructions == nil {
+		return instructions
+	}
+
+	return *s.Instructions
+}
+
+// ApplyTool returns the tool as the agent should see it. The original tool is not modified.
+func (s *ServerOverride) ApplyTool(t *mcp.Tool) *mcp.Tool {
+	if s == nil {
+		return t
+	}
+
+	o, ok := s.Tools[t.Name]
+	if !ok || o == nil {
+		return t
+	}
+
+	advertised := *t
+	advertised.Name = o.advertisedName(t.Name)
+	if o.Description != nil {
+		advertised.Description = *o.Description
+	}
+	if o.InputSchema != nil {
+		advertised.InputSchema = o.InputSchema
+	}
+
+	return &advertised
+}
+
+// ApplyTools returns the tools as the agent should see them
+func (s *ServerOverride) ApplyTools(tools []*mcp.Tool) []*mcp.Tool {
+	if s == nil {
+		return tools
+	}
+
+	applied := make([]*mcp.Tool, 0, len(tools))
+	for _, t := range tools {
+		applied = append(applied, s.ApplyTool(t))
+	}
+
+	return applied
+}
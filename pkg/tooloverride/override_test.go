@@ -0,0 +1,101 @@
+package tooloverride
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		variants    []Variant
+		errContains string
+	}{
+		"baseline and override": {
+			variants: []Variant{
+				{Name: "baseline"},
+				{Name: "concise", Servers: map[string]*ServerOverride{
+					"kubernetes": {Tools: map[string]*ToolOverride{"pods_list": {Description: ptr("List pods")}}},
+				}},
+			},
+		},
+		"missing name": {
+			variants:    []Variant{{}},
+			errContains: "toolOverrides[0]: name is required",
+		},
+		"duplicate name": {
+			variants:    []Variant{{Name: "a"}, {Name: "a"}},
+			errContains: `toolOverrides[1]: duplicate variant name "a"`,
+		},
+		"schema without object type": {
+			variants: []Variant{{Name: "a", Servers: map[string]*ServerOverride{
+				"kubernetes": {Tools: map[string]*ToolOverride{"pods_list": {InputSchema: map[string]any{"type": "string"}}}},
+			}}},
+			errContains: "inputSchema must have type object",
+		},
+		"rename collision": {
+			variants: []Variant{{Name: "a", Servers: map[string]*ServerOverride{
+				"kubernetes": {Tools: map[string]*ToolOverride{
+					"pods_list":      {Name: "list"},
+					"namespace_list": {Name: "list"},
+				}},
+			}}},
+			errContains: `advertised as "list"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Validate(tc.variants)
+			if tc.errContains == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.errContains)
+		})
+	}
+}
+
+func TestServerOverrideApply(t *testing.T) {
+	v := &Variant{Name: "concise", Servers: map[string]*ServerOverride{
+		"kubernetes": {
+			Instructions: ptr("Use the kubernetes tools for cluster questions"),
+			Tools: map[string]*ToolOverride{
+				"pods_list": {
+					Name:        "list_pods",
+					Description: ptr("List pods in a namespace"),
+					InputSchema: map[string]any{"type": "object", "required": []any{"namespace"}},
+				},
+			},
+		},
+	}}
+
+	original := &mcp.Tool{Name: "pods_list", Description: "Lists pods", InputSchema: map[string]any{"type": "object"}}
+	untouched := &mcp.Tool{Name: "pods_delete", Description: "Deletes a pod"}
+
+	s := v.Server("kubernetes")
+	applied := s.ApplyTools([]*mcp.Tool{original, untouched})
+	require.Len(t, applied, 2)
+	assert.Equal(t, "list_pods", applied[0].Name)
+	assert.Equal(t, "List pods in a namespace", applied[0].Description)
+	assert.Equal(t, map[string]any{"type": "object", "required": []any{"namespace"}}, applied[0].InputSchema)
+	assert.Same(t, untouched, applied[1])
+	assert.Equal(t, "pods_list", original.Name, "the original tool should not be modified")
+
+	assert.Equal(t, "Use the kubernetes tools for cluster questions", s.ApplyInstructions("original"))
+
+	other := v.Server("github")
+	assert.Nil(t, other)
+	assert.Same(t, original, other.ApplyTool(original))
+	assert.Equal(t, "original", other.ApplyInstructions("original"))
+
+	var none *Variant
+	assert.Nil(t, none.Server("kubernetes"))
+}
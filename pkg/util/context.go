@@ -22,3 +22,20 @@ func IsVerbose(ctx context.Context) bool {
 	return ok && v
 }
 
+type workDirKey struct{}
+
+// WithWorkDir stores the working directory the agent should run in. Agents use it
+// instead of creating their own, so that it outlives the agent run.
+func WithWorkDir(ctx context.Context, dir string) context.Context {
+	return context.WithValue(ctx, workDirKey{}, dir)
+}
+
+// WorkDirFromContext returns the working directory the agent should run in
+func WorkDirFromContext(ctx context.Context) (string, bool) {
+	dir, ok := ctx.Value(workDirKey{}).(string)
+	if !ok || dir == "" {
+		return "", false
+	}
+
+	return dir, true
+}
@@ -0,0 +1,29 @@
+package util
+
+import (
+	"slices"
+	"strings"
+)
+
+// DefaultEnv are the environment variables passed to isolated processes without being
+// listed: PATH, locale and terminal settings
+var DefaultEnv = []string{"PATH", "LANG", "LC_*", "TERM", "TZ", "TMPDIR", "USER", "SHELL"}
+
+// FilterEnv returns the entries of environ named in DefaultEnv or allowed. Entries
+// ending in "*" match by prefix, e.g. "OPENAI_*".
+func FilterEnv(environ []string, allowed ...string) []string {
+	allowed = append(slices.Clone(DefaultEnv), allowed...)
+
+	var filtered []string
+	for _, kv := range environ {
+		name, _, _ := strings.Cut(kv, "=")
+		for _, a := range allowed {
+			if prefix, ok := strings.CutSuffix(a, "*"); (ok && strings.HasPrefix(name, prefix)) || name == a {
+				filtered = append(filtered, kv)
+				break
+			}
+		}
+	}
+
+	return filtered
+}